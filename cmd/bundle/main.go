@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/oasdiff/yaml"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+var (
+	defaultJSON = false
+	asJSON      = flag.Bool("json", defaultJSON, "when true, writes the bundled document as JSON instead of YAML")
+)
+
+var (
+	defaultValidate = true
+	validate        = flag.Bool("validate", defaultValidate, "when false, skips validating the bundled document")
+)
+
+func main() {
+	flag.Parse()
+	filename := flag.Arg(0)
+	if len(flag.Args()) != 1 || filename == "" {
+		log.Fatalf("Usage: go run github.com/getkin/kin-openapi/cmd/bundle@latest [--json] [--validate] -- <local YAML or JSON file>\nGot: %+v\n", os.Args)
+	}
+
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+	doc, err := loader.LoadFromFile(filename)
+	if err != nil {
+		log.Fatalln("Loading error:", err)
+	}
+
+	if err = doc.Bundle(); err != nil {
+		log.Fatalln("Bundling error:", err)
+	}
+
+	if *validate {
+		if err = doc.Validate(loader.Context); err != nil {
+			log.Fatalln("Validation error:", err)
+		}
+	}
+
+	var data []byte
+	if *asJSON {
+		data, err = doc.MarshalJSON()
+	} else {
+		data, err = yaml.Marshal(doc)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	if _, err = os.Stdout.Write(data); err != nil {
+		log.Fatal(err)
+	}
+}
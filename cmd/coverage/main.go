@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+	"log"
+	"os"
+
+	"github.com/getkin/kin-openapi/openapi3filter"
+)
+
+var (
+	defaultHTML = ""
+	html        = flag.String("html", defaultHTML, "file to write the merged report to as HTML")
+)
+
+var (
+	defaultJSON = ""
+	asJSON      = flag.String("json", defaultJSON, "file to write the merged report to as JSON")
+)
+
+var (
+	defaultMin = 0.0
+	minimum    = flag.Float64("min", defaultMin, "exits with status 1 when less than this percentage of the document was exercised")
+)
+
+func main() {
+	flag.Parse()
+	if len(flag.Args()) == 0 {
+		log.Fatalf("Usage: go run github.com/getkin/kin-openapi/cmd/coverage@latest [--html <file>] [--json <file>] [--min <percent>] -- <JSON coverage report>...\nGot: %+v\n", os.Args)
+	}
+
+	report := &openapi3filter.CoverageReport{}
+	for _, filename := range flag.Args() {
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			log.Fatalln("Loading error:", err)
+		}
+		var r openapi3filter.CoverageReport
+		if err := json.Unmarshal(data, &r); err != nil {
+			log.Fatalf("Loading error: %s: %v", filename, err)
+		}
+		report.Merge(&r)
+	}
+
+	if err := report.WriteText(os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+	if *html != "" {
+		if err := writeFile(*html, report.WriteHTML); err != nil {
+			log.Fatalln("Writing error:", err)
+		}
+	}
+	if *asJSON != "" {
+		if err := writeFile(*asJSON, report.WriteJSON); err != nil {
+			log.Fatalln("Writing error:", err)
+		}
+	}
+
+	if err := report.Check(*minimum); err != nil {
+		log.Println(err)
+		os.Exit(1)
+	}
+}
+
+func writeFile(filename string, write func(io.Writer) error) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
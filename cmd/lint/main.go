@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/getkin/kin-openapi/lint"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+var (
+	defaultConfig = ""
+	config        = flag.String("config", defaultConfig, "YAML or JSON file configuring the severity and options of the rules")
+)
+
+var (
+	defaultExt = false
+	ext        = flag.Bool("ext", defaultExt, "enables visiting other files")
+)
+
+var (
+	defaultJSON = false
+	asJSON      = flag.Bool("json", defaultJSON, "when true, writes the findings as JSON instead of one per line")
+)
+
+var (
+	defaultFailOn = "error"
+	failOn        = flag.String("fail-on", defaultFailOn, "exits with status 1 when a finding is at least this severe: hint, info, warn, error or off to never fail")
+)
+
+func main() {
+	flag.Parse()
+	filename := flag.Arg(0)
+	if len(flag.Args()) != 1 || filename == "" {
+		log.Fatalf("Usage: go run github.com/getkin/kin-openapi/cmd/lint@latest [--config <file>] [--ext] [--json] [--fail-on <severity>] -- <local YAML or JSON file>\nGot: %+v\n", os.Args)
+	}
+
+	threshold, err := lint.ParseSeverity(*failOn)
+	if err != nil {
+		log.Fatalln("Invalid --fail-on:", err)
+	}
+
+	var cfg *lint.Config
+	if *config != "" {
+		if cfg, err = lint.LoadConfig(*config); err != nil {
+			log.Fatalln("Configuration error:", err)
+		}
+	}
+
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = *ext
+	loader.IncludeOrigin = true
+	doc, err := loader.LoadFromFile(filename)
+	if err != nil {
+		log.Fatalln("Loading error:", err)
+	}
+
+	findings, err := lint.NewLinter(cfg).Lint(doc)
+	if err != nil {
+		log.Fatalln("Linting error:", err)
+	}
+
+	if *asJSON {
+		if findings == nil {
+			findings = []lint.Finding{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(findings); err != nil {
+			log.Fatal(err)
+		}
+	} else {
+		for _, finding := range findings {
+			fmt.Println(finding)
+		}
+	}
+
+	if threshold == lint.Off {
+		return
+	}
+	for _, finding := range findings {
+		if finding.Severity >= threshold {
+			os.Exit(1)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/getkin/kin-openapi/mock"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+var (
+	defaultAddr = ":8080"
+	addr        = flag.String("addr", defaultAddr, "the TCP address to listen on")
+)
+
+var (
+	defaultExt = false
+	ext        = flag.Bool("ext", defaultExt, "enables visiting other files, including the external values of examples")
+)
+
+var (
+	defaultRandom = false
+	random        = flag.Bool("random", defaultRandom, "when true, makes up random values for schemas without examples, drawn from --seed")
+)
+
+var (
+	defaultSeed = uint64(0)
+	seed        = flag.Uint64("seed", defaultSeed, "the seed of the random values of --random")
+)
+
+func main() {
+	flag.Parse()
+	filename := flag.Arg(0)
+	if len(flag.Args()) != 1 || filename == "" {
+		log.Fatalf("Usage: go run github.com/getkin/kin-openapi/cmd/mock@latest [--addr <host:port>] [--ext] [--random] [--seed <n>] -- <local YAML or JSON file>\nGot: %+v\n", os.Args)
+	}
+
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = *ext
+	loader.LoadExternalExamples = *ext
+	doc, err := loader.LoadFromFile(filename)
+	if err != nil {
+		log.Fatalln("Loading error:", err)
+	}
+	if err = doc.Validate(loader.Context); err != nil {
+		log.Fatalln("Validation error:", err)
+	}
+
+	var options []mock.Option
+	if *random {
+		generator := openapi3.NewValueGenerator(*seed, openapi3.GenerateAsResponse())
+		options = append(options, mock.Values(func(schema *openapi3.SchemaRef) any {
+			if schema != nil && schema.Value != nil {
+				if value, err := generator.Generate(schema.Value); err == nil {
+					return value
+				}
+			}
+			return mock.Synthesize(schema)
+		}))
+	}
+
+	handler, err := mock.NewHandler(doc, options...)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Serving mock responses for %s on %s", filename, *addr)
+	log.Fatal(http.ListenAndServe(*addr, handler))
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/oasdiff/yaml"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/overlay"
+)
+
+var (
+	defaultJSON = false
+	asJSON      = flag.Bool("json", defaultJSON, "when true, writes the overlaid document as JSON instead of YAML")
+)
+
+func main() {
+	flag.Parse()
+	if len(flag.Args()) < 2 || flag.Arg(0) == "" {
+		log.Fatalf("Usage: go run github.com/getkin/kin-openapi/cmd/overlay@latest [--json] -- <local YAML or JSON file> <overlay file>...\nGot: %+v\n", os.Args)
+	}
+
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+	for _, filename := range flag.Args()[1:] {
+		o, err := overlay.ParseFile(filename)
+		if err != nil {
+			log.Fatalln("Loading error:", err)
+		}
+		if err = o.Validate(); err != nil {
+			log.Fatalln("Overlay validation error:", err)
+		}
+		loader.Overlays = append(loader.Overlays, o)
+	}
+
+	doc, err := loader.LoadFromFile(flag.Arg(0))
+	if err != nil {
+		log.Fatalln("Loading error:", err)
+	}
+	if err = doc.Validate(loader.Context); err != nil {
+		log.Fatalln("Validation error:", err)
+	}
+
+	var data []byte
+	if *asJSON {
+		data, err = doc.MarshalJSON()
+	} else {
+		data, err = yaml.Marshal(doc)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	if _, err = os.Stdout.Write(data); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"io/fs"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+var (
+	defaultOut = "."
+	out        = flag.String("out", defaultOut, "directory to write the root document and the files it references to")
+)
+
+var (
+	defaultVerify = true
+	verify        = flag.Bool("verify", defaultVerify, "when false, skips reloading and validating the written files")
+)
+
+var (
+	defaultForce = false
+	force        = flag.Bool("force", defaultForce, "overwrite existing files, including the input file when written to its own directory")
+)
+
+func main() {
+	flag.Parse()
+	filename := flag.Arg(0)
+	if len(flag.Args()) != 1 || filename == "" {
+		log.Fatalf("Usage: go run github.com/getkin/kin-openapi/cmd/split@latest [--out <dir>] [--verify] [--force] -- <local YAML or JSON file>\nGot: %+v\n", os.Args)
+	}
+
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+	doc, err := loader.LoadFromFile(filename)
+	if err != nil {
+		log.Fatalln("Loading error:", err)
+	}
+
+	if err = doc.Bundle(); err != nil {
+		log.Fatalln("Bundling error:", err)
+	}
+
+	root := filepath.Base(filename)
+	files, err := doc.Split(root, nil)
+	if err != nil {
+		log.Fatalln("Splitting error:", err)
+	}
+
+	names := slices.Sorted(maps.Keys(files))
+	if !*force {
+		// Check every file before writing any
+		for _, name := range names {
+			name = filepath.Join(*out, filepath.FromSlash(name))
+			if _, err = os.Lstat(name); !errors.Is(err, fs.ErrNotExist) {
+				log.Fatalf("Not overwriting %s (use --force to overwrite existing files)\n", name)
+			}
+		}
+	}
+
+	for _, name := range names {
+		data := files[name]
+		name = filepath.Join(*out, filepath.FromSlash(name))
+		if err = os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			log.Fatal(err)
+		}
+		if err = os.WriteFile(name, data, 0o644); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if *verify {
+		loader = openapi3.NewLoader()
+		loader.IsExternalRefsAllowed = true
+		split, err := loader.LoadFromFile(filepath.Join(*out, root))
+		if err != nil {
+			log.Fatalln("Reloading error:", err)
+		}
+		if err = split.Validate(loader.Context); err != nil {
+			log.Fatalln("Validation error:", err)
+		}
+	}
+}
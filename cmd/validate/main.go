@@ -10,6 +10,7 @@ import (
 
 	"github.com/getkin/kin-openapi/openapi2"
 	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
 )
 
 var (
@@ -24,7 +25,7 @@ var (
 
 var (
 	defaultExt = false
-	ext        = flag.Bool("ext", defaultExt, "enables visiting other files")
+	ext        = flag.Bool("ext", defaultExt, "enables visiting other files, including the external values of examples")
 )
 
 var (
@@ -61,6 +62,7 @@ func main() {
 	case vd.OpenAPI == "3" || strings.HasPrefix(vd.OpenAPI, "3."):
 		loader := openapi3.NewLoader()
 		loader.IsExternalRefsAllowed = *ext
+		loader.LoadExternalExamples = *ext && *examples
 
 		var doc *openapi3.T
 		if filename == "-" {
@@ -78,6 +80,8 @@ func main() {
 		}
 		if !*examples {
 			opts = append(opts, openapi3.DisableExamplesValidation())
+		} else {
+			opts = append(opts, openapi3.ExternalExamplesDecoder(openapi3filter.DecodeExample))
 		}
 		if !*patterns {
 			opts = append(opts, openapi3.DisableSchemaPatternValidation())
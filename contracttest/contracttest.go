@@ -0,0 +1,154 @@
+package contracttest
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+// Tester serves requests with an http.Handler and checks that they and the
+// responses of the handler comply with an OpenAPI document, and records the
+// coverage of the document with an openapi3filter.CoverageRecorder. It is safe
+// for concurrent use, such as by parallel tests.
+type Tester struct {
+	handler http.Handler
+	router  routers.Router
+	options openapi3filter.Options
+
+	coverage *openapi3filter.CoverageRecorder
+}
+
+// Option configures a Tester.
+type Option func(*Tester)
+
+// WithRouter routes requests with router instead of a gorillamux router of the
+// document.
+func WithRouter(router routers.Router) Option {
+	return func(ts *Tester) {
+		ts.router = router
+	}
+}
+
+// ValidationOptions sets the options requests and responses are validated
+// with. They default to reporting every error rather than the first one, and
+// to not authenticating requests.
+func ValidationOptions(options openapi3filter.Options) Option {
+	return func(ts *Tester) {
+		ts.options = options
+	}
+}
+
+// NewTester returns a Tester of handler against doc. Unless WithRouter is
+// given, the servers of doc are served from their paths, whatever the host
+// requests are sent to.
+func NewTester(doc *openapi3.T, handler http.Handler, options ...Option) (*Tester, error) {
+	ts := &Tester{
+		handler: handler,
+		options: openapi3filter.Options{
+			MultiError:         true,
+			AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+		},
+		coverage: openapi3filter.NewCoverageRecorder(doc),
+	}
+	for _, option := range options {
+		option(ts)
+	}
+	if ts.router == nil {
+		router, err := gorillamux.NewLocalRouter(doc)
+		if err != nil {
+			return nil, err
+		}
+		ts.router = router
+	}
+	return ts, nil
+}
+
+// Check serves req with the handler, and returns the response it recorded
+// with the violations of the request and of the response. The request is
+// served even when it does not comply, so that tests can check how the
+// handler rejects it.
+func (ts *Tester) Check(req *http.Request) (*httptest.ResponseRecorder, []*openapi3filter.Violation) {
+	ctx := req.Context()
+
+	// The handler and validation each read their own copy of the body, and
+	// validation is free to change its copy, such as to set defaults
+	var violations []*openapi3filter.Violation
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			violations = append(violations, &openapi3filter.Violation{Request: req, Err: err})
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	validated := req.Clone(ctx)
+	validated.Body = io.NopCloser(bytes.NewReader(body))
+
+	route, pathParams, err := ts.router.FindRoute(validated)
+	if err != nil {
+		violations = append(violations, &openapi3filter.Violation{Request: req, Err: err})
+	}
+	var requestValidationInput *openapi3filter.RequestValidationInput
+	if route != nil {
+		requestValidationInput = &openapi3filter.RequestValidationInput{
+			Request:    validated,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    &ts.options,
+		}
+		if err := openapi3filter.ValidateRequest(ctx, requestValidationInput); err != nil {
+			violations = append(violations, &openapi3filter.Violation{Request: req, Err: err})
+		}
+		ts.coverage.RecordRequest(ctx, requestValidationInput)
+	}
+
+	rec := httptest.NewRecorder()
+	ts.handler.ServeHTTP(rec, req)
+
+	if route != nil {
+		responseValidationInput := &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: requestValidationInput,
+			Status:                 rec.Code,
+			Header:                 rec.Header(),
+			Body:                   io.NopCloser(bytes.NewReader(rec.Body.Bytes())),
+			Options:                &ts.options,
+		}
+		if err := openapi3filter.ValidateResponse(ctx, responseValidationInput); err != nil {
+			violations = append(violations, &openapi3filter.Violation{Request: req, StatusCode: rec.Code, Err: err})
+		}
+		ts.coverage.RecordResponse(ctx, responseValidationInput)
+	}
+	return rec, violations
+}
+
+// Do serves req with the handler as Check does, and fails t with a description
+// of each violation.
+func (ts *Tester) Do(t testing.TB, req *http.Request) *httptest.ResponseRecorder {
+	t.Helper()
+	rec, violations := ts.Check(req)
+	for _, v := range violations {
+		t.Error(Describe(v))
+	}
+	return rec
+}
+
+// Coverage returns what the requests served so far, and the responses to
+// them, exercised of the operations of the document.
+func (ts *Tester) Coverage() *openapi3filter.CoverageReport {
+	return ts.coverage.Report()
+}
+
+// Report writes the coverage of the requests served so far to w, as a table of
+// the parameters, responses and oneOf branches of every operation with the
+// number of times they were exercised.
+func (ts *Tester) Report(w io.Writer) error {
+	return ts.Coverage().WriteText(w)
+}
@@ -0,0 +1,201 @@
+package contracttest_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/contracttest"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+)
+
+const spec = `
+openapi: 3.0.0
+info:
+  title: Pets
+  version: 1.0.0
+servers:
+  - url: https://pets.example.com/v1
+paths:
+  /pets:
+    post:
+      operationId: createPet
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/NewPet'
+      responses:
+        "201":
+          description: Created
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+        "400":
+          description: Invalid pet
+  /pets/{id}:
+    get:
+      operationId: getPet
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema: {type: integer}
+      responses:
+        "200":
+          description: A pet
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+            text/plain:
+              schema: {type: string}
+        default:
+          description: An error
+components:
+  schemas:
+    NewPet:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string}
+    Pet:
+      type: object
+      required: [id, name]
+      properties:
+        id: {type: integer}
+        name: {type: string}
+`
+
+func newTester(t *testing.T, handler http.HandlerFunc) *contracttest.Tester {
+	t.Helper()
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData([]byte(spec))
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(loader.Context))
+	tester, err := contracttest.NewTester(doc, handler)
+	require.NoError(t, err)
+	return tester
+}
+
+func newPost(body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/v1/pets", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+// recordingT records the errors of a test instead of failing it.
+type recordingT struct {
+	testing.TB
+	errors []string
+}
+
+func (t *recordingT) Helper() {}
+
+func (t *recordingT) Error(args ...any) {
+	for _, arg := range args {
+		t.errors = append(t.errors, arg.(string))
+	}
+}
+
+func TestTester(t *testing.T) {
+	tester := newTester(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost:
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			if !strings.Contains(string(body), `"name"`) {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":1,"name":"Rex"}`))
+		case r.URL.Path == "/v1/pets/1":
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.Write([]byte(`{"id":1,"name":"Rex"}`))
+		case r.URL.Path == "/v1/pets/2":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"id":"2"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	rec := tester.Do(t, newPost(`{"name":"Rex"}`))
+	require.Equal(t, http.StatusCreated, rec.Code)
+	require.JSONEq(t, `{"id":1,"name":"Rex"}`, rec.Body.String())
+	tester.Do(t, httptest.NewRequest(http.MethodGet, "/v1/pets/1", nil))
+	tester.Do(t, httptest.NewRequest(http.MethodGet, "/v1/pets/3", nil))
+
+	// An invalid request is served, for tests of how the handler rejects it
+	rec, violations := tester.Check(newPost(`{}`))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	require.Len(t, violations, 1)
+	require.Equal(t, `POST /v1/pets: invalid request:
+  request body: doesn't match schema #/components/schemas/NewPet:
+    at /name: property "name" is missing
+      - want: required: ["name"]
+      + got:  {}`, contracttest.Describe(violations[0]))
+
+	// Every schema error of an invalid response is described
+	rt := &recordingT{TB: t}
+	tester.Do(rt, httptest.NewRequest(http.MethodGet, "/v1/pets/2", nil))
+	require.Equal(t, []string{`GET /v1/pets/2: invalid response with status 200:
+  response body doesn't match schema #/components/schemas/Pet:
+    at /id: value must be an integer
+      - want: type: "integer"
+      + got:  "2"
+    at /name: property "name" is missing
+      - want: required: ["id","name"]
+      + got:  {"id":"2"}`}, rt.errors)
+
+	// Requests that match no operation are violations
+	_, violations = tester.Check(httptest.NewRequest(http.MethodDelete, "/v1/pets/1", nil))
+	require.Len(t, violations, 1)
+	require.Equal(t, "DELETE /v1/pets/1: invalid request:\n  method not allowed", contracttest.Describe(violations[0]))
+}
+
+func TestTesterCoverage(t *testing.T) {
+	tester := newTester(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte("Rex"))
+	})
+	tester.Do(t, httptest.NewRequest(http.MethodGet, "/v1/pets/1", nil))
+	tester.Do(t, httptest.NewRequest(http.MethodGet, "/v1/pets/2", nil))
+
+	coverage := tester.Coverage()
+	require.Equal(t, "1/2 operations, 1/1 parameters, 1/5 responses, 0/0 oneOf branches", coverage.Counts().String())
+	require.Equal(t, &openapi3filter.OperationCoverage{
+		Method:      http.MethodGet,
+		Path:        "/pets/{id}",
+		OperationID: "getPet",
+		Calls:       2,
+		Parameters:  []*openapi3filter.ParameterCoverage{{Name: "id", In: "path", Calls: 2}},
+		Responses: []*openapi3filter.ResponseCoverage{
+			{Status: "200", ContentType: "application/json"},
+			{Status: "200", ContentType: "text/plain", Calls: 2},
+			{Status: "default"},
+		},
+	}, coverage.Operations[1])
+
+	var report strings.Builder
+	require.NoError(t, tester.Report(&report))
+	require.Equal(t, `API coverage: 37.5% (1/2 operations, 1/1 parameters, 1/5 responses, 0/0 oneOf branches)
+POST  /pets        createPet             -
+        response   201 application/json  -
+        response   400                   -
+GET   /pets/{id}   getPet                2
+        parameter  path id               2
+        response   200 application/json  -
+        response   200 text/plain        2
+        response   default               -
+`, report.String())
+}
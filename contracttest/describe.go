@@ -0,0 +1,118 @@
+package contracttest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+)
+
+// maxValueLength is the length past which values are cut in descriptions.
+const maxValueLength = 120
+
+// Describe returns a readable description of err, such as a
+// *openapi3filter.Violation. It lists every schema error err is made of with
+// the location of the value, what the schema wants and what the value is:
+//
+//	GET /pets/1: invalid response with status 200:
+//	  response body doesn't match schema:
+//	    at /name: property "name" is missing
+//	      - want: required: ["id","name"]
+//	      + got:  {"id":1}
+func Describe(err error) string {
+	var b strings.Builder
+	var v *openapi3filter.Violation
+	if errors.As(err, &v) {
+		if v.StatusCode != 0 {
+			fmt.Fprintf(&b, "%s %s: invalid response with status %d:", v.Request.Method, v.Request.URL, v.StatusCode)
+		} else {
+			fmt.Fprintf(&b, "%s %s: invalid request:", v.Request.Method, v.Request.URL)
+		}
+		err = v.Err
+	} else {
+		b.WriteString("violation:")
+	}
+	describe(&b, err, 1)
+	return b.String()
+}
+
+func describe(b *strings.Builder, err error, depth int) {
+	indent := "\n" + strings.Repeat("  ", depth)
+	switch e := err.(type) {
+	case openapi3.MultiError:
+		for _, err := range e {
+			describe(b, err, depth)
+		}
+	case *openapi3filter.RequestError:
+		heading := e.Reason
+		switch {
+		case e.Parameter != nil:
+			heading = strings.TrimSuffix(fmt.Sprintf("parameter %q in %s: %s", e.Parameter.Name, e.Parameter.In, e.Reason), ": ")
+		case e.RequestBody != nil:
+			heading = strings.TrimSuffix("request body: "+e.Reason, ": ")
+		}
+		if e.Err == nil || e.Reason == e.Err.Error() {
+			fmt.Fprintf(b, "%s%s", indent, e)
+			return
+		}
+		fmt.Fprintf(b, "%s%s:", indent, heading)
+		describe(b, e.Err, depth+1)
+	case *openapi3filter.ResponseError:
+		if e.Err == nil {
+			fmt.Fprintf(b, "%s%s", indent, e.Reason)
+			return
+		}
+		fmt.Fprintf(b, "%s%s:", indent, e.Reason)
+		describe(b, e.Err, depth+1)
+	case *openapi3.SchemaError:
+		reason := e.Reason
+		if reason == "" {
+			if e.Origin != nil {
+				reason = e.Origin.Error()
+			} else {
+				reason = fmt.Sprintf("doesn't match schema %q", e.SchemaField)
+			}
+		}
+		fmt.Fprintf(b, "%sat /%s: %s", indent, strings.Join(e.JSONPointer(), "/"), reason)
+		fmt.Fprintf(b, "%s  - want: %s", indent, want(e))
+		fmt.Fprintf(b, "%s  + got:  %s", indent, compact(e.Value))
+	default:
+		fmt.Fprintf(b, "%s%v", indent, err)
+	}
+}
+
+// want returns the keyword of the schema that err is about, or the schema
+// when err is not about a single keyword.
+func want(err *openapi3.SchemaError) string {
+	if err.Schema == nil {
+		return "{}"
+	}
+	if err.SchemaField != "" {
+		data, _ := json.Marshal(err.Schema)
+		var fields map[string]json.RawMessage
+		if json.Unmarshal(data, &fields) == nil {
+			if field, ok := fields[err.SchemaField]; ok {
+				return err.SchemaField + ": " + cut(string(field))
+			}
+		}
+	}
+	return compact(err.Schema)
+}
+
+func compact(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return cut(string(data))
+}
+
+func cut(s string) string {
+	if len(s) > maxValueLength {
+		return s[:maxValueLength] + "..."
+	}
+	return s
+}
@@ -0,0 +1,31 @@
+// Package contracttest tests that HTTP handlers comply with the OpenAPI
+// document of their API.
+//
+// A Tester serves requests with a handler through an httptest.ResponseRecorder,
+// validates both the request and the response with openapi3filter, and fails
+// the test with a readable description of what does not comply:
+//
+//	tester, err := contracttest.NewTester(doc, handler)
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	rec := tester.Do(t, httptest.NewRequest(http.MethodGet, "/pets/1", nil))
+//
+// A Tester also accounts for the operations, parameters, response statuses and
+// content types, and oneOf branches its requests exercised. Share it between
+// the tests of a package to report the coverage of the whole run at the end of
+// TestMain, or to fail the run below a threshold:
+//
+//	var tester *contracttest.Tester
+//
+//	func TestMain(m *testing.M) {
+//		// load doc and set up tester...
+//		code := m.Run()
+//		tester.Report(os.Stdout)
+//		if err := tester.Coverage().Check(80); err != nil && code == 0 {
+//			fmt.Println(err)
+//			code = 1
+//		}
+//		os.Exit(code)
+//	}
+package contracttest
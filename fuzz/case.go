@@ -0,0 +1,447 @@
+package fuzz
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+)
+
+// caseBuilder makes up the Case of a route, a seed and a mutation.
+type caseBuilder struct {
+	h         *Harness
+	route     *routers.Route
+	mutation  Mutation
+	rand      *rand.Rand
+	generator *openapi3.ValueGenerator
+
+	parameters []*openapi3.Parameter
+	// values are the values of the parameters sent, raw ones the serialized
+	// values of mutated parameters.
+	values map[*openapi3.Parameter]any
+	raw    map[*openapi3.Parameter]string
+	// flipped is the parameter serialized with the opposite explode.
+	flipped *openapi3.Parameter
+
+	contentType string
+	body        any
+	rawBody     []byte
+	description string
+}
+
+func newCaseBuilder(h *Harness, route *routers.Route, seed uint64, mutation Mutation) *caseBuilder {
+	stream := uint64(slices.Index(h.routes, route))<<8 | uint64(mutation)
+	return &caseBuilder{
+		h:         h,
+		route:     route,
+		mutation:  mutation,
+		rand:      rand.New(rand.NewPCG(seed, stream)),
+		generator: openapi3.NewValueGenerator(seed^stream, openapi3.GenerateAsRequest()),
+		values:    make(map[*openapi3.Parameter]any),
+		raw:       make(map[*openapi3.Parameter]string),
+	}
+}
+
+func (b *caseBuilder) build() (*Case, bool) {
+	b.parameters = operationParameters(b.route)
+	for _, parameter := range b.parameters {
+		if !parameter.Required && b.rand.IntN(2) == 0 {
+			continue
+		}
+		value, ok := b.generate(parameterSchema(parameter))
+		if !ok {
+			return nil, false
+		}
+		b.values[parameter] = value
+	}
+	if !b.buildBody() {
+		return nil, false
+	}
+
+	var ok bool
+	switch b.mutation {
+	case Valid:
+		ok = true
+	case MissingParameter:
+		ok = b.missingParameter()
+	case WrongParameterType:
+		ok = b.wrongParameterType()
+	case OutOfBoundsParameter:
+		ok = b.outOfBoundsParameter()
+	case WrongParameterStyle:
+		ok = b.wrongParameterStyle()
+	case MissingBody:
+		ok = b.missingBody()
+	case MissingProperty:
+		ok = b.missingProperty()
+	case WrongPropertyType:
+		ok = b.wrongPropertyType()
+	case WrongContentType:
+		ok = b.wrongContentType()
+	case MalformedBody:
+		ok = b.malformedBody()
+	}
+	if !ok {
+		return nil, false
+	}
+	return b.request()
+}
+
+func (b *caseBuilder) generate(schema *openapi3.SchemaRef) (any, bool) {
+	if schema == nil || schema.Value == nil {
+		return "", true
+	}
+	value, err := b.generator.Generate(schema.Value)
+	return value, err == nil
+}
+
+// operationParameters returns the parameters of the operation of route and
+// those of its path item it does not override.
+func operationParameters(route *routers.Route) []*openapi3.Parameter {
+	var parameters []*openapi3.Parameter
+	for _, ref := range route.Operation.Parameters {
+		if ref != nil && ref.Value != nil {
+			parameters = append(parameters, ref.Value)
+		}
+	}
+	for _, ref := range route.PathItem.Parameters {
+		if ref != nil && ref.Value != nil && route.Operation.Parameters.GetByInAndName(ref.Value.In, ref.Value.Name) == nil {
+			parameters = append(parameters, ref.Value)
+		}
+	}
+	return parameters
+}
+
+// parameterSchema returns the schema of parameter, or that of its content.
+func parameterSchema(parameter *openapi3.Parameter) *openapi3.SchemaRef {
+	if parameter.Schema != nil {
+		return parameter.Schema
+	}
+	for _, name := range componentNames(parameter.Content) {
+		return parameter.Content[name].Schema
+	}
+	return nil
+}
+
+// pick returns one of the parameters fit keeps, or nil.
+func (b *caseBuilder) pick(fit func(parameter *openapi3.Parameter) bool) *openapi3.Parameter {
+	var fitting []*openapi3.Parameter
+	for _, parameter := range b.parameters {
+		if fit(parameter) {
+			fitting = append(fitting, parameter)
+		}
+	}
+	if len(fitting) == 0 {
+		return nil
+	}
+	return fitting[b.rand.IntN(len(fitting))]
+}
+
+func (b *caseBuilder) missingParameter() bool {
+	parameter := b.pick(func(parameter *openapi3.Parameter) bool {
+		// Leaving out a path parameter would make the request for another route
+		return parameter.Required && parameter.In != openapi3.ParameterInPath
+	})
+	if parameter == nil {
+		return false
+	}
+	delete(b.values, parameter)
+	b.description = fmt.Sprintf("%s parameter %q is missing", parameter.In, parameter.Name)
+	return true
+}
+
+func (b *caseBuilder) wrongParameterType() bool {
+	parameter := b.pick(func(parameter *openapi3.Parameter) bool {
+		schema := parameterSchema(parameter)
+		return parameter.Schema != nil && schema.Value != nil &&
+			(schema.Value.Type.Is(openapi3.TypeInteger) || schema.Value.Type.Is(openapi3.TypeNumber) || schema.Value.Type.Is(openapi3.TypeBoolean))
+	})
+	if parameter == nil {
+		return false
+	}
+	b.raw[parameter] = "not-a-" + parameter.Schema.Value.Type.Slice()[0]
+	b.description = fmt.Sprintf("%s parameter %q is %q", parameter.In, parameter.Name, b.raw[parameter])
+	return true
+}
+
+func (b *caseBuilder) outOfBoundsParameter() bool {
+	parameter := b.pick(func(parameter *openapi3.Parameter) bool {
+		return parameter.Schema != nil && outOfBounds(parameter.Schema.Value) != nil
+	})
+	if parameter == nil {
+		return false
+	}
+	values := outOfBounds(parameter.Schema.Value)
+	b.values[parameter] = values[b.rand.IntN(len(values))]
+	b.description = fmt.Sprintf("%s parameter %q is %v", parameter.In, parameter.Name, b.values[parameter])
+	return true
+}
+
+// outOfBounds returns values just past the bounds of schema.
+func outOfBounds(schema *openapi3.Schema) []any {
+	if schema == nil {
+		return nil
+	}
+	var values []any
+	switch {
+	case schema.Type.Is(openapi3.TypeInteger), schema.Type.Is(openapi3.TypeNumber):
+		if schema.Min != nil {
+			values = append(values, *schema.Min-1)
+		}
+		if schema.Max != nil {
+			values = append(values, *schema.Max+1)
+		}
+	case schema.Type.Is(openapi3.TypeString):
+		if schema.MinLength > 0 {
+			values = append(values, strings.Repeat("x", int(schema.MinLength)-1))
+		}
+		if schema.MaxLength != nil {
+			values = append(values, strings.Repeat("x", int(*schema.MaxLength)+1))
+		}
+		if len(schema.Enum) != 0 {
+			values = append(values, "not-in-enum")
+		}
+	}
+	return values
+}
+
+func (b *caseBuilder) wrongParameterStyle() bool {
+	parameter := b.pick(func(parameter *openapi3.Parameter) bool {
+		switch b.values[parameter].(type) {
+		case []any, map[string]any:
+			return parameter.Schema != nil
+		}
+		return false
+	})
+	if parameter == nil {
+		return false
+	}
+	b.flipped = parameter
+	b.description = fmt.Sprintf("%s parameter %q is serialized with the opposite explode", parameter.In, parameter.Name)
+	return true
+}
+
+// requestBodyContent returns the media types of the request body.
+func (b *caseBuilder) requestBodyContent() (*openapi3.RequestBody, openapi3.Content) {
+	ref := b.route.Operation.RequestBody
+	if ref == nil || ref.Value == nil {
+		return nil, nil
+	}
+	return ref.Value, ref.Value.Content
+}
+
+// buildBody makes up the body of the request, of the first media type that
+// is JSON, or that can be encoded.
+func (b *caseBuilder) buildBody() bool {
+	requestBody, content := b.requestBodyContent()
+	if requestBody == nil || !requestBody.Required && b.mutation == Valid && b.rand.IntN(4) == 0 {
+		return true
+	}
+	names := componentNames(content)
+	slices.SortStableFunc(names, func(a, b string) int {
+		switch {
+		case isJSON(a) && !isJSON(b):
+			return -1
+		case !isJSON(a) && isJSON(b):
+			return 1
+		}
+		return 0
+	})
+	for _, name := range names {
+		if !canEncode(name) {
+			continue
+		}
+		value, ok := b.generate(content[name].Schema)
+		if !ok {
+			return false
+		}
+		b.contentType, b.body = name, value
+		return true
+	}
+	return true
+}
+
+func (b *caseBuilder) missingBody() bool {
+	if requestBody, _ := b.requestBodyContent(); requestBody == nil || !requestBody.Required || b.contentType == "" {
+		return false
+	}
+	b.contentType, b.body = "", nil
+	b.description = "the required body is missing"
+	return true
+}
+
+// bodyObject returns the schema of the body when it is an object.
+func (b *caseBuilder) bodyObject() (map[string]any, *openapi3.Schema) {
+	object, ok := b.body.(map[string]any)
+	if !ok {
+		return nil, nil
+	}
+	_, content := b.requestBodyContent()
+	schema := content[b.contentType].Schema
+	if schema == nil || schema.Value == nil {
+		return nil, nil
+	}
+	return object, schema.Value
+}
+
+func (b *caseBuilder) missingProperty() bool {
+	object, schema := b.bodyObject()
+	if schema == nil || len(schema.Required) == 0 {
+		return false
+	}
+	name := schema.Required[b.rand.IntN(len(schema.Required))]
+	delete(object, name)
+	b.description = fmt.Sprintf("body property %q is missing", name)
+	return true
+}
+
+func (b *caseBuilder) wrongPropertyType() bool {
+	object, schema := b.bodyObject()
+	if schema == nil {
+		return false
+	}
+	var names []string
+	for _, name := range componentNames(schema.Properties) {
+		if property := schema.Properties[name].Value; property != nil && property.Type != nil && !property.Type.IncludesNull() && len(property.Type.Slice()) == 1 {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return false
+	}
+	name := names[b.rand.IntN(len(names))]
+	if schema.Properties[name].Value.Type.Is(openapi3.TypeString) {
+		object[name] = int64(12345)
+	} else {
+		object[name] = "wrong type"
+	}
+	b.description = fmt.Sprintf("body property %q is %#v", name, object[name])
+	return true
+}
+
+func (b *caseBuilder) wrongContentType() bool {
+	_, content := b.requestBodyContent()
+	if len(content) == 0 || content.Get("application/x-fuzz") != nil {
+		return false
+	}
+	data, err := json.Marshal(b.body)
+	if err != nil {
+		return false
+	}
+	b.contentType, b.rawBody = "application/x-fuzz", data
+	b.description = fmt.Sprintf("body is %s", b.contentType)
+	return true
+}
+
+func (b *caseBuilder) malformedBody() bool {
+	if !isJSON(b.contentType) {
+		return false
+	}
+	data, err := json.Marshal(b.body)
+	if err != nil || len(data) < 2 {
+		return false
+	}
+	b.rawBody = data[:len(data)-1]
+	b.description = "body is malformed JSON"
+	return true
+}
+
+// request serializes the parameters and body into the request of the case.
+func (b *caseBuilder) request() (*Case, bool) {
+	c := &Case{
+		Route:       b.route,
+		PathParams:  make(map[string]string),
+		Mutation:    b.mutation,
+		Description: b.description,
+		Method:      b.route.Method,
+		Header:      make(http.Header),
+	}
+
+	path := b.route.Path
+	var query, cookies []string
+	for _, parameter := range b.parameters {
+		raw, ok := b.raw[parameter]
+		value, present := b.values[parameter]
+		if !ok && !present {
+			continue
+		}
+		method, err := parameter.SerializationMethod()
+		if err != nil {
+			return nil, false
+		}
+		if parameter == b.flipped {
+			method.Explode = !method.Explode
+		}
+		if !ok && parameter.Schema == nil {
+			// Parameters with content are serialized as JSON
+			data, err := json.Marshal(value)
+			if err != nil {
+				return nil, false
+			}
+			value = string(data)
+		}
+		switch parameter.In {
+		case openapi3.ParameterInPath:
+			if !ok {
+				raw = serializePath(parameter.Name, value, method)
+			} else {
+				raw = url.PathEscape(raw)
+			}
+			path = strings.ReplaceAll(path, "{"+parameter.Name+"}", raw)
+			if c.PathParams[parameter.Name], err = url.PathUnescape(raw); err != nil {
+				return nil, false
+			}
+		case openapi3.ParameterInQuery:
+			if !ok {
+				raw = serializeQuery(parameter.Name, value, method)
+			} else {
+				raw = url.QueryEscape(parameter.Name) + "=" + url.QueryEscape(raw)
+			}
+			if raw != "" {
+				query = append(query, raw)
+			}
+		case openapi3.ParameterInHeader:
+			if !ok {
+				raw = serializeSimple(value, method.Explode, func(s string) string { return s })
+			}
+			c.Header.Set(parameter.Name, raw)
+		case openapi3.ParameterInCookie:
+			if !ok {
+				raw = serializeSimple(value, method.Explode, url.QueryEscape)
+			}
+			cookies = append(cookies, parameter.Name+"="+raw)
+		}
+	}
+	if len(cookies) != 0 {
+		c.Header.Set("Cookie", strings.Join(cookies, "; "))
+	}
+	c.URL = &url.URL{Path: b.h.basePath + path, RawQuery: strings.Join(query, "&")}
+	c.URL.RawPath = c.URL.Path
+	if unescaped, err := url.PathUnescape(c.URL.Path); err == nil {
+		c.URL.Path = unescaped
+	}
+
+	if b.contentType != "" {
+		data, contentType := b.rawBody, b.contentType
+		if data == nil {
+			var err error
+			if data, contentType, err = encodeBody(b.body, b.contentType); err != nil {
+				return nil, false
+			}
+		}
+		c.Header.Set("Content-Type", contentType)
+		c.Body = data
+	}
+	return c, true
+}
+
+func componentNames[V any](m map[string]V) []string {
+	return slices.Sorted(maps.Keys(m))
+}
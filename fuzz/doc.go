@@ -0,0 +1,24 @@
+// Package fuzz tests the HTTP handlers of an API against its OpenAPI
+// document with requests made up from the document, valid ones and ones
+// deliberately made invalid.
+//
+// A Harness makes up a Case for an operation of the document, a seed and a
+// Mutation: a valid request with values generated by an
+// openapi3.ValueGenerator, serialized as the style and explode of their
+// parameters say, or one with a required parameter left out, a value of the
+// wrong type or out of bounds, a body that is missing, malformed or of the
+// wrong content type, and so on. It sends cases to the handler in-process and
+// reports a Failure when the handler panics, responds with a 5xx status or with
+// a response openapi3filter.ValidateResponse rejects, whatever the request.
+//
+// Check runs the cases of a number of seeds, and Fuzz hooks a Harness into
+// native Go fuzzing:
+//
+//	func FuzzAPI(f *testing.F) {
+//		h, err := fuzz.NewHarness(doc, api.NewHandler())
+//		if err != nil {
+//			f.Fatal(err)
+//		}
+//		h.Fuzz(f)
+//	}
+package fuzz
@@ -0,0 +1,304 @@
+package fuzz
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+)
+
+// Mutation is how the request of a Case departs from a valid one.
+type Mutation int
+
+const (
+	// Valid requests are made up from the document as they are.
+	Valid Mutation = iota
+	// MissingParameter requests leave out a required parameter.
+	MissingParameter
+	// WrongParameterType requests have a parameter of the wrong type, such as
+	// a string for an integer.
+	WrongParameterType
+	// OutOfBoundsParameter requests have a parameter just past the bounds of
+	// its schema: its minimum, maximum, length or enum.
+	OutOfBoundsParameter
+	// WrongParameterStyle requests have an array or object parameter
+	// serialized with the opposite explode.
+	WrongParameterStyle
+	// MissingBody requests have no body where one is required.
+	MissingBody
+	// MissingProperty requests have a body without one of its required
+	// properties.
+	MissingProperty
+	// WrongPropertyType requests have a body with a property of the wrong type.
+	WrongPropertyType
+	// WrongContentType requests have a body of a content type the operation
+	// does not accept.
+	WrongContentType
+	// MalformedBody requests have a JSON body that is cut short.
+	MalformedBody
+
+	mutations
+)
+
+var mutationNames = [...]string{
+	Valid:                "valid",
+	MissingParameter:     "missing parameter",
+	WrongParameterType:   "wrong parameter type",
+	OutOfBoundsParameter: "out of bounds parameter",
+	WrongParameterStyle:  "wrong parameter style",
+	MissingBody:          "missing body",
+	MissingProperty:      "missing property",
+	WrongPropertyType:    "wrong property type",
+	WrongContentType:     "wrong content type",
+	MalformedBody:        "malformed body",
+}
+
+// String returns the name of mutation.
+func (mutation Mutation) String() string {
+	if mutation < 0 || mutation >= mutations {
+		return fmt.Sprintf("Mutation(%d)", int(mutation))
+	}
+	return mutationNames[mutation]
+}
+
+// Case is a request the Harness sends to the handler.
+type Case struct {
+	Route      *routers.Route
+	PathParams map[string]string
+	Mutation   Mutation
+	// Description says what was mutated, such as `query parameter "limit" is 101`.
+	Description string
+
+	Method string
+	URL    *url.URL
+	Header http.Header
+	Body   []byte
+
+	// Valid is whether openapi3filter.ValidateRequest accepts the request,
+	// which a mutation may leave valid, such as when a string is as good as
+	// the value it replaces.
+	Valid bool
+}
+
+// Request returns a new request for c.
+func (c *Case) Request() *http.Request {
+	req := httptest.NewRequest(c.Method, c.URL.RequestURI(), bytes.NewReader(c.Body))
+	req.Header = c.Header.Clone()
+	return req
+}
+
+// String returns the method, URL and mutation of c.
+func (c *Case) String() string {
+	s := c.Method + " " + c.URL.RequestURI()
+	if c.Mutation != Valid {
+		s += fmt.Sprintf(" (%s: %s)", c.Mutation, c.Description)
+	}
+	return s
+}
+
+// Failure is the response of a handler that failed a Case.
+type Failure struct {
+	Case       *Case
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	// Err is why the response failed openapi3filter.ValidateResponse, or that
+	// the handler panicked. It is nil for an otherwise valid 5xx response.
+	Err error
+}
+
+// Error describes the failure.
+func (f *Failure) Error() string {
+	if f.Err != nil {
+		return fmt.Sprintf("%s: status %d: %v", f.Case, f.StatusCode, f.Err)
+	}
+	return fmt.Sprintf("%s: status %d: %s", f.Case, f.StatusCode, bytes.TrimSpace(f.Body))
+}
+
+// Unwrap returns the error of f.
+func (f *Failure) Unwrap() error { return f.Err }
+
+// Harness sends the requests it makes up from an OpenAPI document to a handler.
+type Harness struct {
+	doc      *openapi3.T
+	handler  http.Handler
+	basePath string
+	options  openapi3filter.Options
+	routes   []*routers.Route
+}
+
+// Option configures a Harness.
+type Option func(*Harness)
+
+// WithBasePath sets the path the paths of the document are under, instead of
+// that of the first server of the document.
+func WithBasePath(basePath string) Option {
+	return func(h *Harness) {
+		h.basePath = basePath
+	}
+}
+
+// ValidationOptions sets the options requests and responses are validated
+// with. Requests are not authenticated unless options.AuthenticationFunc is set.
+func ValidationOptions(options openapi3filter.Options) Option {
+	return func(h *Harness) {
+		h.options = options
+	}
+}
+
+// NewHarness returns a Harness sending requests for the operations of doc to
+// handler.
+func NewHarness(doc *openapi3.T, handler http.Handler, opts ...Option) (*Harness, error) {
+	h := &Harness{
+		doc:     doc,
+		handler: handler,
+		options: openapi3filter.Options{AuthenticationFunc: openapi3filter.NoopAuthenticationFunc},
+	}
+	var server *openapi3.Server
+	if len(doc.Servers) != 0 {
+		server = doc.Servers[0]
+		basePath, err := server.BasePath()
+		if err != nil {
+			return nil, err
+		}
+		h.basePath = basePath
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	h.basePath = strings.TrimSuffix(h.basePath, "/")
+
+	if doc.Paths != nil {
+		paths := doc.Paths.Keys()
+		slices.Sort(paths)
+		for _, path := range paths {
+			pathItem := doc.Paths.Value(path)
+			operations := pathItem.Operations()
+			for _, method := range componentNames(operations) {
+				h.routes = append(h.routes, &routers.Route{
+					Spec:      doc,
+					Server:    server,
+					Path:      path,
+					PathItem:  pathItem,
+					Method:    method,
+					Operation: operations[method],
+				})
+			}
+		}
+	}
+	return h, nil
+}
+
+// Routes returns the operations of the document, in the order of their paths
+// and methods.
+func (h *Harness) Routes() []*routers.Route {
+	return h.routes
+}
+
+// Case returns the request for the route at index route in Routes, made up
+// from seed and changed as mutation says. It returns false when mutation does
+// not apply to the operation, such as MissingBody for one without a required
+// body, or no valid value could be generated for one of its schemas.
+func (h *Harness) Case(seed uint64, route int, mutation Mutation) (*Case, bool) {
+	if route < 0 || route >= len(h.routes) || mutation < 0 || mutation >= mutations {
+		return nil, false
+	}
+	b := newCaseBuilder(h, h.routes[route], seed, mutation)
+	c, ok := b.build()
+	if !ok {
+		return nil, false
+	}
+	req := c.Request()
+	options := h.options
+	c.Valid = openapi3filter.ValidateRequest(req.Context(), &openapi3filter.RequestValidationInput{
+		Request:    req,
+		PathParams: c.PathParams,
+		Route:      c.Route,
+		Options:    &options,
+	}) == nil
+	return c, true
+}
+
+// Run sends c to the handler and returns its failure, or nil if the handler
+// passed.
+func (h *Harness) Run(c *Case) (failure *Failure) {
+	req := c.Request()
+	rec := httptest.NewRecorder()
+	defer func() {
+		if r := recover(); r != nil {
+			failure = &Failure{Case: c, StatusCode: http.StatusInternalServerError, Err: fmt.Errorf("handler panicked: %v", r)}
+		}
+	}()
+	h.handler.ServeHTTP(rec, req)
+
+	failure = &Failure{Case: c, StatusCode: rec.Code, Header: rec.Header(), Body: rec.Body.Bytes()}
+	options := h.options
+	if err := openapi3filter.ValidateResponse(req.Context(), &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: &openapi3filter.RequestValidationInput{
+			Request:    c.Request(),
+			PathParams: c.PathParams,
+			Route:      c.Route,
+			Options:    &options,
+		},
+		Status:  rec.Code,
+		Header:  rec.Header(),
+		Body:    io.NopCloser(bytes.NewReader(failure.Body)),
+		Options: &options,
+	}); err != nil {
+		failure.Err = err
+		return failure
+	}
+	if rec.Code >= 500 {
+		return failure
+	}
+	return nil
+}
+
+// Check runs the cases of every operation and mutation for seeds 0 to seeds-1,
+// and returns the failures of the handler.
+func (h *Harness) Check(seeds int) []*Failure {
+	var failures []*Failure
+	for seed := range uint64(seeds) {
+		for route := range h.routes {
+			for mutation := range mutations {
+				if c, ok := h.Case(seed, route, mutation); ok {
+					if failure := h.Run(c); failure != nil {
+						failures = append(failures, failure)
+					}
+				}
+			}
+		}
+	}
+	return failures
+}
+
+// Fuzz fuzzes the handler with f, from a seed corpus of every operation and
+// mutation. The inputs of f are the seed, route and mutation of a Case.
+func (h *Harness) Fuzz(f *testing.F) {
+	for route := range h.routes {
+		for mutation := range mutations {
+			f.Add(uint64(0), uint(route), uint(mutation))
+		}
+	}
+	f.Fuzz(func(t *testing.T, seed uint64, route, mutation uint) {
+		if len(h.routes) == 0 {
+			t.Skip("no operations")
+		}
+		c, ok := h.Case(seed, int(route%uint(len(h.routes))), Mutation(mutation%uint(mutations)))
+		if !ok {
+			t.Skip("mutation does not apply")
+		}
+		if failure := h.Run(c); failure != nil {
+			t.Error(failure)
+		}
+	})
+}
@@ -0,0 +1,130 @@
+package fuzz_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/fuzz"
+	"github.com/getkin/kin-openapi/mock"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func loadDoc(t testing.TB) *openapi3.T {
+	t.Helper()
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromFile("testdata/petstore.yaml")
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(loader.Context))
+	return doc
+}
+
+func mockHandler(t testing.TB, doc *openapi3.T) http.Handler {
+	t.Helper()
+	handler, err := mock.NewHandler(doc)
+	require.NoError(t, err)
+	return handler
+}
+
+func TestHarnessCases(t *testing.T) {
+	doc := loadDoc(t)
+	h, err := fuzz.NewHarness(doc, mockHandler(t, doc))
+	require.NoError(t, err)
+
+	var routes []string
+	for _, route := range h.Routes() {
+		routes = append(routes, route.Method+" "+route.Path)
+	}
+	require.Equal(t, []string{"GET /owners/{ids}", "GET /pets", "POST /pets", "GET /pets/{petId}"}, routes)
+
+	c, ok := h.Case(1, 0, fuzz.Valid)
+	require.True(t, ok)
+	require.Equal(t, c, func() *fuzz.Case { c, _ := h.Case(1, 0, fuzz.Valid); return c }())
+	require.Regexp(t, `^/v1/owners/(\.\d+)+$`, c.URL.Path)
+
+	_, ok = h.Case(1, 0, fuzz.MissingBody)
+	require.False(t, ok)
+
+	mutated := make(map[fuzz.Mutation]bool)
+	for seed := range uint64(20) {
+		for route := range h.Routes() {
+			for mutation := fuzz.Valid; mutation <= fuzz.MalformedBody; mutation++ {
+				c, ok := h.Case(seed, route, mutation)
+				if !ok {
+					continue
+				}
+				if mutation == fuzz.Valid {
+					require.True(t, c.Valid, c.String())
+					continue
+				}
+				mutated[mutation] = mutated[mutation] || !c.Valid
+			}
+		}
+	}
+	// Every mutation made some requests invalid
+	for mutation := fuzz.MissingParameter; mutation <= fuzz.MalformedBody; mutation++ {
+		require.True(t, mutated[mutation], mutation.String())
+	}
+}
+
+func TestHarnessCheck(t *testing.T) {
+	doc := loadDoc(t)
+
+	h, err := fuzz.NewHarness(doc, mockHandler(t, doc))
+	require.NoError(t, err)
+	require.Empty(t, h.Check(20))
+
+	h, err = fuzz.NewHarness(doc, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v1/pets" && r.Method == http.MethodGet:
+			// Fails on limits it did not expect
+			if limit, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && limit > 50 {
+				http.Error(w, "limit too large", http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode([]any{})
+		case r.Method == http.MethodPost:
+			// Responds without the id of the pet
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"name":"Tom"}`))
+		case r.URL.Path == "/v1/pets/1":
+			panic("no such pet")
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	require.NoError(t, err)
+
+	failures := h.Check(20)
+	require.NotEmpty(t, failures)
+	var serverErrors, invalidResponses, panics int
+	for _, failure := range failures {
+		switch {
+		case failure.Err == nil:
+			require.Equal(t, http.StatusInternalServerError, failure.StatusCode)
+			require.Equal(t, "GET", failure.Case.Method)
+			serverErrors++
+		case failure.Case.Method == http.MethodPost:
+			require.ErrorContains(t, failure, `property "id" is missing`)
+			invalidResponses++
+		default:
+			require.ErrorContains(t, failure, "handler panicked: no such pet")
+			panics++
+		}
+	}
+	require.NotZero(t, serverErrors)
+	require.NotZero(t, invalidResponses)
+	require.NotZero(t, panics)
+}
+
+func FuzzHarness(f *testing.F) {
+	doc := loadDoc(f)
+	h, err := fuzz.NewHarness(doc, mockHandler(f, doc))
+	require.NoError(f, err)
+	h.Fuzz(f)
+}
@@ -0,0 +1,221 @@
+package fuzz
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+)
+
+// formatScalar formats a primitive value of a parameter.
+func formatScalar(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case []any, map[string]any:
+		data, _ := json.Marshal(value)
+		return string(data)
+	}
+	return fmt.Sprint(value)
+}
+
+// objectPairs returns the keys and values of object in the order of its keys.
+func objectPairs(object map[string]any) (keys, values []string) {
+	for _, k := range componentNames(object) {
+		keys = append(keys, k)
+		values = append(values, formatScalar(object[k]))
+	}
+	return
+}
+
+// serializeSimple serializes a value in the simple style, escaping each of
+// its parts with escape.
+func serializeSimple(value any, explode bool, escape func(string) string) string {
+	switch value := value.(type) {
+	case []any:
+		items := make([]string, 0, len(value))
+		for _, item := range value {
+			items = append(items, escape(formatScalar(item)))
+		}
+		return strings.Join(items, ",")
+	case map[string]any:
+		keys, values := objectPairs(value)
+		var parts []string
+		for i, k := range keys {
+			if explode {
+				parts = append(parts, escape(k)+"="+escape(values[i]))
+			} else {
+				parts = append(parts, escape(k), escape(values[i]))
+			}
+		}
+		return strings.Join(parts, ",")
+	}
+	return escape(formatScalar(value))
+}
+
+// serializePath serializes the value of a path parameter.
+func serializePath(name string, value any, method *openapi3.SerializationMethod) string {
+	escape := url.PathEscape
+	switch method.Style {
+	case openapi3.SerializationLabel:
+		s := serializeSimple(value, method.Explode, escape)
+		if method.Explode {
+			s = strings.ReplaceAll(s, ",", ".")
+		}
+		return "." + s
+	case openapi3.SerializationMatrix:
+		switch v := value.(type) {
+		case []any:
+			if method.Explode {
+				var s string
+				for _, item := range v {
+					s += ";" + escape(name) + "=" + escape(formatScalar(item))
+				}
+				return s
+			}
+		case map[string]any:
+			if method.Explode {
+				var s string
+				keys, values := objectPairs(v)
+				for i, k := range keys {
+					s += ";" + escape(k) + "=" + escape(values[i])
+				}
+				return s
+			}
+		}
+		return ";" + escape(name) + "=" + serializeSimple(value, false, escape)
+	}
+	return serializeSimple(value, method.Explode, escape)
+}
+
+// serializeQuery serializes a query parameter into its part of a query string.
+func serializeQuery(name string, value any, method *openapi3.SerializationMethod) string {
+	escape := url.QueryEscape
+	pair := func(k, v string) string { return escape(k) + "=" + escape(v) }
+	switch v := value.(type) {
+	case []any:
+		if len(v) == 0 {
+			// An empty array cannot be told from an array of an empty string
+			return ""
+		}
+		separator := ","
+		switch method.Style {
+		case openapi3.SerializationSpaceDelimited:
+			separator = " "
+		case openapi3.SerializationPipeDelimited:
+			separator = "|"
+		}
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, formatScalar(item))
+		}
+		if method.Explode {
+			var pairs []string
+			for _, item := range items {
+				pairs = append(pairs, pair(name, item))
+			}
+			return strings.Join(pairs, "&")
+		}
+		return pair(name, strings.Join(items, separator))
+	case map[string]any:
+		keys, values := objectPairs(v)
+		var pairs []string
+		switch {
+		case method.Style == openapi3.SerializationDeepObject:
+			for i, k := range keys {
+				pairs = append(pairs, pair(name+"["+k+"]", values[i]))
+			}
+		case method.Explode:
+			for i, k := range keys {
+				pairs = append(pairs, pair(k, values[i]))
+			}
+		default:
+			var parts []string
+			for i, k := range keys {
+				parts = append(parts, k, values[i])
+			}
+			return pair(name, strings.Join(parts, ","))
+		}
+		return strings.Join(pairs, "&")
+	}
+	return pair(name, formatScalar(value))
+}
+
+func isJSON(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// canEncode reports whether a body of contentType can be encoded.
+func canEncode(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case isJSON(contentType),
+		mediaType == "application/x-www-form-urlencoded",
+		mediaType == "multipart/form-data",
+		strings.HasPrefix(mediaType, "text/"):
+		return true
+	}
+	return openapi3filter.RegisteredBodyEncoder(mediaType) != nil
+}
+
+// encodeBody encodes a body as contentType, and returns the Content-Type
+// header to send it with.
+func encodeBody(body any, contentType string) ([]byte, string, error) {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case isJSON(contentType):
+		data, err := json.Marshal(body)
+		return data, contentType, err
+	case mediaType == "application/x-www-form-urlencoded":
+		object, _ := body.(map[string]any)
+		values := make(url.Values)
+		for _, k := range componentNames(object) {
+			if items, ok := object[k].([]any); ok {
+				for _, item := range items {
+					values.Add(k, formatScalar(item))
+				}
+				continue
+			}
+			values.Set(k, formatScalar(object[k]))
+		}
+		return []byte(values.Encode()), contentType, nil
+	case mediaType == "multipart/form-data":
+		object, _ := body.(map[string]any)
+		var buf bytes.Buffer
+		w := multipart.NewWriter(&buf)
+		for _, k := range componentNames(object) {
+			items, ok := object[k].([]any)
+			if !ok {
+				items = []any{object[k]}
+			}
+			for _, item := range items {
+				if err := w.WriteField(k, formatScalar(item)); err != nil {
+					return nil, "", err
+				}
+			}
+		}
+		if err := w.Close(); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), w.FormDataContentType(), nil
+	case strings.HasPrefix(mediaType, "text/"):
+		return []byte(formatScalar(body)), contentType, nil
+	}
+	if encoder := openapi3filter.RegisteredBodyEncoder(mediaType); encoder != nil {
+		data, err := encoder(body)
+		return data, contentType, err
+	}
+	return nil, "", fmt.Errorf("cannot encode a body as %q", contentType)
+}
@@ -0,0 +1,76 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+
+	"github.com/oasdiff/yaml"
+)
+
+// Config configures the rules of a Linter.
+type Config struct {
+	// Rules configures rules by name. Rules it does not list keep their
+	// default severity.
+	Rules map[string]RuleConfig `json:"rules,omitempty" yaml:"rules,omitempty"`
+}
+
+// RuleConfig configures a rule. It is written as the name of its severity
+// when there are no options.
+type RuleConfig struct {
+	Severity Severity       `json:"severity" yaml:"severity"`
+	Options  map[string]any `json:"options,omitempty" yaml:"options,omitempty"`
+}
+
+// UnmarshalJSON sets config from the name of a severity or an object.
+func (config *RuleConfig) UnmarshalJSON(data []byte) error {
+	var severity Severity
+	if err := json.Unmarshal(data, &severity); err == nil {
+		*config = RuleConfig{Severity: severity}
+		return nil
+	}
+	type RuleConfigBis RuleConfig
+	x := RuleConfigBis{Severity: -1}
+	if err := json.Unmarshal(data, &x); err != nil {
+		return err
+	}
+	if x.Severity < 0 {
+		return fmt.Errorf("missing severity")
+	}
+	*config = RuleConfig(x)
+	return nil
+}
+
+// ParseConfig decodes a Config from YAML or JSON data.
+func ParseConfig(data []byte) (*Config, error) {
+	config := &Config{}
+	if _, err := yaml.Unmarshal(data, config, yaml.DecodeOpts{DisableTimestamps: true}); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// LoadConfig decodes a Config from the YAML or JSON file filename.
+func LoadConfig(filename string) (*Config, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	config, err := ParseConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration %q: %w", filename, err)
+	}
+	return config, nil
+}
+
+// validate returns an error if config configures rules not in rules.
+func (config *Config) validate(rules []Rule) error {
+	for _, name := range slices.Sorted(maps.Keys(config.Rules)) {
+		if !slices.ContainsFunc(rules, func(r Rule) bool { return r.Name() == name }) {
+			return fmt.Errorf("unknown rule %q", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+// Package lint checks OpenAPI documents against style rules, such as naming
+// conventions or the use of problem details for errors, that Validate does not
+// enforce because the specification does not require them.
+//
+// A Linter runs Rules over a document and reports Findings, each with the
+// severity its rule is configured with, the JSON Pointer of the node it is
+// about and, for documents loaded with openapi3.Loader.IncludeOrigin, its
+// location in the source files:
+//
+//	config, err := lint.LoadConfig(".openapi-lint.yaml")
+//	if err != nil {
+//		return err
+//	}
+//	findings, err := lint.NewLinter(config).Lint(doc)
+//	if err != nil {
+//		return err
+//	}
+//	for _, finding := range findings {
+//		fmt.Println(finding)
+//	}
+//
+// The configuration sets the severity of the rules, and their options:
+//
+//	rules:
+//	  operation-summary: off
+//	  path-kebab-case: error
+//	  pagination-parameters:
+//	    severity: warn
+//	    options:
+//	      parameters: [page, per_page]
+//
+// A node of the document silences rules for itself and what it contains with
+// an x-lint-ignore extension listing them, or set to true for every rule:
+//
+//	paths:
+//	  /legacy_reports:
+//	    x-lint-ignore: [path-kebab-case]
+//
+// BuiltinRules lists the rules of the package. Others implement Rule, or are
+// made with NewRule.
+package lint
@@ -0,0 +1,295 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// IgnoreExtension is the extension that silences rules for a node and what it
+// contains. Its value is the name of a rule, a list of them, or true for all.
+const IgnoreExtension = "x-lint-ignore"
+
+// Severity is how much a Finding matters.
+type Severity int
+
+const (
+	// Off disables a rule.
+	Off Severity = iota
+	Hint
+	Info
+	Warn
+	Error
+)
+
+var severityNames = [...]string{Off: "off", Hint: "hint", Info: "info", Warn: "warn", Error: "error"}
+
+// ParseSeverity returns the Severity named name: off, hint, info, warn (or
+// warning) or error.
+func ParseSeverity(name string) (Severity, error) {
+	if name == "warning" {
+		return Warn, nil
+	}
+	for severity, n := range severityNames {
+		if n == name {
+			return Severity(severity), nil
+		}
+	}
+	return Off, fmt.Errorf("unknown severity %q", name)
+}
+
+// String returns the name of severity.
+func (severity Severity) String() string {
+	if severity < 0 || int(severity) >= len(severityNames) {
+		return fmt.Sprintf("Severity(%d)", int(severity))
+	}
+	return severityNames[severity]
+}
+
+// MarshalText returns the name of severity.
+func (severity Severity) MarshalText() ([]byte, error) {
+	return []byte(severity.String()), nil
+}
+
+// UnmarshalText sets severity to the Severity named text.
+func (severity *Severity) UnmarshalText(text []byte) error {
+	s, err := ParseSeverity(string(text))
+	if err != nil {
+		return err
+	}
+	*severity = s
+	return nil
+}
+
+// Finding is a violation of a rule.
+type Finding struct {
+	Rule        string           `json:"rule" yaml:"rule"`
+	Severity    Severity         `json:"severity" yaml:"severity"`
+	Message     string           `json:"message" yaml:"message"`
+	JSONPointer string           `json:"jsonPointer" yaml:"jsonPointer"`
+	Origin      *openapi3.Origin `json:"origin,omitempty" yaml:"origin,omitempty"`
+}
+
+// String formats finding as "file:line:column: severity: message (rule)", or
+// with the JSON Pointer of its node when its location is unknown.
+func (finding Finding) String() string {
+	where := "#" + finding.JSONPointer
+	if origin := finding.Origin; origin != nil && origin.Key != nil {
+		where = fmt.Sprintf("%s:%d:%d", origin.Key.File, origin.Key.Line, origin.Key.Column)
+	}
+	return fmt.Sprintf("%s: %s: %s (%s)", where, finding.Severity, finding.Message, finding.Rule)
+}
+
+// Rule checks a document against a convention.
+type Rule interface {
+	// Name identifies the rule in configurations, x-lint-ignore extensions
+	// and findings, such as "operation-summary".
+	Name() string
+	// Description says what the rule requires.
+	Description() string
+	// DefaultSeverity is the severity of the rule when it is not configured.
+	DefaultSeverity() Severity
+	// Check reports the violations of the rule in c.Doc with c.Report. It
+	// returns an error when the rule cannot check, such as for invalid options.
+	Check(c *Context) error
+}
+
+// NewRule returns a Rule that checks documents with check.
+func NewRule(name, description string, severity Severity, check func(c *Context) error) Rule {
+	return &rule{name: name, description: description, severity: severity, check: check}
+}
+
+type rule struct {
+	name, description string
+	severity          Severity
+	check             func(c *Context) error
+}
+
+func (r *rule) Name() string              { return r.name }
+func (r *rule) Description() string       { return r.description }
+func (r *rule) DefaultSeverity() Severity { return r.severity }
+func (r *rule) Check(c *Context) error    { return r.check(c) }
+
+// Context is what a Rule checks.
+type Context struct {
+	// Doc is the document to check.
+	Doc *openapi3.T
+	// Options are the options of the rule from the configuration.
+	Options map[string]any
+
+	rule     Rule
+	severity Severity
+	ignored  map[string]map[string]bool
+	findings []Finding
+}
+
+// Report reports a violation of the rule by node, a value of the document as
+// Walk visits them, at jsonPointer. The location of the finding is the origin
+// of node.
+func (c *Context) Report(jsonPointer string, node any, format string, args ...any) {
+	if c.isIgnored(jsonPointer) {
+		return
+	}
+	c.findings = append(c.findings, Finding{
+		Rule:        c.rule.Name(),
+		Severity:    c.severity,
+		Message:     fmt.Sprintf(format, args...),
+		JSONPointer: jsonPointer,
+		Origin:      nodeOrigin(node),
+	})
+}
+
+// Option decodes the option name of the rule into v, which is left as is when
+// the option is not set.
+func (c *Context) Option(name string, v any) error {
+	value, ok := c.Options[name]
+	if !ok {
+		return nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("invalid option %q: %w", name, err)
+	}
+	return nil
+}
+
+// isIgnored reports whether an x-lint-ignore extension of the node at
+// jsonPointer or of one containing it silences the rule.
+func (c *Context) isIgnored(jsonPointer string) bool {
+	for p := jsonPointer; ; {
+		if rules, ok := c.ignored[p]; ok && (rules["*"] || rules[c.rule.Name()]) {
+			return true
+		}
+		i := strings.LastIndexByte(p, '/')
+		if i < 0 {
+			return false
+		}
+		p = p[:i]
+	}
+}
+
+// Linter checks documents against rules.
+type Linter struct {
+	// Rules are the rules to check.
+	Rules []Rule
+	// Config configures the rules, which have their default severity and no
+	// options when it is nil.
+	Config *Config
+}
+
+// NewLinter returns a Linter of the BuiltinRules configured with config.
+func NewLinter(config *Config) *Linter {
+	return &Linter{Rules: BuiltinRules(), Config: config}
+}
+
+// Lint checks doc against the rules of linter and returns their findings,
+// rule by rule. Rules that are off are not checked. It returns an error when
+// the configuration does not fit the rules.
+func (linter *Linter) Lint(doc *openapi3.T) ([]Finding, error) {
+	if linter.Config != nil {
+		if err := linter.Config.validate(linter.Rules); err != nil {
+			return nil, err
+		}
+	}
+	ignored := ignoredRules(doc)
+	var findings []Finding
+	for _, r := range linter.Rules {
+		c := &Context{Doc: doc, rule: r, severity: r.DefaultSeverity(), ignored: ignored}
+		if linter.Config != nil {
+			if config, ok := linter.Config.Rules[r.Name()]; ok {
+				c.severity = config.Severity
+				c.Options = config.Options
+			}
+		}
+		if c.severity == Off {
+			continue
+		}
+		if err := r.Check(c); err != nil {
+			return nil, fmt.Errorf("rule %q: %w", r.Name(), err)
+		}
+		findings = append(findings, c.findings...)
+	}
+	return findings, nil
+}
+
+// ignoredRules returns the rules the x-lint-ignore extensions of doc silence,
+// by the JSON Pointer of their node.
+func ignoredRules(doc *openapi3.T) map[string]map[string]bool {
+	ignored := make(map[string]map[string]bool)
+	_ = openapi3.Walk(doc, openapi3.VisitorFunc(func(c *openapi3.WalkCursor) error {
+		for _, extensions := range nodeExtensions(c.Value) {
+			value, ok := extensions[IgnoreExtension]
+			if !ok {
+				continue
+			}
+			rules := ignored[c.JSONPointer]
+			if rules == nil {
+				rules = make(map[string]bool)
+				ignored[c.JSONPointer] = rules
+			}
+			switch value := value.(type) {
+			case bool:
+				rules["*"] = rules["*"] || value
+			case string:
+				rules[value] = true
+			case []any:
+				for _, name := range value {
+					if name, ok := name.(string); ok {
+						rules[name] = true
+					}
+				}
+			}
+		}
+		return nil
+	}))
+	return ignored
+}
+
+// nodeExtensions returns the extensions of node and, for a $ref, those of
+// the value it refers to.
+func nodeExtensions(node any) []map[string]any {
+	var extensions []map[string]any
+	for _, v := range nodeStructs(node) {
+		if f := v.FieldByName("Extensions"); f.IsValid() {
+			if m, ok := f.Interface().(map[string]any); ok && len(m) != 0 {
+				extensions = append(extensions, m)
+			}
+		}
+	}
+	return extensions
+}
+
+// nodeOrigin returns the origin of node or, for a $ref without one, that of
+// the value it refers to.
+func nodeOrigin(node any) *openapi3.Origin {
+	for _, v := range nodeStructs(node) {
+		if f := v.FieldByName("Origin"); f.IsValid() {
+			if origin, ok := f.Interface().(*openapi3.Origin); ok && origin != nil {
+				return origin
+			}
+		}
+	}
+	return nil
+}
+
+// nodeStructs returns the struct node points to followed, for a $ref, by the
+// struct of the value it refers to.
+func nodeStructs(node any) []reflect.Value {
+	v := reflect.ValueOf(node)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	structs := []reflect.Value{v.Elem()}
+	if _, ok := v.Elem().Type().FieldByName("Ref"); ok {
+		if value := v.Elem().FieldByName("Value"); value.Kind() == reflect.Pointer && !value.IsNil() {
+			structs = append(structs, value.Elem())
+		}
+	}
+	return structs
+}
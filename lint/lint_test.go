@@ -0,0 +1,113 @@
+package lint_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/lint"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func loadDoc(t *testing.T) *openapi3.T {
+	t.Helper()
+	loader := openapi3.NewLoader()
+	loader.IncludeOrigin = true
+	doc, err := loader.LoadFromFile("testdata/openapi.yaml")
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(loader.Context))
+	return doc
+}
+
+func findingStrings(findings []lint.Finding) []string {
+	var s []string
+	for _, finding := range findings {
+		s = append(s, finding.String())
+	}
+	return s
+}
+
+func TestLint(t *testing.T) {
+	findings, err := lint.NewLinter(nil).Lint(loadDoc(t))
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		`testdata/openapi.yaml:32:5: warn: operationId "get_pet" is not in lowerCamelCase (operation-id-camel-case)`,
+		`testdata/openapi.yaml:32:5: warn: operation has no tags (operation-tags)`,
+		`testdata/openapi.yaml:32:5: warn: operation has no summary (operation-summary)`,
+		`testdata/openapi.yaml:24:9: warn: 400 response content is not application/problem+json (error-response-problem-json)`,
+		`testdata/openapi.yaml:72:11: warn: schema of application/json response content is inline (no-inline-response-schemas)`,
+		`testdata/openapi.yaml:39:15: warn: schema of application/json response content is inline (no-inline-response-schemas)`,
+		`testdata/openapi.yaml:7:5: warn: operation responding with a list has no offset query parameter (pagination-parameters)`,
+	}, findingStrings(findings))
+	require.Equal(t, "/paths/~1pets~1{petId}/get", findings[0].JSONPointer)
+	require.Equal(t, "/components/responses/BadRequest/content/application~1json/schema", findings[4].JSONPointer)
+}
+
+func TestLintConfig(t *testing.T) {
+	config, err := lint.LoadConfig("testdata/config.yaml")
+	require.NoError(t, err)
+	require.Equal(t, &lint.Config{Rules: map[string]lint.RuleConfig{
+		"operation-summary":     {Severity: lint.Off},
+		"path-kebab-case":       {Severity: lint.Error},
+		"pagination-parameters": {Severity: lint.Info, Options: map[string]any{"parameters": []any{"limit", "cursor"}}},
+	}}, config)
+
+	linter := lint.NewLinter(config)
+	linter.Rules = append(linter.Rules, lint.NewRule("info-description", "The API has a description.", lint.Hint, func(c *lint.Context) error {
+		if c.Doc.Info.Description == "" {
+			c.Report("/info", c.Doc.Info, "info has no description")
+		}
+		return nil
+	}))
+	findings, err := linter.Lint(loadDoc(t))
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		`testdata/openapi.yaml:32:5: warn: operationId "get_pet" is not in lowerCamelCase (operation-id-camel-case)`,
+		`testdata/openapi.yaml:32:5: warn: operation has no tags (operation-tags)`,
+		`testdata/openapi.yaml:24:9: warn: 400 response content is not application/problem+json (error-response-problem-json)`,
+		`testdata/openapi.yaml:72:11: warn: schema of application/json response content is inline (no-inline-response-schemas)`,
+		`testdata/openapi.yaml:39:15: warn: schema of application/json response content is inline (no-inline-response-schemas)`,
+		`testdata/openapi.yaml:7:5: info: operation responding with a list has no cursor query parameter (pagination-parameters)`,
+		`testdata/openapi.yaml:2:1: hint: info has no description (info-description)`,
+	}, findingStrings(findings))
+}
+
+func TestLintIgnore(t *testing.T) {
+	doc := loadDoc(t)
+	delete(doc.Paths.Value("/legacy_reports").Extensions, lint.IgnoreExtension)
+	findings, err := lint.NewLinter(nil).Lint(doc)
+	require.NoError(t, err)
+	require.Contains(t, findingStrings(findings), `testdata/openapi.yaml:49:5: warn: operation has no tags (operation-tags)`)
+	require.Contains(t, findingStrings(findings), `testdata/openapi.yaml:47:3: warn: path segment "legacy_reports" is not in kebab-case (path-kebab-case)`)
+}
+
+func TestLintErrors(t *testing.T) {
+	for _, test := range []struct {
+		config string
+		err    string
+	}{
+		{
+			config: `{rules: {no-such-rule: warn}}`,
+			err:    `unknown rule "no-such-rule"`,
+		},
+		{
+			config: `{rules: {pagination-parameters: {severity: warn, options: {parameters: limit}}}}`,
+			err:    `rule "pagination-parameters": invalid option "parameters": json: cannot unmarshal string into Go value of type []string`,
+		},
+	} {
+		t.Run(test.config, func(t *testing.T) {
+			config, err := lint.ParseConfig([]byte(test.config))
+			require.NoError(t, err)
+			_, err = lint.NewLinter(config).Lint(loadDoc(t))
+			require.EqualError(t, err, test.err)
+		})
+	}
+
+	for _, config := range []string{
+		`{rules: {operation-tags: fatal}}`,
+		`{rules: {operation-tags: {options: {}}}}`,
+	} {
+		_, err := lint.ParseConfig([]byte(config))
+		require.Error(t, err, config)
+	}
+}
@@ -0,0 +1,186 @@
+package lint
+
+import (
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// BuiltinRules returns the rules of the package, which are all warnings by
+// default:
+//
+//   - operation-id-camel-case: operations have an operationId in lowerCamelCase.
+//   - operation-tags: operations have at least one tag.
+//   - operation-summary: operations have a summary.
+//   - error-response-problem-json: 4xx responses describe their content as
+//     application/problem+json (RFC 9457), or as one of the media types of
+//     the mediaTypes option.
+//   - no-inline-response-schemas: the schemas of response contents are $refs
+//     to components, or arrays of them.
+//   - path-kebab-case: the static segments of paths are in kebab-case.
+//   - pagination-parameters: GET operations responding with a JSON array take
+//     the query parameters of the parameters option, limit and offset by
+//     default.
+func BuiltinRules() []Rule {
+	return []Rule{
+		NewRule("operation-id-camel-case", "Operations have an operationId in lowerCamelCase.", Warn, checkOperationIDCamelCase),
+		NewRule("operation-tags", "Operations have at least one tag.", Warn, checkOperationTags),
+		NewRule("operation-summary", "Operations have a summary.", Warn, checkOperationSummary),
+		NewRule("error-response-problem-json", "4xx responses describe their content as application/problem+json.", Warn, checkErrorResponseProblemJSON),
+		NewRule("no-inline-response-schemas", "The schemas of response contents are $refs to components, or arrays of them.", Warn, checkNoInlineResponseSchemas),
+		NewRule("path-kebab-case", "The static segments of paths are in kebab-case.", Warn, checkPathKebabCase),
+		NewRule("pagination-parameters", "GET operations responding with a JSON array take pagination query parameters.", Warn, checkPaginationParameters),
+	}
+}
+
+var (
+	camelCaseRegExp = regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`)
+	kebabCaseRegExp = regexp.MustCompile(`^[a-z0-9]+([-.][a-z0-9]+)*$`)
+)
+
+// walkOperations calls fn with the operations of doc, those of webhooks and
+// callbacks included, and the path items they belong to.
+func walkOperations(doc *openapi3.T, fn func(pointer string, op *openapi3.Operation, pathItem *openapi3.PathItem)) {
+	_ = openapi3.Walk(doc, openapi3.VisitorFunc(func(c *openapi3.WalkCursor) error {
+		if op, ok := c.Value.(*openapi3.Operation); ok {
+			fn(c.JSONPointer, op, c.Parent.Value.(*openapi3.PathItem))
+			return openapi3.SkipSubtree
+		}
+		return nil
+	}))
+}
+
+func checkOperationIDCamelCase(c *Context) error {
+	walkOperations(c.Doc, func(pointer string, op *openapi3.Operation, _ *openapi3.PathItem) {
+		switch {
+		case op.OperationID == "":
+			c.Report(pointer, op, "operation has no operationId")
+		case !camelCaseRegExp.MatchString(op.OperationID):
+			c.Report(pointer, op, "operationId %q is not in lowerCamelCase", op.OperationID)
+		}
+	})
+	return nil
+}
+
+func checkOperationTags(c *Context) error {
+	walkOperations(c.Doc, func(pointer string, op *openapi3.Operation, _ *openapi3.PathItem) {
+		if len(op.Tags) == 0 {
+			c.Report(pointer, op, "operation has no tags")
+		}
+	})
+	return nil
+}
+
+func checkOperationSummary(c *Context) error {
+	walkOperations(c.Doc, func(pointer string, op *openapi3.Operation, _ *openapi3.PathItem) {
+		if strings.TrimSpace(op.Summary) == "" {
+			c.Report(pointer, op, "operation has no summary")
+		}
+	})
+	return nil
+}
+
+func checkErrorResponseProblemJSON(c *Context) error {
+	mediaTypes := []string{"application/problem+json"}
+	if err := c.Option("mediaTypes", &mediaTypes); err != nil {
+		return err
+	}
+	walkOperations(c.Doc, func(pointer string, op *openapi3.Operation, _ *openapi3.PathItem) {
+		if op.Responses == nil {
+			return
+		}
+		for _, code := range op.Responses.Keys() {
+			response := op.Responses.Value(code)
+			if !strings.HasPrefix(code, "4") || response.Value == nil || len(response.Value.Content) == 0 {
+				continue
+			}
+			if !slices.ContainsFunc(mediaTypes, func(mediaType string) bool { return response.Value.Content[mediaType] != nil }) {
+				c.Report(pointer+"/responses/"+code, response, "%s response content is not %s", code, strings.Join(mediaTypes, " or "))
+			}
+		}
+	})
+	return nil
+}
+
+func checkNoInlineResponseSchemas(c *Context) error {
+	checked := make(map[*openapi3.Response]bool)
+	return openapi3.Walk(c.Doc, openapi3.VisitorFunc(func(cursor *openapi3.WalkCursor) error {
+		response, ok := cursor.Value.(*openapi3.ResponseRef)
+		if !ok || response.Value == nil || checked[response.Value] {
+			return nil
+		}
+		checked[response.Value] = true
+		for _, mediaType := range slices.Sorted(maps.Keys(response.Value.Content)) {
+			schema := response.Value.Content[mediaType].Schema
+			if schema == nil || schema.Ref != "" {
+				continue
+			}
+			if s := schema.Value; s != nil && s.Type.Is("array") && s.Items != nil && s.Items.Ref != "" {
+				continue
+			}
+			c.Report(cursor.JSONPointer+"/content/"+openapi3.EscapeJSONPointerToken(mediaType)+"/schema", schema, "schema of %s response content is inline", mediaType)
+		}
+		return openapi3.SkipSubtree
+	}))
+}
+
+func checkPathKebabCase(c *Context) error {
+	if c.Doc.Paths == nil {
+		return nil
+	}
+	for _, path := range c.Doc.Paths.Keys() {
+		for segment := range strings.SplitSeq(strings.Trim(path, "/"), "/") {
+			if segment == "" || strings.Contains(segment, "{") || kebabCaseRegExp.MatchString(segment) {
+				continue
+			}
+			c.Report("/paths/"+openapi3.EscapeJSONPointerToken(path), c.Doc.Paths.Value(path), "path segment %q is not in kebab-case", segment)
+		}
+	}
+	return nil
+}
+
+func checkPaginationParameters(c *Context) error {
+	parameters := []string{"limit", "offset"}
+	if err := c.Option("parameters", &parameters); err != nil {
+		return err
+	}
+	walkOperations(c.Doc, func(pointer string, op *openapi3.Operation, pathItem *openapi3.PathItem) {
+		if op != pathItem.Get || !respondsWithJSONArray(op) {
+			return
+		}
+		var missing []string
+		for _, name := range parameters {
+			if op.Parameters.GetByInAndName(openapi3.ParameterInQuery, name) == nil &&
+				pathItem.Parameters.GetByInAndName(openapi3.ParameterInQuery, name) == nil {
+				missing = append(missing, name)
+			}
+		}
+		if len(missing) != 0 {
+			c.Report(pointer, op, "operation responding with a list has no %s query parameter", strings.Join(missing, ", "))
+		}
+	})
+	return nil
+}
+
+// respondsWithJSONArray reports whether the 200 response of op is a JSON array.
+func respondsWithJSONArray(op *openapi3.Operation) bool {
+	if op.Responses == nil {
+		return false
+	}
+	response := op.Responses.Status(200)
+	if response == nil || response.Value == nil {
+		return false
+	}
+	for mediaType, content := range response.Value.Content {
+		if mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json") {
+			continue
+		}
+		if content.Schema != nil && content.Schema.Value != nil && content.Schema.Value.Type.Is("array") {
+			return true
+		}
+	}
+	return false
+}
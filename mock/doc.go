@@ -0,0 +1,21 @@
+// Package mock serves mock responses for the operations of an OpenAPI
+// document, so that clients can be developed and tested before the API they
+// call is implemented.
+//
+// A Handler routes requests to the operations of the document, validates
+// them with openapi3filter.ValidateRequest and answers with one of the
+// responses of their operation:
+//
+//	handler, err := mock.NewHandler(doc)
+//	if err != nil {
+//		return err
+//	}
+//	log.Fatal(http.ListenAndServe(":8080", handler))
+//
+// The response is the first 2xx one of the operation, or the one a
+// "Prefer: code=404" request header asks for. Its content is negotiated with
+// the Accept header of the request and rendered from the example a
+// "Prefer: example=notFound" header names, the examples of the media type, or
+// a value made up from its schema with Synthesize. Response headers are
+// rendered the same way.
+package mock
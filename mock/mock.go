@@ -0,0 +1,305 @@
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+// ValueFunc returns a value valid against schema, to render the contents and
+// headers of responses that have no example.
+type ValueFunc func(schema *openapi3.SchemaRef) any
+
+// Handler is an http.Handler that serves mock responses for the operations of
+// an OpenAPI document.
+type Handler struct {
+	router       routers.Router
+	options      openapi3filter.Options
+	errorEncoder openapi3filter.ErrorEncoder
+	values       ValueFunc
+}
+
+// Option configures a Handler.
+type Option func(*Handler)
+
+// WithRouter routes requests with router instead of a gorillamux router of the
+// document.
+func WithRouter(router routers.Router) Option {
+	return func(h *Handler) {
+		h.router = router
+	}
+}
+
+// ValidationOptions sets the options requests are validated with. Requests are
+// not authenticated unless options.AuthenticationFunc is set.
+func ValidationOptions(options openapi3filter.Options) Option {
+	return func(h *Handler) {
+		h.options = options
+	}
+}
+
+// OnErr sets how errors are written, such as invalid requests or requests no
+// response fits. It defaults to openapi3filter.DefaultErrorEncoder, with the
+// errors of openapi3filter converted by a ValidationErrorEncoder.
+func OnErr(encoder openapi3filter.ErrorEncoder) Option {
+	return func(h *Handler) {
+		h.errorEncoder = encoder
+	}
+}
+
+// Values sets how values are made up for schemas. It defaults to Synthesize,
+// which always makes up the same value for a schema: use an
+// openapi3.ValueGenerator for random ones.
+func Values(f ValueFunc) Option {
+	return func(h *Handler) {
+		h.values = f
+	}
+}
+
+// NewHandler returns a Handler serving mock responses for the operations of
+// doc. Unless WithRouter is given, the servers of doc are served from their
+// paths, whatever the host requests are sent to.
+func NewHandler(doc *openapi3.T, options ...Option) (*Handler, error) {
+	h := &Handler{
+		options: openapi3filter.Options{AuthenticationFunc: openapi3filter.NoopAuthenticationFunc},
+		values:  Synthesize,
+	}
+	h.errorEncoder = (&openapi3filter.ValidationErrorEncoder{Encoder: openapi3filter.DefaultErrorEncoder}).Encode
+	for _, option := range options {
+		option(h)
+	}
+	if h.router == nil {
+		router, err := gorillamux.NewLocalRouter(doc)
+		if err != nil {
+			return nil, err
+		}
+		h.router = router
+	}
+	return h, nil
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	route, pathParams, err := h.router.FindRoute(r)
+	if err != nil {
+		h.errorEncoder(ctx, err, w)
+		return
+	}
+	options := h.options
+	if err := openapi3filter.ValidateRequest(ctx, &openapi3filter.RequestValidationInput{
+		Request:    r,
+		PathParams: pathParams,
+		Route:      route,
+		Options:    &options,
+	}); err != nil {
+		h.errorEncoder(ctx, err, w)
+		return
+	}
+
+	prefer := preferences(r.Header.Values("Prefer"))
+	status, response, err := selectResponse(route.Operation, prefer["code"])
+	if err != nil {
+		h.errorEncoder(ctx, err, w)
+		return
+	}
+	var contentType string
+	var body []byte
+	if len(response.Content) != 0 {
+		if contentType, body, err = h.render(response.Content, r.Header.Values("Accept"), prefer["example"]); err != nil {
+			h.errorEncoder(ctx, err, w)
+			return
+		}
+	}
+
+	header := w.Header()
+	for _, name := range componentNames(response.Headers) {
+		if strings.EqualFold(name, "Content-Type") || response.Headers[name] == nil {
+			continue
+		}
+		if value, ok := h.headerValue(response.Headers[name].Value); ok {
+			header.Set(name, value)
+		}
+	}
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	w.WriteHeader(status)
+	if r.Method != http.MethodHead {
+		_, _ = w.Write(body)
+	}
+}
+
+// selectResponse returns the response of op for the status code, or the first
+// 2xx one when code is empty, and the status to send it with.
+func selectResponse(op *openapi3.Operation, code string) (int, *openapi3.Response, error) {
+	if op.Responses != nil {
+		if code != "" {
+			status, err := strconv.Atoi(code)
+			if err != nil || status < 100 || status > 599 {
+				return 0, nil, &openapi3filter.ValidationError{
+					Status: http.StatusBadRequest,
+					Title:  fmt.Sprintf("invalid preferred status code %q", code),
+				}
+			}
+			if response := op.Responses.Status(status); response != nil && response.Value != nil {
+				return status, response.Value, nil
+			}
+			if response := op.Responses.Default(); response != nil && response.Value != nil {
+				return status, response.Value, nil
+			}
+			return 0, nil, &openapi3filter.ValidationError{
+				Status: http.StatusNotImplemented,
+				Title:  fmt.Sprintf("operation has no response for status code %d", status),
+			}
+		}
+		keys := op.Responses.Keys()
+		slices.Sort(keys)
+		for _, key := range keys {
+			response := op.Responses.Value(key)
+			if !strings.HasPrefix(key, "2") || response.Value == nil {
+				continue
+			}
+			status, err := strconv.Atoi(key)
+			if err != nil {
+				status = http.StatusOK
+			}
+			return status, response.Value, nil
+		}
+		if response := op.Responses.Default(); response != nil && response.Value != nil {
+			return http.StatusOK, response.Value, nil
+		}
+	}
+	return 0, nil, &openapi3filter.ValidationError{
+		Status: http.StatusNotImplemented,
+		Title:  "operation has no success response",
+	}
+}
+
+// render returns the content type and body of content for the Accept headers
+// of the request, from the example named example if it is not empty.
+func (h *Handler) render(content openapi3.Content, accept []string, example string) (string, []byte, error) {
+	contentType, mediaType := negotiate(content, accept)
+	if mediaType == nil {
+		return "", nil, &openapi3filter.ValidationError{
+			Status: http.StatusNotAcceptable,
+			Title:  fmt.Sprintf("response content is not available as %s", strings.Join(accept, ", ")),
+		}
+	}
+
+	var value any
+	switch {
+	case example != "":
+		ref := mediaType.Examples[example]
+		if ref == nil || ref.Value == nil {
+			return "", nil, &openapi3filter.ValidationError{
+				Status: http.StatusBadRequest,
+				Title:  fmt.Sprintf("response content has no example %q", example),
+			}
+		}
+		if data, _ := ref.Value.ExternalValueData(); data != nil {
+			return contentType, data, nil
+		}
+		value = ref.Value.Value
+	case mediaType.Example != nil:
+		value = mediaType.Example
+	default:
+		for _, name := range componentNames(mediaType.Examples) {
+			if ref := mediaType.Examples[name]; ref != nil && ref.Value != nil && ref.Value.Value != nil {
+				value = ref.Value.Value
+				break
+			}
+		}
+		if value == nil {
+			// Values are only synthesized without examples
+			value = h.values(mediaType.Schema)
+		}
+	}
+
+	body, err := encode(value, contentType)
+	if err != nil {
+		return "", nil, &openapi3filter.ValidationError{
+			Status: http.StatusInternalServerError,
+			Title:  err.Error(),
+		}
+	}
+	return contentType, body, nil
+}
+
+// headerValue returns the value of a response header.
+func (h *Handler) headerValue(header *openapi3.Header) (string, bool) {
+	if header == nil {
+		return "", false
+	}
+	value := header.Example
+	if value == nil {
+		for _, name := range componentNames(header.Examples) {
+			if ref := header.Examples[name]; ref != nil && ref.Value != nil && ref.Value.Value != nil {
+				value = ref.Value.Value
+				break
+			}
+		}
+	}
+	if value == nil && header.Schema != nil {
+		value = h.values(header.Schema)
+	}
+	if value == nil {
+		return "", false
+	}
+	return formatHeaderValue(value), true
+}
+
+// formatHeaderValue formats value as a simple style header.
+func formatHeaderValue(value any) string {
+	switch value := value.(type) {
+	case []any:
+		values := make([]string, 0, len(value))
+		for _, v := range value {
+			values = append(values, formatHeaderValue(v))
+		}
+		return strings.Join(values, ",")
+	case map[string]any:
+		var values []string
+		for _, k := range componentNames(value) {
+			values = append(values, k, formatHeaderValue(value[k]))
+		}
+		return strings.Join(values, ",")
+	}
+	return fmt.Sprint(value)
+}
+
+// encode encodes value as contentType.
+func encode(value any, contentType string) ([]byte, error) {
+	mediaType := baseMediaType(contentType)
+	if mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") {
+		return json.Marshal(value)
+	}
+	if encoder := openapi3filter.RegisteredBodyEncoder(mediaType); encoder != nil {
+		return encoder(value)
+	}
+	switch value := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []byte(value), nil
+	case []byte:
+		return value, nil
+	}
+	if strings.HasPrefix(mediaType, "text/") {
+		return []byte(fmt.Sprint(value)), nil
+	}
+	return nil, fmt.Errorf("cannot encode response content as %q", contentType)
+}
+
+func componentNames[V any](m map[string]V) []string {
+	return slices.Sorted(maps.Keys(m))
+}
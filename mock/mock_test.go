@@ -0,0 +1,196 @@
+package mock_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/mock"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func newHandler(t *testing.T, options ...mock.Option) http.Handler {
+	t.Helper()
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromFile("testdata/petstore.yaml")
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(loader.Context))
+	handler, err := mock.NewHandler(doc, options...)
+	require.NoError(t, err)
+	return handler
+}
+
+func TestHandler(t *testing.T) {
+	handler := newHandler(t)
+
+	for _, test := range []struct {
+		name   string
+		method string
+		target string
+		header http.Header
+		body   string
+
+		status      int
+		contentType string
+		headers     map[string]string
+		response    string
+	}{
+		{
+			name:        "synthesized",
+			method:      http.MethodGet,
+			target:      "/v1/pets",
+			status:      http.StatusOK,
+			contentType: "application/json",
+			headers:     map[string]string{"X-Total-Count": "381", "X-Rate-Limit": "100"},
+			response:    `[{"id":4805392197461428224,"name":"U3xppbUEJ","tag":"dog"},{"id":9070369528646784000,"name":"sG2L5nbPXkl2V3","tag":"dog"},{"id":9223372036854775807,"name":"QBwwnJ7"}]`,
+		},
+		{
+			name:        "accept",
+			method:      http.MethodGet,
+			target:      "/v1/pets?limit=10",
+			header:      http.Header{"Accept": {"application/xml;q=0.9, text/*"}},
+			status:      http.StatusOK,
+			contentType: "text/csv",
+			response:    "id,name\n1,Tom\n",
+		},
+		{
+			name:   "not acceptable",
+			method: http.MethodGet,
+			target: "/v1/pets",
+			header: http.Header{"Accept": {"application/xml"}},
+			status: http.StatusNotAcceptable,
+		},
+		{
+			name:        "first example",
+			method:      http.MethodPost,
+			target:      "/v1/pets",
+			header:      http.Header{"Content-Type": {"application/json"}},
+			body:        `{"id":3,"name":"Felix"}`,
+			status:      http.StatusCreated,
+			contentType: "application/json",
+			response:    `{"id":2,"name":"Rex"}`,
+		},
+		{
+			name:        "preferred example",
+			method:      http.MethodPost,
+			target:      "/v1/pets",
+			header:      http.Header{"Content-Type": {"application/json"}, "Prefer": {`example="tom"`}},
+			body:        `{"id":3,"name":"Felix"}`,
+			status:      http.StatusCreated,
+			contentType: "application/json",
+			response:    `{"id":1,"name":"Tom","tag":"cat"}`,
+		},
+		{
+			name:        "preferred code",
+			method:      http.MethodPost,
+			target:      "/v1/pets",
+			header:      http.Header{"Content-Type": {"application/json"}, "Prefer": {"code=422"}},
+			body:        `{"id":3,"name":"Felix"}`,
+			status:      http.StatusUnprocessableEntity,
+			contentType: "application/problem+json",
+			response:    `{"status":422,"title":"Invalid pet"}`,
+		},
+		{
+			name:        "default",
+			method:      http.MethodPost,
+			target:      "/v1/pets",
+			header:      http.Header{"Content-Type": {"application/json"}, "Prefer": {"code=503, respond-async"}},
+			body:        `{"id":3,"name":"Felix"}`,
+			status:      http.StatusServiceUnavailable,
+			contentType: "application/json",
+			response:    `{"code":-112}`,
+		},
+		{
+			name:   "unknown example",
+			method: http.MethodPost,
+			target: "/v1/pets",
+			header: http.Header{"Content-Type": {"application/json"}, "Prefer": {"example=felix"}},
+			body:   `{"id":3,"name":"Felix"}`,
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "invalid request",
+			method: http.MethodPost,
+			target: "/v1/pets",
+			header: http.Header{"Content-Type": {"application/json"}},
+			body:   `{"id":3}`,
+			status: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "no content",
+			method: http.MethodDelete,
+			target: "/v1/pets/1",
+			status: http.StatusNoContent,
+		},
+		{
+			name:   "no response",
+			method: http.MethodDelete,
+			target: "/v1/pets/1",
+			header: http.Header{"Prefer": {"code=404"}},
+			status: http.StatusNotImplemented,
+		},
+		{
+			name:   "not found",
+			method: http.MethodGet,
+			target: "/v1/owners",
+			status: http.StatusNotFound,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, test.target, strings.NewReader(test.body))
+			for k, v := range test.header {
+				req.Header[k] = v
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			require.Equal(t, test.status, rec.Code, rec.Body.String())
+			if test.status >= http.StatusBadRequest && test.contentType == "" {
+				return
+			}
+			require.Equal(t, test.contentType, rec.Header().Get("Content-Type"))
+			for k, v := range test.headers {
+				require.Equal(t, v, rec.Header().Get(k), k)
+			}
+			body, err := io.ReadAll(rec.Body)
+			require.NoError(t, err)
+			if strings.HasSuffix(test.contentType, "json") {
+				require.JSONEq(t, test.response, string(body))
+			} else {
+				require.Equal(t, test.response, string(body))
+			}
+		})
+	}
+}
+
+func TestHandlerValues(t *testing.T) {
+	handler := newHandler(t, mock.Values(func(schema *openapi3.SchemaRef) any {
+		if schema.Value.Type.Is("integer") {
+			return 42
+		}
+		return mock.Synthesize(schema)
+	}))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/pets", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "42", rec.Header().Get("X-Total-Count"))
+}
+
+func TestHandlerValuesOnlyWithoutExamples(t *testing.T) {
+	var synthesized []*openapi3.SchemaRef
+	handler := newHandler(t, mock.Values(func(schema *openapi3.SchemaRef) any {
+		synthesized = append(synthesized, schema)
+		return mock.Synthesize(schema)
+	}))
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/v1/pets", strings.NewReader(`{"id":3,"name":"Felix"}`))
+	req.Header.Set("Content-Type", "application/json")
+	handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	require.JSONEq(t, `{"id":2,"name":"Rex"}`, rec.Body.String())
+	require.Empty(t, synthesized)
+}
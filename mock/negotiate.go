@@ -0,0 +1,127 @@
+package mock
+
+import (
+	"mime"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// preferences returns the preferences of Prefer headers (RFC 7240), such as
+// code=404 or example="notFound", by name.
+func preferences(headers []string) map[string]string {
+	prefer := make(map[string]string)
+	for _, header := range headers {
+		for preference := range strings.SplitSeq(header, ",") {
+			for token := range strings.SplitSeq(preference, ";") {
+				name, value, _ := strings.Cut(token, "=")
+				name = strings.ToLower(strings.TrimSpace(name))
+				if name == "" {
+					continue
+				}
+				if _, ok := prefer[name]; !ok {
+					prefer[name] = strings.Trim(strings.TrimSpace(value), `"`)
+				}
+			}
+		}
+	}
+	return prefer
+}
+
+// mediaRange is a media range of an Accept header.
+type mediaRange struct {
+	mediaType string
+	q         float64
+}
+
+// parseAccept returns the media ranges of Accept headers, the preferred first.
+func parseAccept(headers []string) []mediaRange {
+	var ranges []mediaRange
+	for _, header := range headers {
+		for accepted := range strings.SplitSeq(header, ",") {
+			mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+			if err != nil {
+				continue
+			}
+			q := 1.0
+			if v, ok := params["q"]; ok {
+				if q, err = strconv.ParseFloat(v, 64); err != nil {
+					continue
+				}
+			}
+			if q > 0 {
+				ranges = append(ranges, mediaRange{mediaType: mediaType, q: q})
+			}
+		}
+	}
+	slices.SortStableFunc(ranges, func(a, b mediaRange) int {
+		switch {
+		case a.q > b.q:
+			return -1
+		case a.q < b.q:
+			return 1
+		}
+		return 0
+	})
+	return ranges
+}
+
+// negotiate returns the content type of the media type of content that best
+// fits the Accept headers of a request, or nil if none does. Without Accept
+// headers JSON is preferred.
+func negotiate(content openapi3.Content, accept []string) (string, *openapi3.MediaType) {
+	names := componentNames(content)
+	if len(accept) == 0 {
+		for _, name := range names {
+			if mediaType := baseMediaType(name); mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") {
+				return concreteMediaType(name, "*/*"), content[name]
+			}
+		}
+		return concreteMediaType(names[0], "*/*"), content[names[0]]
+	}
+	for _, r := range parseAccept(accept) {
+		for _, name := range names {
+			if mediaTypeMatches(baseMediaType(name), r.mediaType) {
+				return concreteMediaType(name, r.mediaType), content[name]
+			}
+		}
+	}
+	return "", nil
+}
+
+// mediaTypeMatches reports whether two media types, which may be ranges such
+// as text/*, overlap.
+func mediaTypeMatches(a, b string) bool {
+	aType, aSubtype, _ := strings.Cut(a, "/")
+	bType, bSubtype, _ := strings.Cut(b, "/")
+	return (aType == "*" || bType == "*" || aType == bType) &&
+		(aSubtype == "*" || bSubtype == "*" || aSubtype == bSubtype)
+}
+
+// concreteMediaType returns the content type to respond with for the content
+// name of a response accepted by the media range accepted.
+func concreteMediaType(name, accepted string) string {
+	if !strings.Contains(name, "*") {
+		return name
+	}
+	if !strings.Contains(accepted, "*") {
+		return accepted
+	}
+	switch baseMediaType(name) {
+	case "*/*", "application/*":
+		return "application/json"
+	case "text/*":
+		return "text/plain"
+	}
+	return "application/octet-stream"
+}
+
+// baseMediaType returns mediaType without its parameters.
+func baseMediaType(mediaType string) string {
+	if i := strings.IndexByte(mediaType, ';'); i >= 0 {
+		mediaType = mediaType[:i]
+	}
+	return strings.ToLower(strings.TrimSpace(mediaType))
+}
@@ -0,0 +1,25 @@
+package mock
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// synthesizeSeed is the seed of the values Synthesize makes up.
+const synthesizeSeed = 0
+
+// Synthesize returns a value valid against schema, made up by an
+// openapi3.ValueGenerator of a fixed seed so that the same schema always gives
+// the same value. Schemas with an example, examples or a default give it,
+// writeOnly properties are left out, and nil is returned when no valid value
+// can be made up.
+func Synthesize(schema *openapi3.SchemaRef) any {
+	if schema == nil || schema.Value == nil {
+		return nil
+	}
+	g := openapi3.NewValueGenerator(synthesizeSeed, openapi3.GenerateAsResponse(), openapi3.GenerateExamples())
+	value, err := g.Generate(schema.Value)
+	if err != nil {
+		return nil
+	}
+	return value
+}
@@ -0,0 +1,83 @@
+package mock_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/mock"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestSynthesize(t *testing.T) {
+	for _, test := range []struct {
+		schema string
+		value  any
+	}{
+		{`{"type": "string", "enum": ["b", "a"]}`, nil},
+		{`{"type": "string", "default": "d", "example": "e"}`, "e"},
+		{`{"type": "string", "default": "d"}`, "d"},
+		{`{"type": "string", "format": "date-time"}`, nil},
+		{`{"type": "string", "minLength": 8, "maxLength": 10}`, nil},
+		{`{"type": "integer", "minimum": 3, "exclusiveMinimum": true}`, nil},
+		{`{"type": "integer", "minimum": 7, "multipleOf": 5}`, nil},
+		{`{"type": "number", "minimum": 0, "maximum": 0.5, "exclusiveMinimum": true}`, nil},
+		{`{"type": ["null", "boolean"]}`, nil},
+		{`{"type": "array", "minItems": 2, "items": {"type": "integer"}}`, nil},
+		{`{"oneOf": [{"type": "boolean"}, {"type": "string"}]}`, nil},
+		{
+			`{"allOf": [{"type": "object", "required": ["a"], "properties": {"a": {"type": "string", "example": "x"}}}, {"type": "object", "required": ["b"], "properties": {"b": {"type": "integer", "const": 1}}}], "required": ["c"], "properties": {"c": {"type": "boolean", "default": true}}}`,
+			map[string]any{"a": "x", "b": 1.0, "c": true},
+		},
+		{`{"type": "object", "required": ["secret"], "properties": {"secret": {"type": "string", "writeOnly": true}}}`, map[string]any{}},
+	} {
+		t.Run(test.schema, func(t *testing.T) {
+			var schema openapi3.Schema
+			require.NoError(t, schema.UnmarshalJSON([]byte(test.schema)))
+			value := mock.Synthesize(openapi3.NewSchemaRef("", &schema))
+			require.NoError(t, schema.VisitJSON(value, openapi3.VisitAsResponse()))
+			if test.value != nil {
+				require.Equal(t, test.value, value)
+			}
+			// The same schema always gives the same value
+			require.Equal(t, value, mock.Synthesize(openapi3.NewSchemaRef("", &schema)))
+		})
+	}
+
+	var schema openapi3.Schema
+	require.NoError(t, schema.UnmarshalJSON([]byte(`{"type": "integer", "minimum": 5, "maximum": 4}`)))
+	require.Nil(t, mock.Synthesize(openapi3.NewSchemaRef("", &schema)))
+}
+
+func TestSynthesizeRecursive(t *testing.T) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData([]byte(`
+openapi: 3.0.3
+info: {title: Tree, version: 1.0.0}
+paths: {}
+components:
+  schemas:
+    Node:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string}
+        children:
+          type: array
+          items: {$ref: "#/components/schemas/Node"}
+        parent: {$ref: "#/components/schemas/Node"}
+    Loop:
+      type: object
+      required: [next]
+      properties:
+        next: {$ref: "#/components/schemas/Loop"}
+`))
+	require.NoError(t, err)
+	schema := doc.Components.Schemas["Node"]
+	value := mock.Synthesize(schema)
+	require.Contains(t, value, "name")
+	require.NoError(t, schema.Value.VisitJSON(value))
+
+	// No value ends a property that requires itself
+	require.Nil(t, mock.Synthesize(doc.Components.Schemas["Loop"]))
+}
@@ -0,0 +1,152 @@
+package openapi2conv_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi2conv"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestFromV31(t *testing.T) {
+	spec := []byte(`
+openapi: 3.1.0
+info:
+  title: Pets
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      responses:
+        '200':
+          description: Pets
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Pet'
+webhooks:
+  newPet:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+      responses:
+        '200':
+          description: Acknowledged
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name:
+          type: [string, "null"]
+          examples: [Rex, Felix]
+        age:
+          type: integer
+          minimum: 1
+          exclusiveMinimum: 0
+          exclusiveMaximum: 30
+        kind:
+          const: pet
+        color:
+          $ref: '#/components/schemas/Pet/$defs/Color'
+      $defs:
+        Color:
+          type: string
+          enum: [black, white]
+`)
+	loader := openapi3.NewLoader()
+	doc3, err := loader.LoadFromData(spec)
+	require.NoError(t, err)
+	require.NoError(t, doc3.Validate(context.Background()))
+
+	var losses []openapi2conv.Loss
+	doc2, err := openapi2conv.FromV3(doc3, openapi2conv.WithLosses(&losses))
+	require.NoError(t, err)
+
+	pet := doc2.Definitions["Pet"].Value
+
+	name := pet.Properties["name"].Value
+	require.Equal(t, &openapi3.Types{"string"}, name.Type)
+	require.Equal(t, true, name.Extensions["x-nullable"])
+	require.Equal(t, "Rex", name.Example)
+	require.Equal(t, []any{"Rex", "Felix"}, name.Extensions["x-examples"])
+
+	// The stricter of minimum and exclusiveMinimum applies
+	age := pet.Properties["age"].Value
+	require.Equal(t, 1.0, *age.Min)
+	require.False(t, age.ExclusiveMin)
+	require.Equal(t, 30.0, *age.Max)
+	require.True(t, age.ExclusiveMax)
+
+	require.Equal(t, []any{"pet"}, pet.Properties["kind"].Value.Enum)
+
+	require.Equal(t, "#/definitions/Pet.Color", pet.Properties["color"].Ref)
+	require.Equal(t, []any{"black", "white"}, doc2.Definitions["Pet.Color"].Value.Enum)
+
+	data, err := json.Marshal(doc2.Extensions["x-webhooks"])
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+  "newPet": {
+    "post": {
+      "parameters": [{"in": "body", "name": "body", "schema": {"$ref": "#/definitions/Pet"}}],
+      "consumes": ["application/json"],
+      "responses": {"200": {"description": "Acknowledged"}}
+    }
+  }
+}`, string(data))
+
+	require.Equal(t, []openapi2conv.Loss{
+		{Pointer: "/webhooks/newPet", Kind: openapi2conv.LossWebhooks, Action: "converted to the x-webhooks extension"},
+	}, losses)
+}
+
+func TestFromV32ItemSchema(t *testing.T) {
+	spec := []byte(`
+openapi: 3.2.0
+info:
+  title: Events
+  version: 1.0.0
+paths:
+  /events:
+    get:
+      responses:
+        '200':
+          description: Events
+          content:
+            application/json:
+              itemSchema:
+                type: string
+components: {}
+`)
+	loader := openapi3.NewLoader()
+	doc3, err := loader.LoadFromData(spec)
+	require.NoError(t, err)
+
+	doc2, err := openapi2conv.FromV3(doc3)
+	require.NoError(t, err)
+	schema := doc2.Paths["/events"].Get.Responses["200"].Schema.Value
+	require.Equal(t, &openapi3.Types{"array"}, schema.Type)
+	require.Equal(t, &openapi3.Types{"string"}, schema.Items.Value.Type)
+}
+
+func TestToV3SchemaRefNullType(t *testing.T) {
+	schema := openapi2conv.ToV3SchemaRef(&openapi2.SchemaRef{Value: &openapi2.Schema{
+		Type: &openapi3.Types{"string", "null"},
+	}})
+	require.Equal(t, &openapi3.Types{"string"}, schema.Value.Type)
+	require.True(t, schema.Value.Nullable)
+
+	schema = openapi2conv.ToV3SchemaRef(&openapi2.SchemaRef{Value: &openapi2.Schema{
+		Type: &openapi3.Types{"string"},
+	}})
+	require.False(t, schema.Value.Nullable)
+}
@@ -0,0 +1,217 @@
+package openapi2conv
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Option configures a conversion. See WithLosses and WithTargetVersion.
+type Option func(*options)
+
+type options struct {
+	losses        *[]Loss
+	targetVersion string
+
+	// fromV3Only and toV3Only name the options given that only apply to
+	// one direction, so that the other one rejects them
+	fromV3Only, toV3Only []string
+}
+
+// WithLosses makes FromV3 store into *losses the constructs of the OpenAPI 3 document
+// that could not be represented in OpenAPI 2, ordered by JSON pointer.
+// ToV3 rejects it.
+func WithLosses(losses *[]Loss) Option {
+	return func(o *options) {
+		o.losses = losses
+		o.fromV3Only = append(o.fromV3Only, "WithLosses")
+	}
+}
+
+// LossKind identifies the kind of construct lost when converting a document to OpenAPI 2.
+type LossKind string
+
+const (
+	// LossSchemaKeyword is a schema keyword that OpenAPI 2 schemas do not support.
+	LossSchemaKeyword LossKind = "schemaKeyword"
+	// LossOAuthFlows is an OAuth2 flow beyond the single one an OpenAPI 2 security scheme describes.
+	LossOAuthFlows LossKind = "oauthFlows"
+	// LossSecurityScheme is a security scheme type without an OpenAPI 2 equivalent.
+	LossSecurityScheme LossKind = "securityScheme"
+	// LossCookieParameter is a parameter located in a cookie.
+	LossCookieParameter LossKind = "cookieParameter"
+	// LossParameterSerialization is a parameter style without an OpenAPI 2 collection format.
+	LossParameterSerialization LossKind = "parameterSerialization"
+	// LossParameterContent is a parameter described by a media type rather than a schema.
+	LossParameterContent LossKind = "parameterContent"
+	// LossExamples is an example of a parameter or media type.
+	LossExamples LossKind = "examples"
+	// LossCallbacks is a callback of an operation.
+	LossCallbacks LossKind = "callbacks"
+	// LossLinks is a link of a response.
+	LossLinks LossKind = "links"
+	// LossServers is a server or server variable beyond the host and base path of the first server.
+	LossServers LossKind = "servers"
+	// LossMediaTypes is a request or response media type beyond the single schema OpenAPI 2 allows.
+	LossMediaTypes LossKind = "mediaTypes"
+	// LossOperation is an operation with an HTTP method OpenAPI 2 does not support.
+	LossOperation LossKind = "operation"
+	// LossComponents is a reusable component of a type OpenAPI 2 does not support.
+	LossComponents LossKind = "components"
+	// LossWebhooks is a webhook.
+	LossWebhooks LossKind = "webhooks"
+)
+
+// Loss describes a construct of an OpenAPI 3 document that could not be represented in OpenAPI 2.
+type Loss struct {
+	// Pointer is the JSON pointer to the construct within the OpenAPI 3 document.
+	Pointer string `json:"pointer" yaml:"pointer"`
+	// Kind is the kind of construct.
+	Kind LossKind `json:"kind" yaml:"kind"`
+	// Action describes what was done instead of converting the construct.
+	Action string `json:"action" yaml:"action"`
+}
+
+// droppedSchemaKeywords returns the keywords of schema FromV3SchemaRef does not convert.
+func droppedSchemaKeywords(schema *openapi3.Schema) []string {
+	var keywords []string
+	add := func(keyword string, present bool) {
+		if present {
+			keywords = append(keywords, keyword)
+		}
+	}
+	add("oneOf", len(schema.OneOf) != 0)
+	add("anyOf", len(schema.AnyOf) != 0)
+	add("not", schema.Not != nil)
+	add("discriminator", schema.Discriminator != nil)
+	// const is converted to a single value enum
+	add("const", schema.Const != nil && len(schema.Enum) != 0)
+	add("prefixItems", len(schema.PrefixItems) != 0)
+	add("contains", schema.Contains != nil)
+	add("minContains", schema.MinContains != nil)
+	add("maxContains", schema.MaxContains != nil)
+	add("patternProperties", len(schema.PatternProperties) != 0)
+	add("dependentSchemas", len(schema.DependentSchemas) != 0)
+	add("dependentRequired", len(schema.DependentRequired) != 0)
+	add("propertyNames", schema.PropertyNames != nil)
+	add("unevaluatedItems", schema.UnevaluatedItems.Has != nil || schema.UnevaluatedItems.Schema != nil)
+	add("unevaluatedProperties", schema.UnevaluatedProperties.Has != nil || schema.UnevaluatedProperties.Schema != nil)
+	add("if", schema.If != nil)
+	add("then", schema.Then != nil)
+	add("else", schema.Else != nil)
+	add("$dynamicRef", schema.DynamicRef != "")
+	add("contentSchema", schema.ContentSchema != nil)
+	return keywords
+}
+
+// lossRecorder records the constructs dropped or approximated by the conversion
+// functions of FromV3, at the JSON pointer they are given. A nil recorder records
+// nothing, as when the exported conversion functions are used on their own.
+type lossRecorder struct {
+	losses  []Loss
+	visited map[*openapi3.Schema]struct{}
+}
+
+func newLossRecorder() *lossRecorder {
+	return &lossRecorder{visited: make(map[*openapi3.Schema]struct{})}
+}
+
+func (r *lossRecorder) add(pointer string, kind LossKind, action string) {
+	if r == nil {
+		return
+	}
+	r.losses = append(r.losses, Loss{Pointer: pointer, Kind: kind, Action: action})
+}
+
+// sorted returns the recorded losses ordered by JSON pointer.
+func (r *lossRecorder) sorted() []Loss {
+	slices.SortFunc(r.losses, func(a, b Loss) int {
+		return cmp.Or(strings.Compare(a.Pointer, b.Pointer), strings.Compare(string(a.Kind), string(b.Kind)))
+	})
+	return r.losses
+}
+
+// pointerTo appends escaped reference tokens to a JSON pointer.
+func pointerTo(pointer string, tokens ...string) string {
+	for _, token := range tokens {
+		pointer += "/" + openapi3.EscapeJSONPointerToken(token)
+	}
+	return pointer
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	return slices.Sorted(maps.Keys(m))
+}
+
+// schema records the keywords of schema that are not converted, once per schema.
+func (r *lossRecorder) schema(pointer string, schema *openapi3.Schema) {
+	if r == nil {
+		return
+	}
+	if _, ok := r.visited[schema]; ok {
+		return
+	}
+	r.visited[schema] = struct{}{}
+
+	for _, keyword := range droppedSchemaKeywords(schema) {
+		r.add(pointerTo(pointer, keyword), LossSchemaKeyword, "dropped")
+	}
+	if len(schema.Defs) != 0 && !isComponentSchemaPointer(pointer) {
+		r.add(pointerTo(pointer, "$defs"), LossSchemaKeyword, "dropped, only the $defs of component schemas are hoisted into definitions")
+	}
+	if types := fromV3Types(schema.Type); types != nil && len(*types) > 1 {
+		r.add(pointerTo(pointer, "type"), LossSchemaKeyword, "kept as a list of types, which OpenAPI 2 does not define")
+	}
+}
+
+func isComponentSchemaPointer(pointer string) bool {
+	name, ok := strings.CutPrefix(pointer, "/components/schemas/")
+	return ok && !strings.Contains(name, "/")
+}
+
+func (r *lossRecorder) examples(pointer string, example any, examples openapi3.Examples) {
+	if example != nil {
+		r.add(pointerTo(pointer, "example"), LossExamples, "dropped")
+	}
+	if len(examples) != 0 {
+		r.add(pointerTo(pointer, "examples"), LossExamples, "dropped")
+	}
+}
+
+// droppedServers records the servers of a path item or operation,
+// which OpenAPI 2 does not support.
+func (r *lossRecorder) droppedServers(pointer string, servers openapi3.Servers) {
+	for i := range servers {
+		r.add(pointerTo(pointer, strconv.Itoa(i)), LossServers, "dropped, the host and base path of the document apply")
+	}
+}
+
+// droppedNames records every entry of a map as dropped.
+func droppedNames[V any](r *lossRecorder, pointer string, kind LossKind, m map[string]V) {
+	for name := range m {
+		r.add(pointerTo(pointer, name), kind, "dropped")
+	}
+}
+
+// csvSerialized reports whether arrays are serialized by parameter as OpenAPI 2 csv collections.
+func csvSerialized(parameter *openapi3.Parameter) bool {
+	sm, err := parameter.SerializationMethod()
+	if err != nil {
+		return true
+	}
+	switch sm.Style {
+	case openapi3.SerializationSimple:
+		return true
+	case openapi3.SerializationForm:
+		return !sm.Explode
+	}
+	return false
+}
+
+func isFormContentType(contentType string) bool {
+	return contentType == "application/x-www-form-urlencoded" || contentType == "multipart/form-data"
+}
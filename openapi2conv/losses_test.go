@@ -0,0 +1,217 @@
+package openapi2conv_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi2conv"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestFromV3WithLosses(t *testing.T) {
+	spec := []byte(`
+openapi: 3.0.3
+info:
+  title: Pets
+  version: 1.0.0
+servers:
+- url: https://{region}.example.com/v1
+  variables:
+    region:
+      default: eu
+- url: http://backup.example.com/v1
+paths:
+  /pets/{id}:
+    parameters:
+    - name: id
+      in: path
+      required: true
+      schema:
+        type: string
+    get:
+      parameters:
+      - name: session
+        in: cookie
+        schema:
+          type: string
+      - name: tags
+        in: query
+        schema:
+          type: array
+          items:
+            type: string
+      responses:
+        '200':
+          description: A pet
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+            application/xml:
+              schema:
+                $ref: '#/components/schemas/Pet'
+          links:
+            owner:
+              operationId: getOwner
+    put:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+          text/plain:
+            schema:
+              type: string
+      callbacks:
+        updated:
+          '{$request.body#/callback}':
+            post:
+              responses:
+                '204':
+                  description: Acknowledged
+      responses:
+        '204':
+          description: Updated
+    trace:
+      responses:
+        '200':
+          description: Traced
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        kind:
+          oneOf:
+          - $ref: '#/components/schemas/Cat'
+          - $ref: '#/components/schemas/Dog'
+          discriminator:
+            propertyName: type
+        name:
+          type: string
+          not:
+            enum: [""]
+    Cat:
+      type: object
+    Dog:
+      type: object
+  securitySchemes:
+    oauth:
+      type: oauth2
+      flows:
+        implicit:
+          authorizationUrl: https://example.com/authorize
+          scopes: {}
+        clientCredentials:
+          tokenUrl: https://example.com/token
+          scopes: {}
+    bearer:
+      type: http
+      scheme: bearer
+`)
+	loader := openapi3.NewLoader()
+	doc3, err := loader.LoadFromData(spec)
+	require.NoError(t, err)
+
+	var losses []openapi2conv.Loss
+	doc2, err := openapi2conv.FromV3(doc3, openapi2conv.WithLosses(&losses))
+	require.NoError(t, err)
+	require.Empty(t, doc2.Paths["/pets/{id}"].Get.Parameters[1].CollectionFormat)
+
+	require.Equal(t, []openapi2conv.Loss{
+		{Pointer: "/components/schemas/Pet/properties/kind/discriminator", Kind: openapi2conv.LossSchemaKeyword, Action: "dropped"},
+		{Pointer: "/components/schemas/Pet/properties/kind/oneOf", Kind: openapi2conv.LossSchemaKeyword, Action: "dropped"},
+		{Pointer: "/components/schemas/Pet/properties/name/not", Kind: openapi2conv.LossSchemaKeyword, Action: "dropped"},
+		{Pointer: "/components/securitySchemes/bearer/scheme", Kind: openapi2conv.LossSecurityScheme, Action: "converted to an apiKey scheme for the Authorization header"},
+		{Pointer: "/components/securitySchemes/oauth/flows/clientCredentials", Kind: openapi2conv.LossOAuthFlows, Action: "dropped, only the implicit flow is converted"},
+		{Pointer: "/paths/~1pets~1{id}/get/parameters/0/in", Kind: openapi2conv.LossCookieParameter, Action: `kept with "in": "cookie", which OpenAPI 2 does not define`},
+		{Pointer: "/paths/~1pets~1{id}/get/parameters/1", Kind: openapi2conv.LossParameterSerialization, Action: "converted without a collectionFormat, which defaults to csv"},
+		{Pointer: "/paths/~1pets~1{id}/get/responses/200/content/application~1xml", Kind: openapi2conv.LossMediaTypes, Action: "dropped, only the application/json schema is converted"},
+		{Pointer: "/paths/~1pets~1{id}/get/responses/200/links/owner", Kind: openapi2conv.LossLinks, Action: "dropped"},
+		{Pointer: "/paths/~1pets~1{id}/put/callbacks/updated", Kind: openapi2conv.LossCallbacks, Action: "dropped"},
+		{Pointer: "/paths/~1pets~1{id}/put/requestBody/content/text~1plain", Kind: openapi2conv.LossMediaTypes, Action: "only listed in consumes, the parameters are those of application/json"},
+		{Pointer: "/paths/~1pets~1{id}/trace", Kind: openapi2conv.LossOperation, Action: "dropped"},
+		{Pointer: "/servers/0/variables/region", Kind: openapi2conv.LossServers, Action: "dropped, the variable is left unexpanded in the host and base path"},
+		{Pointer: "/servers/1", Kind: openapi2conv.LossServers, Action: "only its scheme is kept, the host and base path are those of the first server"},
+	}, losses)
+}
+
+func TestFromV3WithLossesOfWebhooks(t *testing.T) {
+	spec := []byte(`
+openapi: 3.1.0
+info:
+  title: Pets
+  version: 1.0.0
+paths: {}
+webhooks:
+  newPet:
+    servers:
+    - url: https://hooks.example.com
+    post:
+      responses:
+        '200':
+          description: Received
+    connect:
+      responses:
+        '200':
+          description: Connected
+components:
+  securitySchemes:
+    oauth:
+      type: oauth2
+      flows:
+        password:
+          tokenUrl: https://example.com/token
+          scopes: {}
+        authorizationCode:
+          authorizationUrl: https://example.com/authorize
+          tokenUrl: https://example.com/token
+          scopes: {}
+`)
+	loader := openapi3.NewLoader()
+	doc3, err := loader.LoadFromData(spec)
+	require.NoError(t, err)
+
+	var losses []openapi2conv.Loss
+	doc2, err := openapi2conv.FromV3(doc3, openapi2conv.WithLosses(&losses))
+	require.NoError(t, err)
+	require.Equal(t, "accessCode", doc2.SecurityDefinitions["oauth"].Flow)
+
+	require.Equal(t, []openapi2conv.Loss{
+		{Pointer: "/components/securitySchemes/oauth/flows/password", Kind: openapi2conv.LossOAuthFlows, Action: "dropped, only the authorizationCode flow is converted"},
+		{Pointer: "/webhooks/newPet", Kind: openapi2conv.LossWebhooks, Action: "converted to the x-webhooks extension"},
+		{Pointer: "/webhooks/newPet/connect", Kind: openapi2conv.LossOperation, Action: "dropped"},
+		{Pointer: "/webhooks/newPet/servers/0", Kind: openapi2conv.LossServers, Action: "dropped, the host and base path of the document apply"},
+	}, losses)
+}
+
+func TestFromV3WithoutLosses(t *testing.T) {
+	spec := []byte(`
+openapi: 3.0.3
+info:
+  title: Pets
+  version: 1.0.0
+paths:
+  /pets:
+    get:
+      responses:
+        '200':
+          description: Pets
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  type: string
+components: {}
+`)
+	loader := openapi3.NewLoader()
+	doc3, err := loader.LoadFromData(spec)
+	require.NoError(t, err)
+
+	losses := []openapi2conv.Loss{{Pointer: "/stale"}}
+	_, err = openapi2conv.FromV3(doc3, openapi2conv.WithLosses(&losses))
+	require.NoError(t, err)
+	require.Empty(t, losses)
+}
@@ -2,25 +2,42 @@ package openapi2conv
 
 import (
 	"cmp"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"maps"
+	"net/http"
 	"net/url"
+	"regexp"
 	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/getkin/kin-openapi/openapi2"
 	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3conv"
 )
 
 // ToV3 converts an OpenAPIv2 spec to an OpenAPIv3 spec
-func ToV3(doc2 *openapi2.T) (*openapi3.T, error) {
-	return ToV3WithLoader(doc2, openapi3.NewLoader(), nil)
+func ToV3(doc2 *openapi2.T, opts ...Option) (*openapi3.T, error) {
+	return ToV3WithLoader(doc2, openapi3.NewLoader(), nil, opts...)
 }
 
-func ToV3WithLoader(doc2 *openapi2.T, loader *openapi3.Loader, location *url.URL) (*openapi3.T, error) {
+func ToV3WithLoader(doc2 *openapi2.T, loader *openapi3.Loader, location *url.URL, opts ...Option) (*openapi3.T, error) {
+	o := options{targetVersion: defaultTargetVersion}
+	for _, apply := range opts {
+		apply(&o)
+	}
+	if len(o.fromV3Only) != 0 {
+		return nil, fmt.Errorf("option %s only applies to FromV3", o.fromV3Only[0])
+	}
+	if !targetVersionPattern.MatchString(o.targetVersion) {
+		return nil, fmt.Errorf("unsupported target version %q", o.targetVersion)
+	}
+	target := (&openapi3.T{OpenAPI: o.targetVersion}).OpenAPIMajorMinor()
+
 	doc3 := &openapi3.T{
-		OpenAPI:      "3.0.3",
+		OpenAPI:      defaultTargetVersion,
 		Info:         &doc2.Info,
 		Components:   &openapi3.Components{},
 		Tags:         doc2.Tags,
@@ -72,15 +89,14 @@ func ToV3WithLoader(doc2 *openapi2.T, loader *openapi3.Loader, location *url.URL
 		}
 	}
 
-	if paths := doc2.Paths; len(paths) != 0 {
-		doc3.Paths = openapi3.NewPathsWithCapacity(len(paths))
-		for path, pathItem := range paths {
-			r, err := ToV3PathItem(doc2, doc3.Components, pathItem, doc2.Consumes)
-			if err != nil {
-				return nil, err
-			}
-			doc3.Paths.Set(path, r)
+	// Paths are required, even when empty
+	doc3.Paths = openapi3.NewPathsWithCapacity(len(doc2.Paths))
+	for path, pathItem := range doc2.Paths {
+		r, err := ToV3PathItem(doc2, doc3.Components, pathItem, doc2.Consumes)
+		if err != nil {
+			return nil, err
 		}
+		doc3.Paths.Set(path, r)
 	}
 
 	if responses := doc2.Responses; len(responses) != 0 {
@@ -114,9 +130,79 @@ func ToV3WithLoader(doc2 *openapi2.T, loader *openapi3.Loader, location *url.URL
 		return nil, err
 	}
 
+	if target != "3.0" {
+		// Documents are upgraded whether valid or not, validating them is left to callers
+		if err := toV3Webhooks(doc2, doc3); err != nil {
+			return nil, err
+		}
+		if err := loader.ResolveRefsIn(doc3, location); err != nil {
+			return nil, err
+		}
+		toV31Examples(doc3)
+		openapi3conv.Upgrade(doc3)
+	}
+	doc3.OpenAPI = o.targetVersion
+
 	return doc3, nil
 }
 
+// defaultTargetVersion is the version of the documents produced by ToV3.
+const defaultTargetVersion = "3.0.3"
+
+// targetVersionPattern matches the versions ToV3 can produce.
+var targetVersionPattern = regexp.MustCompile(`^3\.[0-2]\.[0-9]+$`)
+
+// WithTargetVersion makes ToV3 produce documents of the given OpenAPI 3.0, 3.1
+// or 3.2 version (e.g. "3.1.0"), instead of "3.0.3". Other versions are rejected.
+// Documents of version 3.1 and later use type arrays rather than nullable,
+// numeric exclusive bounds, examples rather than example, and webhooks
+// converted from the x-webhooks extension written by FromV3.
+// As Swagger 2.0 has no reusable path items, no components.pathItems are produced.
+// Converted documents are not validated.
+func WithTargetVersion(version string) Option {
+	return func(o *options) {
+		o.targetVersion = version
+		o.toV3Only = append(o.toV3Only, "WithTargetVersion")
+	}
+}
+
+// toV3Webhooks converts the x-webhooks extension written by FromV3 back into webhooks.
+func toV3Webhooks(doc2 *openapi2.T, doc3 *openapi3.T) error {
+	value, ok := doc3.Extensions["x-webhooks"]
+	if !ok {
+		return nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	var webhooks map[string]*openapi2.PathItem
+	if err := json.Unmarshal(data, &webhooks); err != nil {
+		return fmt.Errorf("invalid x-webhooks extension: %w", err)
+	}
+	doc3.Webhooks = make(map[string]*openapi3.PathItem, len(webhooks))
+	for name, pathItem := range webhooks {
+		if doc3.Webhooks[name], err = ToV3PathItem(doc2, doc3.Components, pathItem, doc2.Consumes); err != nil {
+			return err
+		}
+	}
+	delete(doc3.Extensions, "x-webhooks")
+	return nil
+}
+
+// toV31Examples converts the x-examples extension written by FromV3 back into schema examples.
+func toV31Examples(doc3 *openapi3.T) {
+	_ = doc3.WalkSchemas(func(_ string, schemaRef *openapi3.SchemaRef) error {
+		schema := schemaRef.Value
+		if examples, ok := schema.Extensions["x-examples"].([]any); ok {
+			schema.Examples = examples
+			schema.Example = nil
+			delete(schema.Extensions, "x-examples")
+		}
+		return nil
+	})
+}
+
 func ToV3PathItem(doc2 *openapi2.T, components *openapi3.Components, pathItem *openapi2.PathItem, consumes []string) (*openapi3.PathItem, error) {
 	doc3 := &openapi3.PathItem{
 		Extensions: stripNonExtensions(pathItem.Extensions),
@@ -483,7 +569,7 @@ func ToV3SchemaRef(schema *openapi2.SchemaRef) *openapi3.SchemaRef {
 
 	v3Schema := &openapi3.Schema{
 		Extensions:           schema.Value.Extensions,
-		Type:                 schema.Value.Type,
+		Type:                 fromV3Types(schema.Value.Type),
 		Title:                schema.Value.Title,
 		Format:               schema.Value.Format,
 		Description:          schema.Value.Description,
@@ -533,6 +619,10 @@ func ToV3SchemaRef(schema *openapi2.SchemaRef) *openapi3.SchemaRef {
 	for i, v := range schema.Value.AllOf {
 		v3Schema.AllOf[i] = ToV3SchemaRef(v)
 	}
+	// A "null" type, as in OAS 3.1 type arrays, is described by nullable
+	if schema.Value.Type.IncludesNull() {
+		v3Schema.Nullable = true
+	}
 	if val, ok := v3Schema.Extensions["x-nullable"]; ok {
 		if nullable, valid := val.(bool); valid {
 			v3Schema.Nullable = nullable
@@ -594,6 +684,12 @@ func ToV3Ref(ref string) string {
 }
 
 func FromV3Ref(ref string) string {
+	// $defs of component schemas are hoisted into definitions by FromV3Schemas
+	if rest, ok := strings.CutPrefix(ref, "#/components/schemas/"); ok {
+		if name, def, ok := strings.Cut(rest, "/$defs/"); ok && !strings.Contains(name, "/") && !strings.Contains(def, "/") {
+			return "#/definitions/" + name + "." + def
+		}
+	}
 	for new, old := range ref2To3 {
 		if strings.HasPrefix(ref, old) {
 			ref = strings.Replace(ref, old, new, 1)
@@ -661,13 +757,27 @@ func ToV3SecurityScheme(securityScheme *openapi2.SecurityScheme) (*openapi3.Secu
 	}, nil
 }
 
-// FromV3 converts an OpenAPIv3 spec to an OpenAPIv2 spec
-func FromV3(doc3 *openapi3.T) (*openapi2.T, error) {
-	doc2Responses, err := FromV3Responses(doc3.Components.Responses, doc3.Components)
+// FromV3 converts an OpenAPIv3 spec to an OpenAPIv2 spec.
+// Constructs without an OpenAPIv2 equivalent are dropped or approximated,
+// use WithLosses to list them.
+func FromV3(doc3 *openapi3.T, opts ...Option) (*openapi2.T, error) {
+	o := options{}
+	for _, apply := range opts {
+		apply(&o)
+	}
+	if len(o.toV3Only) != 0 {
+		return nil, fmt.Errorf("option %s only applies to ToV3", o.toV3Only[0])
+	}
+	var r *lossRecorder
+	if o.losses != nil {
+		r = newLossRecorder()
+	}
+
+	doc2Responses, err := fromV3Responses(r, "/components/responses", doc3.Components.Responses, doc3.Components)
 	if err != nil {
 		return nil, err
 	}
-	schemas, parameters := FromV3Schemas(doc3.Components.Schemas, doc3.Components)
+	schemas, parameters := fromV3Schemas(r, doc3.Components.Schemas, doc3.Components)
 	doc2 := &openapi2.T{
 		Swagger:      "2.0",
 		Info:         *doc3.Info,
@@ -683,7 +793,9 @@ func FromV3(doc3 *openapi3.T) (*openapi2.T, error) {
 	isHTTP := false
 	servers := doc3.Servers
 	for i, server := range servers {
-		if parsedURL, err := url.Parse(server.URL); err == nil {
+		pointer := pointerTo("/servers", strconv.Itoa(i))
+		parsedURL, err := url.Parse(server.URL)
+		if err == nil {
 			switch parsedURL.Scheme {
 			case "https":
 				isHTTPS = true
@@ -696,6 +808,16 @@ func FromV3(doc3 *openapi3.T) (*openapi2.T, error) {
 				doc2.BasePath = parsedURL.Path
 			}
 		}
+		switch {
+		case i == 0:
+			for name := range server.Variables {
+				r.add(pointerTo(pointer, "variables", name), LossServers, "dropped, the variable is left unexpanded in the host and base path")
+			}
+		case err == nil && (parsedURL.Scheme == "http" || parsedURL.Scheme == "https"):
+			r.add(pointer, LossServers, "only its scheme is kept, the host and base path are those of the first server")
+		default:
+			r.add(pointer, LossServers, "dropped, the host and base path are those of the first server")
+		}
 	}
 
 	if isHTTPS {
@@ -705,42 +827,30 @@ func FromV3(doc3 *openapi3.T) (*openapi2.T, error) {
 		doc2.Schemes = append(doc2.Schemes, "http")
 	}
 
+	doc2.Paths = make(map[string]*openapi2.PathItem, doc3.Paths.Len())
 	for path, pathItem := range doc3.Paths.Map() {
 		if pathItem == nil {
 			continue
 		}
-		doc2.AddOperation(path, "GET", nil)
-		addPathExtensions(doc2, path, stripNonExtensions(pathItem.Extensions))
-		for method, operation := range pathItem.Operations() {
-			if operation == nil {
-				continue
-			}
-			doc2Operation, err := FromV3Operation(doc3, operation)
-			if err != nil {
-				return nil, err
-			}
-			doc2.AddOperation(path, method, doc2Operation)
+		doc2PathItem, err := fromV3PathItem(r, pointerTo("/paths", path), doc3, pathItem)
+		if err != nil {
+			return nil, err
 		}
-		params := openapi2.Parameters{}
-		for _, param := range pathItem.Parameters {
-			p, err := FromV3Parameter(param, doc3.Components)
-			if err != nil {
-				return nil, err
-			}
-			params = append(params, p)
+		if doc2PathItem.Parameters == nil {
+			doc2PathItem.Parameters = openapi2.Parameters{}
 		}
-		slices.SortFunc(params, compareParameters)
-		doc2.Paths[path].Parameters = params
+		slices.SortFunc(doc2PathItem.Parameters, compareParameters)
+		doc2.Paths[path] = doc2PathItem
 	}
 
 	for name, param := range doc3.Components.Parameters {
-		if doc2.Parameters[name], err = FromV3Parameter(param, doc3.Components); err != nil {
+		if doc2.Parameters[name], err = fromV3Parameter(r, pointerTo("/components/parameters", name), param, doc3.Components); err != nil {
 			return nil, err
 		}
 	}
 
 	for name, requestBodyRef := range doc3.Components.RequestBodies {
-		bodyOrRefParameters, formDataParameters, consumes, err := fromV3RequestBodies(name, requestBodyRef, doc3.Components)
+		bodyOrRefParameters, formDataParameters, consumes, err := fromV3RequestBodies(r, pointerTo("/components/requestBodies", name), name, requestBodyRef, doc3.Components)
 		if err != nil {
 			return nil, err
 		}
@@ -749,9 +859,8 @@ func FromV3(doc3 *openapi3.T) (*openapi2.T, error) {
 				doc2.Parameters[param.Name] = param
 			}
 		} else if len(bodyOrRefParameters) != 0 {
-			for _, param := range bodyOrRefParameters {
-				doc2.Parameters[name] = param
-			}
+			// A single request body, as for operations
+			doc2.Parameters[name] = bodyOrRefParameters[0]
 		}
 
 		if len(consumes) != 0 {
@@ -762,7 +871,7 @@ func FromV3(doc3 *openapi3.T) (*openapi2.T, error) {
 	if m := doc3.Components.SecuritySchemes; m != nil {
 		doc2SecuritySchemes := make(map[string]*openapi2.SecurityScheme)
 		for id, securityScheme := range m {
-			v, err := FromV3SecurityScheme(securityScheme)
+			v, err := fromV3SecurityScheme(r, pointerTo("/components/securitySchemes", id), securityScheme)
 			if err != nil {
 				return nil, err
 			}
@@ -772,6 +881,33 @@ func FromV3(doc3 *openapi3.T) (*openapi2.T, error) {
 	}
 	doc2.Security = FromV3SecurityRequirements(doc3.Security)
 
+	// Other components have no OpenAPI 2 equivalent
+	droppedNames(r, "/components/headers", LossComponents, doc3.Components.Headers)
+	droppedNames(r, "/components/examples", LossComponents, doc3.Components.Examples)
+	droppedNames(r, "/components/links", LossComponents, doc3.Components.Links)
+	droppedNames(r, "/components/callbacks", LossComponents, doc3.Components.Callbacks)
+
+	if len(doc3.Webhooks) != 0 {
+		webhooks := make(map[string]*openapi2.PathItem, len(doc3.Webhooks))
+		for name, pathItem := range doc3.Webhooks {
+			pointer := pointerTo("/webhooks", name)
+			r.add(pointer, LossWebhooks, "converted to the x-webhooks extension")
+			if pathItem == nil {
+				continue
+			}
+			if webhooks[name], err = fromV3PathItem(r, pointer, doc3, pathItem); err != nil {
+				return nil, err
+			}
+		}
+		if doc2.Extensions == nil {
+			doc2.Extensions = make(map[string]any)
+		}
+		doc2.Extensions["x-webhooks"] = webhooks
+	}
+
+	if o.losses != nil {
+		*o.losses = r.sorted()
+	}
 	return doc2, nil
 }
 
@@ -784,7 +920,10 @@ func consumesToArray(consumes map[string]struct{}) []string {
 	return consumesArr
 }
 
-func fromV3RequestBodies(name string, requestBodyRef *openapi3.RequestBodyRef, components *openapi3.Components) (
+// fromV3RequestBodies converts a request body to a body parameter for each of its non-form
+// media types, or to formData parameters. Callers keep the form parameters if any, otherwise
+// the first body parameter: the other media types are only listed in consumes.
+func fromV3RequestBodies(r *lossRecorder, pointer, name string, requestBodyRef *openapi3.RequestBodyRef, components *openapi3.Components) (
 	bodyOrRefParameters openapi2.Parameters,
 	formParameters openapi2.Parameters,
 	consumes map[string]struct{},
@@ -797,13 +936,35 @@ func fromV3RequestBodies(name string, requestBodyRef *openapi3.RequestBodyRef, c
 
 	// Only select one formData or request body for an individual requestBody as OpenAPI 2 does not support multiples
 	if requestBodyRef.Value != nil {
-		for contentType, mediaType := range requestBodyRef.Value.Content {
+		content := requestBodyRef.Value.Content
+		contentTypes := sortedKeys(content)
+		var formType, bodyType string
+		for _, contentType := range contentTypes {
+			if isFormContentType(contentType) {
+				formType = contentType
+			} else if bodyType == "" {
+				bodyType = contentType
+			}
+		}
+		kept := cmp.Or(formType, bodyType)
+		for _, contentType := range contentTypes {
+			mediaType := content[contentType]
 			if consumes == nil {
 				consumes = make(map[string]struct{})
 			}
 			consumes[contentType] = struct{}{}
-			if contentType == "application/x-www-form-urlencoded" || contentType == "multipart/form-data" {
-				formParameters = FromV3RequestBodyFormData(mediaType)
+
+			// Only the losses of the media type kept are of interest
+			mediaPointer, mediaLosses := pointerTo(pointer, "content", contentType), r
+			if contentType != kept {
+				r.add(mediaPointer, LossMediaTypes, "only listed in consumes, the parameters are those of "+kept)
+				mediaLosses = nil
+			} else if mediaType != nil {
+				r.examples(mediaPointer, mediaType.Example, mediaType.Examples)
+			}
+
+			if isFormContentType(contentType) {
+				formParameters = fromV3RequestBodyFormData(mediaLosses, mediaPointer, mediaType)
 				continue
 			}
 
@@ -812,22 +973,27 @@ func fromV3RequestBodies(name string, requestBodyRef *openapi3.RequestBodyRef, c
 				paramName = originalName.(string)
 			}
 
-			var r *openapi2.Parameter
-			if r, err = FromV3RequestBody(paramName, requestBodyRef, mediaType, components); err != nil {
+			var parameter *openapi2.Parameter
+			if parameter, err = fromV3RequestBody(mediaLosses, mediaPointer, paramName, requestBodyRef, mediaType, components); err != nil {
 				return
 			}
 
-			bodyOrRefParameters = append(bodyOrRefParameters, r)
+			bodyOrRefParameters = append(bodyOrRefParameters, parameter)
 		}
 	}
 	return
 }
 
 func FromV3Schemas(schemas map[string]*openapi3.SchemaRef, components *openapi3.Components) (map[string]*openapi2.SchemaRef, map[string]*openapi2.Parameter) {
+	return fromV3Schemas(nil, schemas, components)
+}
+
+func fromV3Schemas(r *lossRecorder, schemas map[string]*openapi3.SchemaRef, components *openapi3.Components) (map[string]*openapi2.SchemaRef, map[string]*openapi2.Parameter) {
 	v2Defs := make(map[string]*openapi2.SchemaRef)
 	v2Params := make(map[string]*openapi2.Parameter)
 	for name, schema := range schemas {
-		schemaConv, parameterConv := FromV3SchemaRef(schema, components)
+		pointer := pointerTo("/components/schemas", name)
+		schemaConv, parameterConv := fromV3SchemaRef(r, pointer, schema, components)
 		if schemaConv != nil {
 			v2Defs[name] = schemaConv
 		} else if parameterConv != nil {
@@ -836,11 +1002,25 @@ func FromV3Schemas(schemas map[string]*openapi3.SchemaRef, components *openapi3.
 			}
 			v2Params[name] = parameterConv
 		}
+
+		// OAS 2.0 has no $defs: hoist them into definitions (see FromV3Ref)
+		if schema.Ref == "" && schema.Value != nil {
+			for defName, def := range schema.Value.Defs {
+				if defConv, _ := fromV3SchemaRef(r, pointerTo(pointer, "$defs", defName), def, components); defConv != nil {
+					v2Defs[name+"."+defName] = defConv
+				}
+			}
+		}
 	}
 	return v2Defs, v2Params
 }
 
 func FromV3SchemaRef(schema *openapi3.SchemaRef, components *openapi3.Components) (*openapi2.SchemaRef, *openapi2.Parameter) {
+	return fromV3SchemaRef(nil, "", schema, components)
+}
+
+func fromV3SchemaRef(r *lossRecorder, pointer string, schema *openapi3.SchemaRef, components *openapi3.Components) (*openapi2.SchemaRef, *openapi2.Parameter) {
+	// Referenced schemas are converted, and their losses recorded, where they are defined
 	if ref := schema.Ref; ref != "" {
 		// FromV3RequestBodyFormData (and other recursive call sites in
 		// this file) pass components=nil when recursing into array
@@ -868,8 +1048,12 @@ func FromV3SchemaRef(schema *openapi3.SchemaRef, components *openapi3.Components
 		}, nil
 	}
 
+	r.schema(pointer, schema.Value)
+
+	minimum, exclusiveMin := effectiveMin(schema.Value.Min, schema.Value.ExclusiveMin)
+	maximum, exclusiveMax := effectiveMax(schema.Value.Max, schema.Value.ExclusiveMax)
 	if schema.Value != nil {
-		if schema.Value.Type.Is("string") && schema.Value.Format == "binary" {
+		if fromV3Types(schema.Value.Type).Is("string") && schema.Value.Format == "binary" {
 			paramType := &openapi3.Types{"file"}
 
 			value := schema.Value.Extensions["x-formData-name"]
@@ -881,10 +1065,10 @@ func FromV3SchemaRef(schema *openapi3.SchemaRef, components *openapi3.Components
 				Description:  schema.Value.Description,
 				Type:         paramType,
 				Enum:         schema.Value.Enum,
-				Minimum:      effectiveMin(schema.Value.Min, schema.Value.ExclusiveMin),
-				Maximum:      effectiveMax(schema.Value.Max, schema.Value.ExclusiveMax),
-				ExclusiveMin: exclusiveBoundToBool(schema.Value.ExclusiveMin),
-				ExclusiveMax: exclusiveBoundToBool(schema.Value.ExclusiveMax),
+				Minimum:      minimum,
+				Maximum:      maximum,
+				ExclusiveMin: exclusiveMin,
+				ExclusiveMax: exclusiveMax,
 				MinLength:    schema.Value.MinLength,
 				MaxLength:    schema.Value.MaxLength,
 				Default:      schema.Value.Default,
@@ -902,7 +1086,7 @@ func FromV3SchemaRef(schema *openapi3.SchemaRef, components *openapi3.Components
 
 	v2Schema := &openapi2.Schema{
 		Extensions:           schema.Value.Extensions,
-		Type:                 schema.Value.Type,
+		Type:                 fromV3Types(schema.Value.Type),
 		Title:                schema.Value.Title,
 		Format:               schema.Value.Format,
 		Description:          schema.Value.Description,
@@ -911,15 +1095,15 @@ func FromV3SchemaRef(schema *openapi3.SchemaRef, components *openapi3.Components
 		Example:              schema.Value.Example,
 		ExternalDocs:         schema.Value.ExternalDocs,
 		UniqueItems:          schema.Value.UniqueItems,
-		ExclusiveMin:         exclusiveBoundToBool(schema.Value.ExclusiveMin),
-		ExclusiveMax:         exclusiveBoundToBool(schema.Value.ExclusiveMax),
+		ExclusiveMin:         exclusiveMin,
+		ExclusiveMax:         exclusiveMax,
 		ReadOnly:             schema.Value.ReadOnly,
 		WriteOnly:            schema.Value.WriteOnly,
 		AllowEmptyValue:      schema.Value.AllowEmptyValue,
 		Deprecated:           schema.Value.Deprecated,
 		XML:                  schema.Value.XML,
-		Min:                  effectiveMin(schema.Value.Min, schema.Value.ExclusiveMin),
-		Max:                  effectiveMax(schema.Value.Max, schema.Value.ExclusiveMax),
+		Min:                  minimum,
+		Max:                  maximum,
 		MultipleOf:           schema.Value.MultipleOf,
 		MinLength:            schema.Value.MinLength,
 		MaxLength:            schema.Value.MaxLength,
@@ -935,7 +1119,7 @@ func FromV3SchemaRef(schema *openapi3.SchemaRef, components *openapi3.Components
 	}
 
 	if v := schema.Value.Items; v != nil {
-		v2Schema.Items, _ = FromV3SchemaRef(v, components)
+		v2Schema.Items, _ = fromV3SchemaRef(r, pointerTo(pointer, "items"), v, components)
 	}
 
 	keys := make([]string, 0, len(schema.Value.Properties))
@@ -944,14 +1128,14 @@ func FromV3SchemaRef(schema *openapi3.SchemaRef, components *openapi3.Components
 	}
 	slices.Sort(keys)
 	for _, key := range keys {
-		property, _ := FromV3SchemaRef(schema.Value.Properties[key], components)
+		property, _ := fromV3SchemaRef(r, pointerTo(pointer, "properties", key), schema.Value.Properties[key], components)
 		if property != nil {
 			v2Schema.Properties[key] = property
 		}
 	}
 
 	for i, v := range schema.Value.AllOf {
-		v2Schema.AllOf[i], _ = FromV3SchemaRef(v, components)
+		v2Schema.AllOf[i], _ = fromV3SchemaRef(r, pointerTo(pointer, "allOf", strconv.Itoa(i)), v, components)
 	}
 	if schema.Value.PermitsNull() {
 		schema.Value.Nullable = false
@@ -960,6 +1144,20 @@ func FromV3SchemaRef(schema *openapi3.SchemaRef, components *openapi3.Components
 		}
 		v2Schema.Extensions["x-nullable"] = true
 	}
+	if len(v2Schema.Enum) == 0 && schema.Value.Const != nil {
+		v2Schema.Enum = []any{schema.Value.Const}
+	}
+	if examples := schema.Value.Examples; len(examples) != 0 {
+		if v2Schema.Example == nil {
+			v2Schema.Example = examples[0]
+		}
+		if len(examples) > 1 {
+			if v2Schema.Extensions == nil {
+				v2Schema.Extensions = make(map[string]any)
+			}
+			v2Schema.Extensions["x-examples"] = examples
+		}
+	}
 
 	return &openapi2.SchemaRef{
 		Extensions: schema.Extensions,
@@ -979,18 +1177,28 @@ func FromV3SecurityRequirements(requirements openapi3.SecurityRequirements) open
 }
 
 func FromV3PathItem(doc3 *openapi3.T, pathItem *openapi3.PathItem) (*openapi2.PathItem, error) {
+	return fromV3PathItem(nil, "", doc3, pathItem)
+}
+
+func fromV3PathItem(r *lossRecorder, pointer string, doc3 *openapi3.T, pathItem *openapi3.PathItem) (*openapi2.PathItem, error) {
 	result := &openapi2.PathItem{
 		Extensions: stripNonExtensions(pathItem.Extensions),
 	}
+	r.droppedServers(pointerTo(pointer, "servers"), pathItem.Servers)
 	for method, operation := range pathItem.Operations() {
-		r, err := FromV3Operation(doc3, operation)
+		operationPointer := pointerTo(pointer, strings.ToLower(method))
+		if method == http.MethodConnect || method == http.MethodTrace {
+			r.add(operationPointer, LossOperation, "dropped")
+			continue
+		}
+		op, err := fromV3Operation(r, operationPointer, doc3, operation)
 		if err != nil {
 			return nil, err
 		}
-		result.SetOperation(method, r)
+		result.SetOperation(method, op)
 	}
-	for _, parameter := range pathItem.Parameters {
-		p, err := FromV3Parameter(parameter, doc3.Components)
+	for i, parameter := range pathItem.Parameters {
+		p, err := fromV3Parameter(r, pointerTo(pointer, "parameters", strconv.Itoa(i)), parameter, doc3.Components)
 		if err != nil {
 			return nil, err
 		}
@@ -1014,6 +1222,16 @@ nameSearch:
 }
 
 func FromV3RequestBodyFormData(mediaType *openapi3.MediaType) openapi2.Parameters {
+	return fromV3RequestBodyFormData(nil, "", mediaType)
+}
+
+func fromV3RequestBodyFormData(r *lossRecorder, pointer string, mediaType *openapi3.MediaType) openapi2.Parameters {
+	pointer = pointerTo(pointer, "schema")
+	if mediaType.Schema.Ref != "" {
+		// Losses of the referenced schema are recorded where it is defined
+		r = nil
+	}
+	r.schema(pointer, mediaType.Schema.Value)
 	parameters := openapi2.Parameters{}
 	for propName, schemaRef := range mediaType.Schema.Value.Properties {
 		if ref := schemaRef.Ref; ref != "" {
@@ -1022,7 +1240,11 @@ func FromV3RequestBodyFormData(mediaType *openapi3.MediaType) openapi2.Parameter
 			continue
 		}
 		val := schemaRef.Value
-		typ := val.Type
+		propPointer := pointerTo(pointer, "properties", propName)
+		r.schema(propPointer, val)
+		typ := fromV3Types(val.Type)
+		minimum, exclusiveMin := effectiveMin(val.Min, val.ExclusiveMin)
+		maximum, exclusiveMax := effectiveMax(val.Max, val.ExclusiveMax)
 		if val.Format == "binary" {
 			typ = &openapi3.Types{"file"}
 		}
@@ -1030,7 +1252,7 @@ func FromV3RequestBodyFormData(mediaType *openapi3.MediaType) openapi2.Parameter
 
 		var v2Items *openapi2.SchemaRef
 		if val.Items != nil {
-			v2Items, _ = FromV3SchemaRef(val.Items, nil)
+			v2Items, _ = fromV3SchemaRef(r, pointerTo(propPointer, "items"), val.Items, nil)
 		}
 		parameter := &openapi2.Parameter{
 			Name:         propName,
@@ -1039,16 +1261,16 @@ func FromV3RequestBodyFormData(mediaType *openapi3.MediaType) openapi2.Parameter
 			In:           "formData",
 			Extensions:   stripNonExtensions(val.Extensions),
 			Enum:         val.Enum,
-			ExclusiveMin: exclusiveBoundToBool(val.ExclusiveMin),
-			ExclusiveMax: exclusiveBoundToBool(val.ExclusiveMax),
+			ExclusiveMin: exclusiveMin,
+			ExclusiveMax: exclusiveMax,
 			MinLength:    val.MinLength,
 			MaxLength:    val.MaxLength,
 			Default:      val.Default,
 			Items:        v2Items,
 			MinItems:     val.MinItems,
 			MaxItems:     val.MaxItems,
-			Maximum:      effectiveMax(val.Max, val.ExclusiveMax),
-			Minimum:      effectiveMin(val.Min, val.ExclusiveMin),
+			Maximum:      maximum,
+			Minimum:      minimum,
 			Pattern:      val.Pattern,
 			// CollectionFormat: val.CollectionFormat,
 			// Format:          val.Format,
@@ -1063,6 +1285,10 @@ func FromV3RequestBodyFormData(mediaType *openapi3.MediaType) openapi2.Parameter
 }
 
 func FromV3Operation(doc3 *openapi3.T, operation *openapi3.Operation) (*openapi2.Operation, error) {
+	return fromV3Operation(nil, "", doc3, operation)
+}
+
+func fromV3Operation(r *lossRecorder, pointer string, doc3 *openapi3.T, operation *openapi3.Operation) (*openapi2.Operation, error) {
 	if operation == nil {
 		return nil, nil
 	}
@@ -1079,12 +1305,15 @@ func FromV3Operation(doc3 *openapi3.T, operation *openapi3.Operation) (*openapi2
 		resultSecurity := FromV3SecurityRequirements(*v)
 		result.Security = &resultSecurity
 	}
-	for _, parameter := range operation.Parameters {
-		r, err := FromV3Parameter(parameter, doc3.Components)
+	if operation.Servers != nil {
+		r.droppedServers(pointerTo(pointer, "servers"), *operation.Servers)
+	}
+	for i, parameter := range operation.Parameters {
+		p, err := fromV3Parameter(r, pointerTo(pointer, "parameters", strconv.Itoa(i)), parameter, doc3.Components)
 		if err != nil {
 			return nil, err
 		}
-		result.Parameters = append(result.Parameters, r)
+		result.Parameters = append(result.Parameters, p)
 	}
 	if v := operation.RequestBody; v != nil {
 		// Find parameter name that we can use for the body
@@ -1093,7 +1322,7 @@ func FromV3Operation(doc3 *openapi3.T, operation *openapi3.Operation) (*openapi2
 			return nil, errors.New("could not find a name for request body")
 		}
 
-		bodyOrRefParameters, formDataParameters, consumes, err := fromV3RequestBodies(name, v, doc3.Components)
+		bodyOrRefParameters, formDataParameters, consumes, err := fromV3RequestBodies(r, pointerTo(pointer, "requestBody"), name, v, doc3.Components)
 		if err != nil {
 			return nil, err
 		}
@@ -1114,16 +1343,21 @@ func FromV3Operation(doc3 *openapi3.T, operation *openapi3.Operation) (*openapi2
 	slices.SortFunc(result.Parameters, compareParameters)
 
 	if responses := operation.Responses; responses != nil {
-		resultResponses, err := FromV3Responses(responses.Map(), doc3.Components)
+		resultResponses, err := fromV3Responses(r, pointerTo(pointer, "responses"), responses.Map(), doc3.Components)
 		if err != nil {
 			return nil, err
 		}
 		result.Responses = resultResponses
 	}
+	droppedNames(r, pointerTo(pointer, "callbacks"), LossCallbacks, operation.Callbacks)
 	return result, nil
 }
 
 func FromV3RequestBody(name string, requestBodyRef *openapi3.RequestBodyRef, mediaType *openapi3.MediaType, components *openapi3.Components) (*openapi2.Parameter, error) {
+	return fromV3RequestBody(nil, "", name, requestBodyRef, mediaType, components)
+}
+
+func fromV3RequestBody(r *lossRecorder, pointer, name string, requestBodyRef *openapi3.RequestBodyRef, mediaType *openapi3.MediaType, components *openapi3.Components) (*openapi2.Parameter, error) {
 	requestBody := requestBodyRef.Value
 
 	result := &openapi2.Parameter{
@@ -1135,12 +1369,16 @@ func FromV3RequestBody(name string, requestBodyRef *openapi3.RequestBodyRef, med
 	}
 
 	if mediaType != nil {
-		result.Schema, _ = FromV3SchemaRef(mediaType.Schema, components)
+		result.Schema, _ = fromV3MediaTypeSchemaRef(r, pointer, mediaType, components)
 	}
 	return result, nil
 }
 
 func FromV3Parameter(ref *openapi3.ParameterRef, components *openapi3.Components) (*openapi2.Parameter, error) {
+	return fromV3Parameter(nil, "", ref, components)
+}
+
+func fromV3Parameter(r *lossRecorder, pointer string, ref *openapi3.ParameterRef, components *openapi3.Components) (*openapi2.Parameter, error) {
 	if ref := ref.Ref; ref != "" {
 		return &openapi2.Parameter{Ref: FromV3Ref(ref)}, nil
 	}
@@ -1155,8 +1393,18 @@ func FromV3Parameter(ref *openapi3.ParameterRef, components *openapi3.Components
 		Required:    parameter.Required,
 		Extensions:  stripNonExtensions(parameter.Extensions),
 	}
+	if parameter.In == openapi3.ParameterInCookie {
+		r.add(pointerTo(pointer, "in"), LossCookieParameter, `kept with "in": "cookie", which OpenAPI 2 does not define`)
+	}
+	if len(parameter.Content) != 0 {
+		r.add(pointerTo(pointer, "content"), LossParameterContent, "dropped, the parameter has no type")
+	}
+	r.examples(pointer, parameter.Example, parameter.Examples)
 	if schemaRef := parameter.Schema; schemaRef != nil {
-		schemaRefV2, _ := FromV3SchemaRef(schemaRef, components)
+		if schemaRef.Value != nil && schemaRef.Value.Type.Is("array") && !csvSerialized(parameter) {
+			r.add(pointer, LossParameterSerialization, "converted without a collectionFormat, which defaults to csv")
+		}
+		schemaRefV2, _ := fromV3SchemaRef(r, pointerTo(pointer, "schema"), schemaRef, components)
 		if ref := schemaRefV2.Ref; ref != "" {
 			result.Schema = &openapi2.SchemaRef{Ref: FromV3Ref(ref)}
 			return result, nil
@@ -1185,18 +1433,26 @@ func FromV3Parameter(ref *openapi3.ParameterRef, components *openapi3.Components
 }
 
 func FromV3Responses(responses map[string]*openapi3.ResponseRef, components *openapi3.Components) (map[string]*openapi2.Response, error) {
+	return fromV3Responses(nil, "", responses, components)
+}
+
+func fromV3Responses(r *lossRecorder, pointer string, responses map[string]*openapi3.ResponseRef, components *openapi3.Components) (map[string]*openapi2.Response, error) {
 	v2Responses := make(map[string]*openapi2.Response, len(responses))
 	for k, response := range responses {
-		r, err := FromV3Response(response, components)
+		v2Response, err := fromV3Response(r, pointerTo(pointer, k), response, components)
 		if err != nil {
 			return nil, err
 		}
-		v2Responses[k] = r
+		v2Responses[k] = v2Response
 	}
 	return v2Responses, nil
 }
 
 func FromV3Response(ref *openapi3.ResponseRef, components *openapi3.Components) (*openapi2.Response, error) {
+	return fromV3Response(nil, "", ref, components)
+}
+
+func fromV3Response(r *lossRecorder, pointer string, ref *openapi3.ResponseRef, components *openapi3.Components) (*openapi2.Response, error) {
 	if ref := ref.Ref; ref != "" {
 		return &openapi2.Response{Ref: FromV3Ref(ref)}, nil
 	}
@@ -1213,25 +1469,36 @@ func FromV3Response(ref *openapi3.ResponseRef, components *openapi3.Components)
 		Description: description,
 		Extensions:  stripNonExtensions(response.Extensions),
 	}
-	if content := response.Content; content != nil {
-		if ct := content["application/json"]; ct != nil {
-			result.Schema, _ = FromV3SchemaRef(ct.Schema, components)
+	for contentType, mediaType := range response.Content {
+		mediaPointer := pointerTo(pointer, "content", contentType)
+		if contentType != "application/json" {
+			r.add(mediaPointer, LossMediaTypes, "dropped, only the application/json schema is converted")
+			continue
+		}
+		if mediaType != nil {
+			r.examples(mediaPointer, mediaType.Example, mediaType.Examples)
+			result.Schema, _ = fromV3MediaTypeSchemaRef(r, mediaPointer, mediaType, components)
 		}
 	}
 	if headers := response.Headers; len(headers) > 0 {
 		var err error
-		if result.Headers, err = FromV3Headers(headers, components); err != nil {
+		if result.Headers, err = fromV3Headers(r, pointerTo(pointer, "headers"), headers, components); err != nil {
 			return nil, err
 		}
 	}
+	droppedNames(r, pointerTo(pointer, "links"), LossLinks, response.Links)
 	return result, nil
 }
 
 func FromV3Headers(defs openapi3.Headers, components *openapi3.Components) (map[string]*openapi2.Header, error) {
+	return fromV3Headers(nil, "", defs, components)
+}
+
+func fromV3Headers(r *lossRecorder, pointer string, defs openapi3.Headers, components *openapi3.Components) (map[string]*openapi2.Header, error) {
 	headers := make(map[string]*openapi2.Header, len(defs))
 	for name, header := range defs {
 		ref := openapi3.ParameterRef{Ref: header.Ref, Value: &header.Value.Parameter}
-		parameter, err := FromV3Parameter(&ref, components)
+		parameter, err := fromV3Parameter(r, pointerTo(pointer, name), &ref, components)
 		if err != nil {
 			return nil, err
 		}
@@ -1243,6 +1510,10 @@ func FromV3Headers(defs openapi3.Headers, components *openapi3.Components) (map[
 }
 
 func FromV3SecurityScheme(ref *openapi3.SecuritySchemeRef) (*openapi2.SecurityScheme, error) {
+	return fromV3SecurityScheme(nil, "", ref)
+}
+
+func fromV3SecurityScheme(r *lossRecorder, pointer string, ref *openapi3.SecuritySchemeRef) (*openapi2.SecurityScheme, error) {
 	securityScheme := ref.Value
 	if securityScheme == nil {
 		return nil, nil
@@ -1258,6 +1529,7 @@ func FromV3SecurityScheme(ref *openapi3.SecuritySchemeRef) (*openapi2.SecuritySc
 		case "basic":
 			result.Type = "basic"
 		default:
+			r.add(pointerTo(pointer, "scheme"), LossSecurityScheme, "converted to an apiKey scheme for the Authorization header")
 			result.Type = "apiKey"
 			result.In = "header"
 			result.Name = "Authorization"
@@ -1271,7 +1543,8 @@ func FromV3SecurityScheme(ref *openapi3.SecuritySchemeRef) (*openapi2.SecuritySc
 		flows := securityScheme.Flows
 		if flows != nil {
 			var flow *openapi3.OAuthFlow
-			// TODO: Is this the right priority? What if multiple defined?
+			// An OpenAPI 2 scheme has a single flow: the first one defined is kept,
+			// in the order OpenAPI 3 lists them, and the others are reported as losses
 			switch {
 			case flows.Implicit != nil:
 				result.Flow = "implicit"
@@ -1300,6 +1573,26 @@ func FromV3SecurityScheme(ref *openapi3.SecuritySchemeRef) (*openapi2.SecuritySc
 
 			result.Scopes = make(map[string]string, len(flow.Scopes))
 			maps.Copy(result.Scopes, flow.Scopes)
+
+			kept := ""
+			for _, other := range []struct {
+				name string
+				flow *openapi3.OAuthFlow
+			}{
+				{"implicit", flows.Implicit},
+				{"authorizationCode", flows.AuthorizationCode},
+				{"password", flows.Password},
+				{"clientCredentials", flows.ClientCredentials},
+			} {
+				if other.flow == nil {
+					continue
+				}
+				if kept == "" {
+					kept = other.name
+					continue
+				}
+				r.add(pointerTo(pointer, "flows", other.name), LossOAuthFlows, "dropped, only the "+kept+" flow is converted")
+			}
 		}
 	default:
 		return nil, fmt.Errorf("unsupported security scheme type %q", securityScheme.Type)
@@ -1322,18 +1615,6 @@ func stripNonExtensions(extensions map[string]any) map[string]any {
 	return extensions
 }
 
-func addPathExtensions(doc2 *openapi2.T, path string, extensions map[string]any) {
-	if doc2.Paths == nil {
-		doc2.Paths = make(map[string]*openapi2.PathItem)
-	}
-	pathItem := doc2.Paths[path]
-	if pathItem == nil {
-		pathItem = &openapi2.PathItem{}
-		doc2.Paths[path] = pathItem
-	}
-	pathItem.Extensions = extensions
-}
-
 func compareParameters(a, b *openapi2.Parameter) int {
 	if c := cmp.Compare(a.Name, b.Name); c != 0 {
 		return c
@@ -1352,30 +1633,61 @@ func boolPtr(b bool) *bool {
 	return &b
 }
 
-// exclusiveBoundToBool converts an ExclusiveBound to a bool for OpenAPI 2.0 compatibility
-// In OpenAPI 2.0, exclusiveMinimum/exclusiveMaximum are boolean modifiers
-func exclusiveBoundToBool(eb openapi3.ExclusiveBound) bool {
+// effectiveMin returns the minimum value for OAS 2.0 conversion and whether it is exclusive.
+// In OAS 3.1, exclusiveMinimum is a number applying alongside minimum: the stricter of both is kept.
+func effectiveMin(min *float64, eb openapi3.ExclusiveBound) (*float64, bool) {
 	if eb.Bool != nil {
-		return *eb.Bool
+		return min, *eb.Bool
 	}
-	// If it's a number (OpenAPI 3.1 style), we return true to indicate exclusivity
-	return eb.Value != nil
+	if eb.Value != nil && (min == nil || *eb.Value >= *min) {
+		return eb.Value, true
+	}
+	return min, false
 }
 
-// effectiveMin returns the minimum value for OAS 2.0 conversion, considering ExclusiveBound.
-// In OAS 3.1, exclusiveMinimum is a number. In OAS 2.0, it must be in the minimum field.
-func effectiveMin(min *float64, eb openapi3.ExclusiveBound) *float64 {
-	if min != nil {
-		return min
+// effectiveMax returns the maximum value for OAS 2.0 conversion and whether it is exclusive.
+func effectiveMax(max *float64, eb openapi3.ExclusiveBound) (*float64, bool) {
+	if eb.Bool != nil {
+		return max, *eb.Bool
 	}
-	// If OAS 3.1 style numeric exclusive bound with no minimum, use the bound value as minimum
-	return eb.Value
+	if eb.Value != nil && (max == nil || *eb.Value <= *max) {
+		return eb.Value, true
+	}
+	return max, false
 }
 
-// effectiveMax returns the maximum value for OAS 2.0 conversion, considering ExclusiveBound.
-func effectiveMax(max *float64, eb openapi3.ExclusiveBound) *float64 {
-	if max != nil {
-		return max
+// fromV3Types removes "null" from OAS 3.1 type arrays, nullability being carried by x-nullable in OAS 2.0.
+func fromV3Types(types *openapi3.Types) *openapi3.Types {
+	if types == nil || !types.IncludesNull() {
+		return types
+	}
+	result := slices.DeleteFunc(slices.Clone(*types), func(t string) bool { return t == openapi3.TypeNull })
+	if len(result) == 0 {
+		return nil
+	}
+	return &result
+}
+
+// fromV3MediaTypeSchema returns the schema of a media type, describing
+// OAS 3.2 sequential media types (itemSchema) as arrays.
+func fromV3MediaTypeSchema(mediaType *openapi3.MediaType) *openapi3.SchemaRef {
+	if mediaType.Schema == nil && mediaType.ItemSchema != nil {
+		schema := openapi3.NewArraySchema()
+		schema.Items = mediaType.ItemSchema
+		return schema.NewRef()
+	}
+	return mediaType.Schema
+}
+
+// fromV3MediaTypeSchemaRef converts the schema of a media type.
+func fromV3MediaTypeSchemaRef(r *lossRecorder, pointer string, mediaType *openapi3.MediaType, components *openapi3.Components) (*openapi2.SchemaRef, *openapi2.Parameter) {
+	schema := fromV3MediaTypeSchema(mediaType)
+	if schema == nil {
+		return nil, nil
+	}
+	if mediaType.Schema == nil {
+		// The losses of an itemSchema are not told apart from those of the array describing it
+		r = nil
 	}
-	return eb.Value
+	return fromV3SchemaRef(r, pointerTo(pointer, "schema"), schema, components)
 }
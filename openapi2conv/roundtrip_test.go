@@ -0,0 +1,174 @@
+package openapi2conv_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi2conv"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestToV3TargetVersionRoundTrip(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("..", "openapi2", "testdata", "*.json"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+	fixtures, err := filepath.Glob(filepath.Join("testdata", "roundtrip", "*.json"))
+	require.NoError(t, err)
+	require.NotEmpty(t, fixtures)
+	files = append(files, fixtures...)
+
+	for _, file := range files {
+		for _, version := range []string{"3.1.0", "3.2.0"} {
+			t.Run(filepath.Base(file)+"@"+version, func(t *testing.T) {
+				data, err := os.ReadFile(file)
+				require.NoError(t, err)
+
+				// Swagger 2.0 -> 3.0.3 -> Swagger 2.0 is the reference
+				var doc2 openapi2.T
+				require.NoError(t, json.Unmarshal(data, &doc2))
+				doc30, err := openapi2conv.ToV3(&doc2)
+				require.NoError(t, err)
+				expected, err := openapi2conv.FromV3(doc30)
+				require.NoError(t, err)
+
+				doc2 = openapi2.T{}
+				require.NoError(t, json.Unmarshal(data, &doc2))
+				doc3, err := openapi2conv.ToV3(&doc2, openapi2conv.WithTargetVersion(version))
+				require.NoError(t, err)
+				require.Equal(t, version, doc3.OpenAPI)
+				require.NoError(t, doc3.Validate(context.Background()))
+
+				actual, err := openapi2conv.FromV3(doc3)
+				require.NoError(t, err)
+
+				expectedJSON, err := json.Marshal(expected)
+				require.NoError(t, err)
+				actualJSON, err := json.Marshal(actual)
+				require.NoError(t, err)
+				require.JSONEq(t, string(expectedJSON), string(actualJSON))
+			})
+		}
+	}
+}
+
+func TestToV3TargetVersionFixtures(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "roundtrip", "*.json"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			data, err := os.ReadFile(file)
+			require.NoError(t, err)
+			var doc2 openapi2.T
+			require.NoError(t, json.Unmarshal(data, &doc2))
+
+			doc3, err := openapi2conv.ToV3(&doc2, openapi2conv.WithTargetVersion("3.1.0"))
+			require.NoError(t, err)
+			require.NoError(t, doc3.Validate(context.Background()))
+
+			// Expected documents use type arrays, numeric exclusive bounds, examples and webhooks
+			expected, err := os.ReadFile(filepath.Join("testdata", "roundtrip", "v3.1", filepath.Base(file)))
+			require.NoError(t, err)
+			actual, err := json.Marshal(doc3)
+			require.NoError(t, err)
+			require.JSONEq(t, string(expected), string(actual))
+		})
+	}
+}
+
+func TestToV3TargetVersion(t *testing.T) {
+	spec := []byte(`{
+  "swagger": "2.0",
+  "info": {"title": "Pets", "version": "1.0.0"},
+  "x-webhooks": {
+    "newPet": {
+      "post": {
+        "parameters": [{"in": "body", "name": "body", "schema": {"$ref": "#/definitions/Pet"}}],
+        "responses": {"200": {"description": "Acknowledged"}}
+      }
+    }
+  },
+  "paths": {},
+  "definitions": {
+    "Pet": {
+      "type": "object",
+      "properties": {
+        "name": {"type": "string", "x-nullable": true, "example": "Rex", "x-examples": ["Rex", "Felix"]},
+        "age": {"type": "integer", "minimum": 0, "exclusiveMinimum": true}
+      }
+    }
+  }
+}`)
+	var doc2 openapi2.T
+	require.NoError(t, json.Unmarshal(spec, &doc2))
+
+	doc3, err := openapi2conv.ToV3(&doc2, openapi2conv.WithTargetVersion("3.1.0"))
+	require.NoError(t, err)
+	require.NoError(t, doc3.Validate(context.Background()))
+
+	pet := doc3.Components.Schemas["Pet"].Value
+	name := pet.Properties["name"].Value
+	require.Equal(t, &openapi3.Types{"string", "null"}, name.Type)
+	require.False(t, name.Nullable)
+	require.Nil(t, name.Example)
+	require.Equal(t, []any{"Rex", "Felix"}, name.Examples)
+	require.NotContains(t, name.Extensions, "x-examples")
+
+	age := pet.Properties["age"].Value
+	require.Nil(t, age.Min)
+	require.Equal(t, 0.0, *age.ExclusiveMin.Value)
+
+	require.NotContains(t, doc3.Extensions, "x-webhooks")
+	require.Contains(t, doc3.Webhooks, "newPet")
+	require.Equal(t, pet, doc3.Webhooks["newPet"].Post.RequestBody.Value.Content["*/*"].Schema.Value)
+
+	_, err = openapi2conv.ToV3(&doc2, openapi2conv.WithTargetVersion("4.0.0"))
+	require.EqualError(t, err, `unsupported target version "4.0.0"`)
+	_, err = openapi2conv.ToV3(&doc2, openapi2conv.WithTargetVersion("3.1"))
+	require.EqualError(t, err, `unsupported target version "3.1"`)
+
+	// The exact version asked for is produced
+	doc3, err = openapi2conv.ToV3(&doc2, openapi2conv.WithTargetVersion("3.0.0"))
+	require.NoError(t, err)
+	require.Equal(t, "3.0.0", doc3.OpenAPI)
+	doc3, err = openapi2conv.ToV3(&doc2, openapi2conv.WithTargetVersion("3.1.1"))
+	require.NoError(t, err)
+	require.Equal(t, "3.1.1", doc3.OpenAPI)
+
+	// Options of FromV3 are rejected
+	var losses []openapi2conv.Loss
+	_, err = openapi2conv.ToV3(&doc2, openapi2conv.WithLosses(&losses))
+	require.EqualError(t, err, "option WithLosses only applies to FromV3")
+	_, err = openapi2conv.FromV3(doc3, openapi2conv.WithTargetVersion("3.1.0"))
+	require.EqualError(t, err, "option WithTargetVersion only applies to ToV3")
+}
+
+func TestToV3TargetVersionOfInvalidDocument(t *testing.T) {
+	spec := []byte(`{
+  "swagger": "2.0",
+  "info": {"title": "Pets", "version": "1.0.0"},
+  "paths": {},
+  "definitions": {
+    "Pet": {"type": "string", "pattern": "["}
+  }
+}`)
+	var doc2 openapi2.T
+	require.NoError(t, json.Unmarshal(spec, &doc2))
+
+	doc3, err := openapi2conv.ToV3(&doc2)
+	require.NoError(t, err)
+	require.Equal(t, "3.0.3", doc3.OpenAPI)
+
+	// Invalid documents are upgraded as well, validating them is left to the caller
+	doc3, err = openapi2conv.ToV3(&doc2, openapi2conv.WithTargetVersion("3.1.0"))
+	require.NoError(t, err)
+	require.Equal(t, "3.1.0", doc3.OpenAPI)
+	require.Error(t, doc3.Validate(context.Background()))
+}
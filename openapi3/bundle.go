@@ -0,0 +1,615 @@
+package openapi3
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/url"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Bundle rewrites a document loaded from several files into a single
+// self-contained document: afterwards no $ref points outside of it.
+//
+// Each $ref is handled according to the location the Loader resolved it to:
+//   - A location in the root document becomes a local "#/..." ref. This also
+//     covers OpenAPI 3.1+ refs to the $id of a schema declared there.
+//   - A location in another document is moved into the components of the
+//     root document, in the collection of the referencing object, and the ref
+//     is rewritten to point at it. Path items are inlined instead, since
+//     components have no pathItems collection.
+//   - A location nested inside another moved location of the same collection,
+//     such as a $defs entry of a moved schema, is rewritten to point into that
+//     component rather than being moved on its own.
+//
+// A root document component whose value is a $ref to another document becomes
+// the home of that location and keeps its name. Other moved locations are
+// named as follows:
+//  1. The base name is the last reference token of the location's fragment or,
+//     when the fragment is empty, the file name without its extensions.
+//     Characters that are not allowed in component names become underscores.
+//  2. Locations are named in ascending order of their absolute URI. A base
+//     name already taken in the collection, by the root document or by an
+//     earlier location, gets the first free suffix among "_2", "_3", ...
+//  3. Locations whose values are identical once their own refs are rewritten
+//     share one component, that of the location named first, and step 2 is
+//     repeated over the remaining locations.
+//
+// The resulting names only depend on the documents, so bundling the same files
+// twice gives the same output.
+//
+// Bundle expects doc to have been loaded by a Loader, which records where each
+// $ref resolves to, and returns an error for a $ref to another document that
+// was not resolved. doc is left unchanged in that case.
+func (doc *T) Bundle() error {
+	b := bundler{
+		doc:     doc,
+		seen:    make(map[any]struct{}),
+		targets: make(map[string]*bundleTarget),
+		used:    make(map[string]map[string]struct{}),
+	}
+	if doc.url != nil {
+		b.root = documentURI(doc.url)
+	}
+	b.document()
+	if b.err != nil {
+		return b.err
+	}
+
+	b.nest()
+	for {
+		b.assignNames()
+		b.rewrite()
+		if !b.dedupe() {
+			break
+		}
+	}
+
+	for _, t := range b.live() {
+		if !t.host {
+			if doc.Components == nil {
+				doc.Components = &Components{}
+			}
+			t.add(doc.Components, t.name)
+		}
+	}
+	for _, site := range b.sites {
+		if site.host {
+			site.setRef("")
+		}
+	}
+	for _, pathItem := range b.pathItems {
+		pathItem.Ref = ""
+	}
+	return nil
+}
+
+// bundleTarget is a location in another document that one or more $refs point to.
+type bundleTarget struct {
+	collection string
+	key        string
+	location   *url.URL
+	value      any
+	add        func(components *Components, name string)
+
+	name string
+	// host is set when a root document component is a $ref to this location.
+	host bool
+	// parent is the outermost target of the same collection this one is nested in.
+	parent *bundleTarget
+	// canonical is the identical target this one was merged into, if any.
+	canonical *bundleTarget
+}
+
+func (t *bundleTarget) resolve() *bundleTarget {
+	for t.canonical != nil {
+		t = t.canonical
+	}
+	return t
+}
+
+type bundleSite struct {
+	// target is nil for a $ref to the root document, which becomes local.
+	target *bundleTarget
+	local  string
+	setRef func(ref string)
+	// host is set for the root document component that names the target.
+	host bool
+}
+
+type bundler struct {
+	doc       *T
+	root      string
+	seen      map[any]struct{}
+	targets   map[string]*bundleTarget
+	sites     []bundleSite
+	pathItems []*PathItem
+	used      map[string]map[string]struct{}
+	err       error
+}
+
+// documentURI returns location without its fragment.
+func documentURI(location *url.URL) string {
+	u := *location
+	u.Fragment, u.RawFragment = "", ""
+	return u.String()
+}
+
+// ref records a $ref found in the document. host is the name of the root
+// document component holding the $ref, if any.
+func (b *bundler) ref(collection, ref string, refPath *url.URL, value any, host string, setRef func(string), add func(*Components, string)) {
+	if ref == "" || b.err != nil {
+		return
+	}
+	if refPath == nil {
+		if !strings.HasPrefix(ref, "#") {
+			b.err = fmt.Errorf("unresolved $ref %q", ref)
+		}
+		return
+	}
+	location := copyURI(refPath)
+	location.Fragment = strings.TrimPrefix(location.Fragment, "#")
+	location.RawFragment = ""
+	if host != "" && !strings.HasPrefix(ref, "#") && documentURI(location) == b.root {
+		// The loader records a single location per resolved value, so a root
+		// component referenced from elsewhere in the root document may carry
+		// its own location rather than that of its $ref.
+		if u, err := new(Loader).resolvePathWithRef(ref, b.doc.url); err == nil {
+			location = u
+		}
+	}
+	if documentURI(location) == b.root {
+		b.sites = append(b.sites, bundleSite{local: "#" + location.Fragment, setRef: setRef})
+		return
+	}
+
+	key := location.String()
+	t, ok := b.targets[collection+" "+key]
+	if !ok {
+		t = &bundleTarget{collection: collection, key: key, location: location, value: value, add: add}
+		b.targets[collection+" "+key] = t
+	}
+	site := bundleSite{target: t, setRef: setRef}
+	if host != "" && !t.host {
+		t.host, t.name, site.host = true, host, true
+	}
+	b.sites = append(b.sites, site)
+}
+
+// visit reports whether v has not been visited yet and marks it visited.
+func (b *bundler) visit(v any) bool {
+	if _, ok := b.seen[v]; ok {
+		return false
+	}
+	b.seen[v] = struct{}{}
+	return true
+}
+
+// nest links each target to the outermost target of the same collection whose
+// location contains it.
+func (b *bundler) nest() {
+	for _, t := range b.targets {
+		for _, p := range b.targets {
+			if p == t || p.collection != t.collection || documentURI(p.location) != documentURI(t.location) {
+				continue
+			}
+			if !strings.HasPrefix(t.location.Fragment, p.location.Fragment+"/") {
+				continue
+			}
+			if t.parent == nil || len(p.location.Fragment) < len(t.parent.location.Fragment) {
+				t.parent = p
+			}
+		}
+	}
+}
+
+// live returns the targets that become components, hosted ones first and the
+// others in ascending order of their location.
+func (b *bundler) live() []*bundleTarget {
+	var targets []*bundleTarget
+	for _, t := range b.targets {
+		if t.parent == nil && t.canonical == nil {
+			targets = append(targets, t)
+		}
+	}
+	slices.SortFunc(targets, func(a, b *bundleTarget) int {
+		if a.host != b.host {
+			if a.host {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Compare(a.key, b.key)
+	})
+	return targets
+}
+
+func (b *bundler) assignNames() {
+	clear(b.used)
+	if c := b.doc.Components; c != nil {
+		b.reserve("schemas", slices.Collect(maps.Keys(c.Schemas)))
+		b.reserve("parameters", slices.Collect(maps.Keys(c.Parameters)))
+		b.reserve("headers", slices.Collect(maps.Keys(c.Headers)))
+		b.reserve("requestBodies", slices.Collect(maps.Keys(c.RequestBodies)))
+		b.reserve("responses", slices.Collect(maps.Keys(c.Responses)))
+		b.reserve("securitySchemes", slices.Collect(maps.Keys(c.SecuritySchemes)))
+		b.reserve("examples", slices.Collect(maps.Keys(c.Examples)))
+		b.reserve("links", slices.Collect(maps.Keys(c.Links)))
+		b.reserve("callbacks", slices.Collect(maps.Keys(c.Callbacks)))
+	}
+	for _, t := range b.live() {
+		if t.host {
+			continue
+		}
+		base := bundleBaseName(t.location)
+		name := base
+		for i := 2; b.isUsed(t.collection, name); i++ {
+			name = base + "_" + strconv.Itoa(i)
+		}
+		b.reserve(t.collection, []string{name})
+		t.name = name
+	}
+}
+
+func (b *bundler) reserve(collection string, names []string) {
+	if b.used[collection] == nil {
+		b.used[collection] = make(map[string]struct{})
+	}
+	for _, name := range names {
+		b.used[collection][name] = struct{}{}
+	}
+}
+
+func (b *bundler) isUsed(collection, name string) bool {
+	_, ok := b.used[collection][name]
+	return ok
+}
+
+// bundleBaseName returns the name a location is given before collisions are resolved.
+func bundleBaseName(location *url.URL) string {
+	var name string
+	if fragment := strings.TrimSuffix(location.Fragment, "/"); fragment != "" {
+		name = unescapeRefString(path.Base(fragment))
+	} else {
+		name = path.Base(location.Path)
+		for ext := path.Ext(name); ext != "" && ext != name; ext = path.Ext(name) {
+			name = strings.TrimSuffix(name, ext)
+		}
+	}
+	name = InvalidIdentifierCharRegExp.ReplaceAllString(name, "_")
+	if name == "" || name == "." || name == "/" {
+		name = "_"
+	}
+	return name
+}
+
+// rewrite points every recorded $ref at the current name of its target.
+func (b *bundler) rewrite() {
+	for _, site := range b.sites {
+		if site.target == nil {
+			site.setRef(site.local)
+			continue
+		}
+		t := site.target.resolve()
+		var pointer string
+		if p := t.parent; p != nil {
+			pointer = strings.TrimPrefix(t.location.Fragment, p.location.Fragment)
+			t = p.resolve()
+		}
+		site.setRef("#/components/" + t.collection + "/" + t.name + pointer)
+	}
+}
+
+// dedupe merges targets whose values are identical and reports whether any was merged.
+func (b *bundler) dedupe() bool {
+	merged := false
+	first := make(map[string]*bundleTarget)
+	for _, t := range b.live() {
+		data, err := json.Marshal(t.value)
+		if err != nil {
+			continue
+		}
+		key := t.collection + " " + string(data)
+		c, ok := first[key]
+		if !ok {
+			first[key] = t
+			continue
+		}
+		if !t.host {
+			t.canonical = c
+			merged = true
+		}
+	}
+	return merged
+}
+
+func (b *bundler) document() {
+	if c := b.doc.Components; c != nil {
+		for _, name := range componentNames(c.Schemas) {
+			b.schemaRef(c.Schemas[name], name)
+		}
+		for _, name := range componentNames(c.Parameters) {
+			b.parameterRef(c.Parameters[name], name)
+		}
+		for _, name := range componentNames(c.Headers) {
+			b.headerRef(c.Headers[name], name)
+		}
+		for _, name := range componentNames(c.RequestBodies) {
+			b.requestBodyRef(c.RequestBodies[name], name)
+		}
+		for _, name := range componentNames(c.Responses) {
+			b.responseRef(c.Responses[name], name)
+		}
+		for _, name := range componentNames(c.SecuritySchemes) {
+			b.securitySchemeRef(c.SecuritySchemes[name], name)
+		}
+		for _, name := range componentNames(c.Examples) {
+			b.exampleRef(c.Examples[name], name)
+		}
+		for _, name := range componentNames(c.Links) {
+			b.linkRef(c.Links[name], name)
+		}
+		for _, name := range componentNames(c.Callbacks) {
+			b.callbackRef(c.Callbacks[name], name)
+		}
+	}
+	if b.doc.Paths != nil {
+		items := b.doc.Paths.Map()
+		for _, name := range componentNames(items) {
+			b.pathItem(items[name])
+		}
+	}
+	for _, name := range componentNames(b.doc.Webhooks) {
+		b.pathItem(b.doc.Webhooks[name])
+	}
+}
+
+func (b *bundler) pathItem(pathItem *PathItem) {
+	if pathItem == nil || !b.visit(pathItem) {
+		return
+	}
+	if pathItem.Ref != "" {
+		b.pathItems = append(b.pathItems, pathItem)
+	}
+	for _, p := range pathItem.Parameters {
+		b.parameterRef(p, "")
+	}
+	operations := pathItem.Operations()
+	for _, method := range componentNames(operations) {
+		op := operations[method]
+		for _, p := range op.Parameters {
+			b.parameterRef(p, "")
+		}
+		b.requestBodyRef(op.RequestBody, "")
+		if op.Responses != nil {
+			responses := op.Responses.Map()
+			for _, code := range componentNames(responses) {
+				b.responseRef(responses[code], "")
+			}
+		}
+		for _, name := range componentNames(op.Callbacks) {
+			b.callbackRef(op.Callbacks[name], "")
+		}
+	}
+}
+
+func (b *bundler) schemaRef(x *SchemaRef, host string) {
+	if x == nil {
+		return
+	}
+	b.ref("schemas", x.Ref, x.RefPath(), x.Value, host, func(ref string) { x.Ref = ref }, func(c *Components, name string) {
+		if c.Schemas == nil {
+			c.Schemas = make(Schemas)
+		}
+		c.Schemas[name] = &SchemaRef{Value: x.Value}
+	})
+	b.schema(x.Value)
+}
+
+func (b *bundler) schema(s *Schema) {
+	if s == nil || !b.visit(s) {
+		return
+	}
+	for _, name := range componentNames(s.Properties) {
+		b.schemaRef(s.Properties[name], "")
+	}
+	for _, list := range []SchemaRefs{s.AllOf, s.AnyOf, s.OneOf, s.PrefixItems} {
+		for _, x := range list {
+			b.schemaRef(x, "")
+		}
+	}
+	for _, x := range []*SchemaRef{
+		s.Items, s.AdditionalProperties.Schema, s.Not, s.Contains, s.PropertyNames,
+		s.UnevaluatedItems.Schema, s.UnevaluatedProperties.Schema,
+		s.If, s.Then, s.Else, s.ContentSchema,
+	} {
+		b.schemaRef(x, "")
+	}
+	for _, name := range componentNames(s.PatternProperties) {
+		b.schemaRef(s.PatternProperties[name], "")
+	}
+	for _, name := range componentNames(s.DependentSchemas) {
+		b.schemaRef(s.DependentSchemas[name], "")
+	}
+	for _, name := range componentNames(s.Defs) {
+		b.schemaRef(s.Defs[name], "")
+	}
+
+	// Discriminator mapping values are plain strings rather than ref objects,
+	// and the loader only resolves those that point to another document.
+	if d := s.Discriminator; d != nil {
+		for _, k := range componentNames(d.Mapping) {
+			m := d.Mapping[k]
+			x := (*SchemaRef)(&m)
+			if x.RefPath() == nil {
+				continue
+			}
+			b.ref("schemas", x.Ref, x.RefPath(), x.Value, "", func(ref string) {
+				m := d.Mapping[k]
+				m.Ref = ref
+				d.Mapping[k] = m
+			}, func(c *Components, name string) {
+				if c.Schemas == nil {
+					c.Schemas = make(Schemas)
+				}
+				c.Schemas[name] = &SchemaRef{Value: x.Value}
+			})
+			b.schema(x.Value)
+		}
+	}
+}
+
+func (b *bundler) parameterRef(x *ParameterRef, host string) {
+	if x == nil {
+		return
+	}
+	b.ref("parameters", x.Ref, x.RefPath(), x.Value, host, func(ref string) { x.Ref = ref }, func(c *Components, name string) {
+		if c.Parameters == nil {
+			c.Parameters = make(ParametersMap)
+		}
+		c.Parameters[name] = &ParameterRef{Value: x.Value}
+	})
+	if x.Value != nil && b.visit(x.Value) {
+		b.parameter(x.Value)
+	}
+}
+
+func (b *bundler) parameter(p *Parameter) {
+	b.schemaRef(p.Schema, "")
+	b.content(p.Content)
+	b.examples(p.Examples)
+}
+
+func (b *bundler) headerRef(x *HeaderRef, host string) {
+	if x == nil {
+		return
+	}
+	b.ref("headers", x.Ref, x.RefPath(), x.Value, host, func(ref string) { x.Ref = ref }, func(c *Components, name string) {
+		if c.Headers == nil {
+			c.Headers = make(Headers)
+		}
+		c.Headers[name] = &HeaderRef{Value: x.Value}
+	})
+	if x.Value != nil && b.visit(x.Value) {
+		b.parameter(&x.Value.Parameter)
+	}
+}
+
+func (b *bundler) requestBodyRef(x *RequestBodyRef, host string) {
+	if x == nil {
+		return
+	}
+	b.ref("requestBodies", x.Ref, x.RefPath(), x.Value, host, func(ref string) { x.Ref = ref }, func(c *Components, name string) {
+		if c.RequestBodies == nil {
+			c.RequestBodies = make(RequestBodies)
+		}
+		c.RequestBodies[name] = &RequestBodyRef{Value: x.Value}
+	})
+	if x.Value != nil && b.visit(x.Value) {
+		b.content(x.Value.Content)
+	}
+}
+
+func (b *bundler) responseRef(x *ResponseRef, host string) {
+	if x == nil {
+		return
+	}
+	b.ref("responses", x.Ref, x.RefPath(), x.Value, host, func(ref string) { x.Ref = ref }, func(c *Components, name string) {
+		if c.Responses == nil {
+			c.Responses = make(ResponseBodies)
+		}
+		c.Responses[name] = &ResponseRef{Value: x.Value}
+	})
+	if x.Value != nil && b.visit(x.Value) {
+		for _, name := range componentNames(x.Value.Headers) {
+			b.headerRef(x.Value.Headers[name], "")
+		}
+		b.content(x.Value.Content)
+		for _, name := range componentNames(x.Value.Links) {
+			b.linkRef(x.Value.Links[name], "")
+		}
+	}
+}
+
+func (b *bundler) content(content Content) {
+	for _, name := range componentNames(content) {
+		mediaType := content[name]
+		if mediaType == nil {
+			continue
+		}
+		b.schemaRef(mediaType.Schema, "")
+		b.schemaRef(mediaType.ItemSchema, "")
+		b.examples(mediaType.Examples)
+		for _, name := range componentNames(mediaType.Encoding) {
+			if encoding := mediaType.Encoding[name]; encoding != nil {
+				for _, name := range componentNames(encoding.Headers) {
+					b.headerRef(encoding.Headers[name], "")
+				}
+			}
+		}
+	}
+}
+
+func (b *bundler) examples(examples Examples) {
+	for _, name := range componentNames(examples) {
+		b.exampleRef(examples[name], "")
+	}
+}
+
+func (b *bundler) securitySchemeRef(x *SecuritySchemeRef, host string) {
+	if x == nil {
+		return
+	}
+	b.ref("securitySchemes", x.Ref, x.RefPath(), x.Value, host, func(ref string) { x.Ref = ref }, func(c *Components, name string) {
+		if c.SecuritySchemes == nil {
+			c.SecuritySchemes = make(SecuritySchemes)
+		}
+		c.SecuritySchemes[name] = &SecuritySchemeRef{Value: x.Value}
+	})
+}
+
+func (b *bundler) exampleRef(x *ExampleRef, host string) {
+	if x == nil {
+		return
+	}
+	b.ref("examples", x.Ref, x.RefPath(), x.Value, host, func(ref string) { x.Ref = ref }, func(c *Components, name string) {
+		if c.Examples == nil {
+			c.Examples = make(Examples)
+		}
+		c.Examples[name] = &ExampleRef{Value: x.Value}
+	})
+}
+
+func (b *bundler) linkRef(x *LinkRef, host string) {
+	if x == nil {
+		return
+	}
+	b.ref("links", x.Ref, x.RefPath(), x.Value, host, func(ref string) { x.Ref = ref }, func(c *Components, name string) {
+		if c.Links == nil {
+			c.Links = make(Links)
+		}
+		c.Links[name] = &LinkRef{Value: x.Value}
+	})
+}
+
+func (b *bundler) callbackRef(x *CallbackRef, host string) {
+	if x == nil {
+		return
+	}
+	b.ref("callbacks", x.Ref, x.RefPath(), x.Value, host, func(ref string) { x.Ref = ref }, func(c *Components, name string) {
+		if c.Callbacks == nil {
+			c.Callbacks = make(Callbacks)
+		}
+		c.Callbacks[name] = &CallbackRef{Value: x.Value}
+	})
+	if x.Value != nil && b.visit(x.Value) {
+		items := x.Value.Map()
+		for _, expr := range componentNames(items) {
+			b.pathItem(items[expr])
+		}
+	}
+}
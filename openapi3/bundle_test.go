@@ -0,0 +1,85 @@
+package openapi3_test
+
+import (
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestBundle(t *testing.T) {
+	ctx := t.Context()
+
+	regexpRef := regexp.MustCompile(`"\$ref":`)
+	regexpRefInternal := regexp.MustCompile(`"\$ref":"#`)
+
+	tests := []struct {
+		filename string
+	}{
+		{"testdata/bundle/openapi.yaml"},
+		{"testdata/testref.openapi.yml"},
+		{"testdata/recursiveRef/openapi.yml"},
+		{"testdata/spec.yaml"},
+		{"testdata/callbacks.yml"},
+		{"testdata/issue831/testref.internalizepath.openapi.yml"},
+		{"testdata/issue959/openapi.yml"},
+		{"testdata/interalizationNameCollision/api.yml"},
+		{"testdata/discriminator.yml"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.filename, func(t *testing.T) {
+			loader := openapi3.NewLoader()
+			loader.IsExternalRefsAllowed = true
+			doc, err := loader.LoadFromFile(test.filename)
+			require.NoError(t, err)
+			require.NoError(t, doc.Validate(ctx))
+
+			require.NoError(t, doc.Bundle())
+			require.NoError(t, doc.Validate(ctx))
+
+			actual, err := doc.MarshalJSON()
+			require.NoError(t, err)
+			require.Len(t, regexpRefInternal.FindAll(actual, -1), len(regexpRef.FindAll(actual, -1)), "checking all references are internal")
+
+			// The bundled document loads without access to other files
+			loader = openapi3.NewLoader()
+			doc2, err := loader.LoadFromData(actual)
+			require.NoError(t, err)
+			require.NoError(t, doc2.Validate(ctx))
+
+			// Bundling is deterministic
+			loader = openapi3.NewLoader()
+			loader.IsExternalRefsAllowed = true
+			doc3, err := loader.LoadFromFile(test.filename)
+			require.NoError(t, err)
+			require.NoError(t, doc3.Bundle())
+			again, err := doc3.MarshalJSON()
+			require.NoError(t, err)
+			require.Equal(t, string(actual), string(again))
+
+			if expected, err := os.ReadFile(test.filename + ".bundled.json"); err == nil {
+				require.JSONEq(t, string(expected), string(actual))
+			}
+		})
+	}
+}
+
+func TestBundleUnresolvedRef(t *testing.T) {
+	doc := &openapi3.T{
+		OpenAPI: "3.0.3",
+		Info:    &openapi3.Info{Title: "t", Version: "1"},
+		Paths:   openapi3.NewPaths(),
+		Components: &openapi3.Components{
+			Schemas: openapi3.Schemas{
+				"Pet": &openapi3.SchemaRef{Ref: "pet.yaml#/Pet"},
+			},
+		},
+	}
+	err := doc.Bundle()
+	require.EqualError(t, err, `unresolved $ref "pet.yaml#/Pet"`)
+	require.Equal(t, "pet.yaml#/Pet", doc.Components.Schemas["Pet"].Ref)
+}
@@ -0,0 +1,1474 @@
+// Code generated by go generate using clone.tmpl; DO NOT EDIT clone.go.
+package openapi3
+
+import (
+	"maps"
+	"slices"
+)
+
+// Clone returns a deep copy of x. A value shared by several references in x is
+// shared by the same references in the copy, so cycles are preserved.
+func (x *T) Clone() *T {
+	return newCloner().cloneT(x)
+}
+
+// Clone returns a deep copy of x. A value shared by several references in x is
+// shared by the same references in the copy, so cycles are preserved.
+func (x *Components) Clone() *Components {
+	return newCloner().cloneComponents(x)
+}
+
+// Clone returns a deep copy of x. A value shared by several references in x is
+// shared by the same references in the copy, so cycles are preserved.
+func (x *Paths) Clone() *Paths {
+	return newCloner().clonePaths(x)
+}
+
+// Clone returns a deep copy of x. A value shared by several references in x is
+// shared by the same references in the copy, so cycles are preserved.
+func (x *PathItem) Clone() *PathItem {
+	return newCloner().clonePathItem(x)
+}
+
+// Clone returns a deep copy of x. A value shared by several references in x is
+// shared by the same references in the copy, so cycles are preserved.
+func (x *Operation) Clone() *Operation {
+	return newCloner().cloneOperation(x)
+}
+
+// Clone returns a deep copy of x. A value shared by several references in x is
+// shared by the same references in the copy, so cycles are preserved.
+func (x *Responses) Clone() *Responses {
+	return newCloner().cloneResponses(x)
+}
+
+// Clone returns a deep copy of x. A value shared by several references in x is
+// shared by the same references in the copy, so cycles are preserved.
+func (x *Callback) Clone() *Callback {
+	return newCloner().cloneCallback(x)
+}
+
+// Clone returns a deep copy of x. A value shared by several references in x is
+// shared by the same references in the copy, so cycles are preserved.
+func (x *CallbackRef) Clone() *CallbackRef {
+	return newCloner().cloneCallbackRef(x)
+}
+
+// Clone returns a deep copy of x. A value shared by several references in x is
+// shared by the same references in the copy, so cycles are preserved.
+func (x *Example) Clone() *Example {
+	return newCloner().cloneExample(x)
+}
+
+// Clone returns a deep copy of x. A value shared by several references in x is
+// shared by the same references in the copy, so cycles are preserved.
+func (x *ExampleRef) Clone() *ExampleRef {
+	return newCloner().cloneExampleRef(x)
+}
+
+// Clone returns a deep copy of x. A value shared by several references in x is
+// shared by the same references in the copy, so cycles are preserved.
+func (x *Header) Clone() *Header {
+	return newCloner().cloneHeader(x)
+}
+
+// Clone returns a deep copy of x. A value shared by several references in x is
+// shared by the same references in the copy, so cycles are preserved.
+func (x *HeaderRef) Clone() *HeaderRef {
+	return newCloner().cloneHeaderRef(x)
+}
+
+// Clone returns a deep copy of x. A value shared by several references in x is
+// shared by the same references in the copy, so cycles are preserved.
+func (x *Link) Clone() *Link {
+	return newCloner().cloneLink(x)
+}
+
+// Clone returns a deep copy of x. A value shared by several references in x is
+// shared by the same references in the copy, so cycles are preserved.
+func (x *LinkRef) Clone() *LinkRef {
+	return newCloner().cloneLinkRef(x)
+}
+
+// Clone returns a deep copy of x. A value shared by several references in x is
+// shared by the same references in the copy, so cycles are preserved.
+func (x *Parameter) Clone() *Parameter {
+	return newCloner().cloneParameter(x)
+}
+
+// Clone returns a deep copy of x. A value shared by several references in x is
+// shared by the same references in the copy, so cycles are preserved.
+func (x *ParameterRef) Clone() *ParameterRef {
+	return newCloner().cloneParameterRef(x)
+}
+
+// Clone returns a deep copy of x. A value shared by several references in x is
+// shared by the same references in the copy, so cycles are preserved.
+func (x *RequestBody) Clone() *RequestBody {
+	return newCloner().cloneRequestBody(x)
+}
+
+// Clone returns a deep copy of x. A value shared by several references in x is
+// shared by the same references in the copy, so cycles are preserved.
+func (x *RequestBodyRef) Clone() *RequestBodyRef {
+	return newCloner().cloneRequestBodyRef(x)
+}
+
+// Clone returns a deep copy of x. A value shared by several references in x is
+// shared by the same references in the copy, so cycles are preserved.
+func (x *Response) Clone() *Response {
+	return newCloner().cloneResponse(x)
+}
+
+// Clone returns a deep copy of x. A value shared by several references in x is
+// shared by the same references in the copy, so cycles are preserved.
+func (x *ResponseRef) Clone() *ResponseRef {
+	return newCloner().cloneResponseRef(x)
+}
+
+// Clone returns a deep copy of x. A value shared by several references in x is
+// shared by the same references in the copy, so cycles are preserved.
+func (x *Schema) Clone() *Schema {
+	return newCloner().cloneSchema(x)
+}
+
+// Clone returns a deep copy of x. A value shared by several references in x is
+// shared by the same references in the copy, so cycles are preserved.
+func (x *SchemaRef) Clone() *SchemaRef {
+	return newCloner().cloneSchemaRef(x)
+}
+
+// Clone returns a deep copy of x. A value shared by several references in x is
+// shared by the same references in the copy, so cycles are preserved.
+func (x *SecurityScheme) Clone() *SecurityScheme {
+	return newCloner().cloneSecurityScheme(x)
+}
+
+// Clone returns a deep copy of x. A value shared by several references in x is
+// shared by the same references in the copy, so cycles are preserved.
+func (x *SecuritySchemeRef) Clone() *SecuritySchemeRef {
+	return newCloner().cloneSecuritySchemeRef(x)
+}
+
+// cloner deep copies a tree of values, mapping each pointer of the original
+// to its copy so that shared and cyclic pointers keep their identity.
+type cloner struct {
+	copies map[any]any
+}
+
+func newCloner() *cloner {
+	return &cloner{copies: make(map[any]any)}
+}
+
+// clonePtr copies a pointer to a value that holds no references.
+func clonePtr[V any](x *V) *V {
+	if x == nil {
+		return nil
+	}
+	y := *x
+	return &y
+}
+
+// cloneAny deep copies the maps and slices of decoded JSON values.
+func cloneAny(x any) any {
+	switch x := x.(type) {
+	case map[string]any:
+		if x == nil {
+			return x
+		}
+		y := make(map[string]any, len(x))
+		for k, v := range x {
+			y[k] = cloneAny(v)
+		}
+		return y
+	case []any:
+		if x == nil {
+			return x
+		}
+		y := make([]any, len(x))
+		for i, v := range x {
+			y[i] = cloneAny(v)
+		}
+		return y
+	}
+	return x
+}
+
+func (c *cloner) copyBoolSchema(x BoolSchema) BoolSchema {
+	y := x
+	y.Has = clonePtr(x.Has)
+	y.Schema = c.cloneSchemaRef(x.Schema)
+	return y
+}
+
+func (c *cloner) cloneCallback(x *Callback) *Callback {
+	if x == nil {
+		return nil
+	}
+	if y, ok := c.copies[x]; ok {
+		return y.(*Callback)
+	}
+	y := new(Callback)
+	c.copies[x] = y
+	*y = c.copyCallback(*x)
+	return y
+}
+
+func (c *cloner) copyCallback(x Callback) Callback {
+	y := x
+	y.Extensions = c.cloneAnyMap(x.Extensions)
+	y.Origin = c.cloneOrigin(x.Origin)
+	y.m = c.clonePathItemMap(x.m)
+	return y
+}
+
+func (c *cloner) cloneCallbackRef(x *CallbackRef) *CallbackRef {
+	if x == nil {
+		return nil
+	}
+	if y, ok := c.copies[x]; ok {
+		return y.(*CallbackRef)
+	}
+	y := new(CallbackRef)
+	c.copies[x] = y
+	*y = c.copyCallbackRef(*x)
+	return y
+}
+
+func (c *cloner) copyCallbackRef(x CallbackRef) CallbackRef {
+	y := x
+	y.Extensions = c.cloneAnyMap(x.Extensions)
+	y.Origin = c.cloneOrigin(x.Origin)
+	y.Summary = clonePtr(x.Summary)
+	y.Description = clonePtr(x.Description)
+	y.Value = c.cloneCallback(x.Value)
+	y.extra = slices.Clone(x.extra)
+	y.refPath = copyURI(x.refPath)
+	return y
+}
+
+func (c *cloner) cloneComponents(x *Components) *Components {
+	if x == nil {
+		return nil
+	}
+	if y, ok := c.copies[x]; ok {
+		return y.(*Components)
+	}
+	y := new(Components)
+	c.copies[x] = y
+	*y = c.copyComponents(*x)
+	return y
+}
+
+func (c *cloner) copyComponents(x Components) Components {
+	y := x
+	y.Extensions = c.cloneAnyMap(x.Extensions)
+	y.Origin = c.cloneOrigin(x.Origin)
+	y.Schemas = c.cloneSchemas(x.Schemas)
+	y.Parameters = c.cloneParametersMap(x.Parameters)
+	y.Headers = c.cloneHeaders(x.Headers)
+	y.RequestBodies = c.cloneRequestBodies(x.RequestBodies)
+	y.Responses = c.cloneResponseBodies(x.Responses)
+	y.SecuritySchemes = c.cloneSecuritySchemes(x.SecuritySchemes)
+	y.Examples = c.cloneExamples(x.Examples)
+	y.Links = c.cloneLinks(x.Links)
+	y.Callbacks = c.cloneCallbacks(x.Callbacks)
+	return y
+}
+
+func (c *cloner) cloneContact(x *Contact) *Contact {
+	if x == nil {
+		return nil
+	}
+	if y, ok := c.copies[x]; ok {
+		return y.(*Contact)
+	}
+	y := new(Contact)
+	c.copies[x] = y
+	*y = c.copyContact(*x)
+	return y
+}
+
+func (c *cloner) copyContact(x Contact) Contact {
+	y := x
+	y.Extensions = c.cloneAnyMap(x.Extensions)
+	y.Origin = c.cloneOrigin(x.Origin)
+	return y
+}
+
+func (c *cloner) cloneDiscriminator(x *Discriminator) *Discriminator {
+	if x == nil {
+		return nil
+	}
+	if y, ok := c.copies[x]; ok {
+		return y.(*Discriminator)
+	}
+	y := new(Discriminator)
+	c.copies[x] = y
+	*y = c.copyDiscriminator(*x)
+	return y
+}
+
+func (c *cloner) copyDiscriminator(x Discriminator) Discriminator {
+	y := x
+	y.Extensions = c.cloneAnyMap(x.Extensions)
+	y.Origin = c.cloneOrigin(x.Origin)
+	y.Mapping = c.cloneMappingRefMap(x.Mapping)
+	return y
+}
+
+func (c *cloner) cloneEncoding(x *Encoding) *Encoding {
+	if x == nil {
+		return nil
+	}
+	if y, ok := c.copies[x]; ok {
+		return y.(*Encoding)
+	}
+	y := new(Encoding)
+	c.copies[x] = y
+	*y = c.copyEncoding(*x)
+	return y
+}
+
+func (c *cloner) copyEncoding(x Encoding) Encoding {
+	y := x
+	y.Extensions = c.cloneAnyMap(x.Extensions)
+	y.Origin = c.cloneOrigin(x.Origin)
+	y.Headers = c.cloneHeaders(x.Headers)
+	y.Explode = clonePtr(x.Explode)
+	return y
+}
+
+func (c *cloner) cloneExample(x *Example) *Example {
+	if x == nil {
+		return nil
+	}
+	if y, ok := c.copies[x]; ok {
+		return y.(*Example)
+	}
+	y := new(Example)
+	c.copies[x] = y
+	*y = c.copyExample(*x)
+	return y
+}
+
+func (c *cloner) copyExample(x Example) Example {
+	y := x
+	y.Extensions = c.cloneAnyMap(x.Extensions)
+	y.Origin = c.cloneOrigin(x.Origin)
+	y.Value = cloneAny(x.Value)
+	y.external = c.cloneExternalValue(x.external)
+	return y
+}
+
+func (c *cloner) cloneExampleRef(x *ExampleRef) *ExampleRef {
+	if x == nil {
+		return nil
+	}
+	if y, ok := c.copies[x]; ok {
+		return y.(*ExampleRef)
+	}
+	y := new(ExampleRef)
+	c.copies[x] = y
+	*y = c.copyExampleRef(*x)
+	return y
+}
+
+func (c *cloner) copyExampleRef(x ExampleRef) ExampleRef {
+	y := x
+	y.Extensions = c.cloneAnyMap(x.Extensions)
+	y.Origin = c.cloneOrigin(x.Origin)
+	y.Summary = clonePtr(x.Summary)
+	y.Description = clonePtr(x.Description)
+	y.Value = c.cloneExample(x.Value)
+	y.extra = slices.Clone(x.extra)
+	y.refPath = copyURI(x.refPath)
+	return y
+}
+
+func (c *cloner) copyExclusiveBound(x ExclusiveBound) ExclusiveBound {
+	y := x
+	y.Bool = clonePtr(x.Bool)
+	y.Value = clonePtr(x.Value)
+	return y
+}
+
+func (c *cloner) cloneExternalDocs(x *ExternalDocs) *ExternalDocs {
+	if x == nil {
+		return nil
+	}
+	if y, ok := c.copies[x]; ok {
+		return y.(*ExternalDocs)
+	}
+	y := new(ExternalDocs)
+	c.copies[x] = y
+	*y = c.copyExternalDocs(*x)
+	return y
+}
+
+func (c *cloner) copyExternalDocs(x ExternalDocs) ExternalDocs {
+	y := x
+	y.Extensions = c.cloneAnyMap(x.Extensions)
+	y.Origin = c.cloneOrigin(x.Origin)
+	return y
+}
+
+func (c *cloner) cloneHeader(x *Header) *Header {
+	if x == nil {
+		return nil
+	}
+	if y, ok := c.copies[x]; ok {
+		return y.(*Header)
+	}
+	y := new(Header)
+	c.copies[x] = y
+	*y = c.copyHeader(*x)
+	return y
+}
+
+func (c *cloner) copyHeader(x Header) Header {
+	y := x
+	y.Parameter = c.copyParameter(x.Parameter)
+	return y
+}
+
+func (c *cloner) cloneHeaderRef(x *HeaderRef) *HeaderRef {
+	if x == nil {
+		return nil
+	}
+	if y, ok := c.copies[x]; ok {
+		return y.(*HeaderRef)
+	}
+	y := new(HeaderRef)
+	c.copies[x] = y
+	*y = c.copyHeaderRef(*x)
+	return y
+}
+
+func (c *cloner) copyHeaderRef(x HeaderRef) HeaderRef {
+	y := x
+	y.Extensions = c.cloneAnyMap(x.Extensions)
+	y.Origin = c.cloneOrigin(x.Origin)
+	y.Summary = clonePtr(x.Summary)
+	y.Description = clonePtr(x.Description)
+	y.Value = c.cloneHeader(x.Value)
+	y.extra = slices.Clone(x.extra)
+	y.refPath = copyURI(x.refPath)
+	return y
+}
+
+func (c *cloner) cloneInfo(x *Info) *Info {
+	if x == nil {
+		return nil
+	}
+	if y, ok := c.copies[x]; ok {
+		return y.(*Info)
+	}
+	y := new(Info)
+	c.copies[x] = y
+	*y = c.copyInfo(*x)
+	return y
+}
+
+func (c *cloner) copyInfo(x Info) Info {
+	y := x
+	y.Extensions = c.cloneAnyMap(x.Extensions)
+	y.Origin = c.cloneOrigin(x.Origin)
+	y.Contact = c.cloneContact(x.Contact)
+	y.License = c.cloneLicense(x.License)
+	return y
+}
+
+func (c *cloner) cloneLicense(x *License) *License {
+	if x == nil {
+		return nil
+	}
+	if y, ok := c.copies[x]; ok {
+		return y.(*License)
+	}
+	y := new(License)
+	c.copies[x] = y
+	*y = c.copyLicense(*x)
+	return y
+}
+
+func (c *cloner) copyLicense(x License) License {
+	y := x
+	y.Extensions = c.cloneAnyMap(x.Extensions)
+	y.Origin = c.cloneOrigin(x.Origin)
+	return y
+}
+
+func (c *cloner) cloneLink(x *Link) *Link {
+	if x == nil {
+		return nil
+	}
+	if y, ok := c.copies[x]; ok {
+		return y.(*Link)
+	}
+	y := new(Link)
+	c.copies[x] = y
+	*y = c.copyLink(*x)
+	return y
+}
+
+func (c *cloner) copyLink(x Link) Link {
+	y := x
+	y.Extensions = c.cloneAnyMap(x.Extensions)
+	y.Origin = c.cloneOrigin(x.Origin)
+	y.Parameters = c.cloneAnyMap(x.Parameters)
+	y.Server = c.cloneServer(x.Server)
+	y.RequestBody = cloneAny(x.RequestBody)
+	return y
+}
+
+func (c *cloner) cloneLinkRef(x *LinkRef) *LinkRef {
+	if x == nil {
+		return nil
+	}
+	if y, ok := c.copies[x]; ok {
+		return y.(*LinkRef)
+	}
+	y := new(LinkRef)
+	c.copies[x] = y
+	*y = c.copyLinkRef(*x)
+	return y
+}
+
+func (c *cloner) copyLinkRef(x LinkRef) LinkRef {
+	y := x
+	y.Extensions = c.cloneAnyMap(x.Extensions)
+	y.Origin = c.cloneOrigin(x.Origin)
+	y.Summary = clonePtr(x.Summary)
+	y.Description = clonePtr(x.Description)
+	y.Value = c.cloneLink(x.Value)
+	y.extra = slices.Clone(x.extra)
+	y.refPath = copyURI(x.refPath)
+	return y
+}
+
+func (c *cloner) cloneLocation(x *Location) *Location {
+	if x == nil {
+		return nil
+	}
+	if y, ok := c.copies[x]; ok {
+		return y.(*Location)
+	}
+	y := new(Location)
+	c.copies[x] = y
+	*y = *x
+	return y
+}
+
+func (c *cloner) copyMappingRef(x MappingRef) MappingRef {
+	y := x
+	y.Extensions = c.cloneAnyMap(x.Extensions)
+	y.Origin = c.cloneOrigin(x.Origin)
+	y.Value = c.cloneSchema(x.Value)
+	y.extra = slices.Clone(x.extra)
+	y.sibling = c.cloneSchema(x.sibling)
+	y.refPath = copyURI(x.refPath)
+	return y
+}
+
+func (c *cloner) cloneMediaType(x *MediaType) *MediaType {
+	if x == nil {
+		return nil
+	}
+	if y, ok := c.copies[x]; ok {
+		return y.(*MediaType)
+	}
+	y := new(MediaType)
+	c.copies[x] = y
+	*y = c.copyMediaType(*x)
+	return y
+}
+
+func (c *cloner) copyMediaType(x MediaType) MediaType {
+	y := x
+	y.Extensions = c.cloneAnyMap(x.Extensions)
+	y.Origin = c.cloneOrigin(x.Origin)
+	y.Schema = c.cloneSchemaRef(x.Schema)
+	y.ItemSchema = c.cloneSchemaRef(x.ItemSchema)
+	y.Example = cloneAny(x.Example)
+	y.Examples = c.cloneExamples(x.Examples)
+	y.Encoding = c.cloneEncodings(x.Encoding)
+	return y
+}
+
+func (c *cloner) cloneOAuthFlow(x *OAuthFlow) *OAuthFlow {
+	if x == nil {
+		return nil
+	}
+	if y, ok := c.copies[x]; ok {
+		return y.(*OAuthFlow)
+	}
+	y := new(OAuthFlow)
+	c.copies[x] = y
+	*y = c.copyOAuthFlow(*x)
+	return y
+}
+
+func (c *cloner) copyOAuthFlow(x OAuthFlow) OAuthFlow {
+	y := x
+	y.Extensions = c.cloneAnyMap(x.Extensions)
+	y.Origin = c.cloneOrigin(x.Origin)
+	y.Scopes = maps.Clone(x.Scopes)
+	return y
+}
+
+func (c *cloner) cloneOAuthFlows(x *OAuthFlows) *OAuthFlows {
+	if x == nil {
+		return nil
+	}
+	if y, ok := c.copies[x]; ok {
+		return y.(*OAuthFlows)
+	}
+	y := new(OAuthFlows)
+	c.copies[x] = y
+	*y = c.copyOAuthFlows(*x)
+	return y
+}
+
+func (c *cloner) copyOAuthFlows(x OAuthFlows) OAuthFlows {
+	y := x
+	y.Extensions = c.cloneAnyMap(x.Extensions)
+	y.Origin = c.cloneOrigin(x.Origin)
+	y.Implicit = c.cloneOAuthFlow(x.Implicit)
+	y.Password = c.cloneOAuthFlow(x.Password)
+	y.ClientCredentials = c.cloneOAuthFlow(x.ClientCredentials)
+	y.AuthorizationCode = c.cloneOAuthFlow(x.AuthorizationCode)
+	return y
+}
+
+func (c *cloner) cloneOperation(x *Operation) *Operation {
+	if x == nil {
+		return nil
+	}
+	if y, ok := c.copies[x]; ok {
+		return y.(*Operation)
+	}
+	y := new(Operation)
+	c.copies[x] = y
+	*y = c.copyOperation(*x)
+	return y
+}
+
+func (c *cloner) copyOperation(x Operation) Operation {
+	y := x
+	y.Extensions = c.cloneAnyMap(x.Extensions)
+	y.Origin = c.cloneOrigin(x.Origin)
+	y.Tags = slices.Clone(x.Tags)
+	y.Parameters = c.cloneParameters(x.Parameters)
+	y.RequestBody = c.cloneRequestBodyRef(x.RequestBody)
+	y.Responses = c.cloneResponses(x.Responses)
+	y.Callbacks = c.cloneCallbacks(x.Callbacks)
+	y.Security = c.cloneSecurityRequirementsPtr(x.Security)
+	y.Servers = c.cloneServersPtr(x.Servers)
+	y.ExternalDocs = c.cloneExternalDocs(x.ExternalDocs)
+	return y
+}
+
+func (c *cloner) cloneOrigin(x *Origin) *Origin {
+	if x == nil {
+		return nil
+	}
+	if y, ok := c.copies[x]; ok {
+		return y.(*Origin)
+	}
+	y := new(Origin)
+	c.copies[x] = y
+	*y = c.copyOrigin(*x)
+	return y
+}
+
+func (c *cloner) copyOrigin(x Origin) Origin {
+	y := x
+	y.Key = c.cloneLocation(x.Key)
+	y.Fields = maps.Clone(x.Fields)
+	y.Sequences = c.cloneLocationSliceMap(x.Sequences)
+	return y
+}
+
+func (c *cloner) cloneParameter(x *Parameter) *Parameter {
+	if x == nil {
+		return nil
+	}
+	if y, ok := c.copies[x]; ok {
+		return y.(*Parameter)
+	}
+	y := new(Parameter)
+	c.copies[x] = y
+	*y = c.copyParameter(*x)
+	return y
+}
+
+func (c *cloner) copyParameter(x Parameter) Parameter {
+	y := x
+	y.Extensions = c.cloneAnyMap(x.Extensions)
+	y.Origin = c.cloneOrigin(x.Origin)
+	y.Explode = clonePtr(x.Explode)
+	y.Schema = c.cloneSchemaRef(x.Schema)
+	y.Example = cloneAny(x.Example)
+	y.Examples = c.cloneExamples(x.Examples)
+	y.Content = c.cloneContent(x.Content)
+	return y
+}
+
+func (c *cloner) cloneParameterRef(x *ParameterRef) *ParameterRef {
+	if x == nil {
+		return nil
+	}
+	if y, ok := c.copies[x]; ok {
+		return y.(*ParameterRef)
+	}
+	y := new(ParameterRef)
+	c.copies[x] = y
+	*y = c.copyParameterRef(*x)
+	return y
+}
+
+func (c *cloner) copyParameterRef(x ParameterRef) ParameterRef {
+	y := x
+	y.Extensions = c.cloneAnyMap(x.Extensions)
+	y.Origin = c.cloneOrigin(x.Origin)
+	y.Summary = clonePtr(x.Summary)
+	y.Description = clonePtr(x.Description)
+	y.Value = c.cloneParameter(x.Value)
+	y.extra = slices.Clone(x.extra)
+	y.refPath = copyURI(x.refPath)
+	return y
+}
+
+func (c *cloner) clonePathItem(x *PathItem) *PathItem {
+	if x == nil {
+		return nil
+	}
+	if y, ok := c.copies[x]; ok {
+		return y.(*PathItem)
+	}
+	y := new(PathItem)
+	c.copies[x] = y
+	*y = c.copyPathItem(*x)
+	return y
+}
+
+func (c *cloner) copyPathItem(x PathItem) PathItem {
+	y := x
+	y.Extensions = c.cloneAnyMap(x.Extensions)
+	y.Origin = c.cloneOrigin(x.Origin)
+	y.Connect = c.cloneOperation(x.Connect)
+	y.Delete = c.cloneOperation(x.Delete)
+	y.Get = c.cloneOperation(x.Get)
+	y.Head = c.cloneOperation(x.Head)
+	y.Options = c.cloneOperation(x.Options)
+	y.Patch = c.cloneOperation(x.Patch)
+	y.Post = c.cloneOperation(x.Post)
+	y.Put = c.cloneOperation(x.Put)
+	y.Trace = c.cloneOperation(x.Trace)
+	y.Servers = c.cloneServers(x.Servers)
+	y.Parameters = c.cloneParameters(x.Parameters)
+	return y
+}
+
+func (c *cloner) clonePaths(x *Paths) *Paths {
+	if x == nil {
+		return nil
+	}
+	if y, ok := c.copies[x]; ok {
+		return y.(*Paths)
+	}
+	y := new(Paths)
+	c.copies[x] = y
+	*y = c.copyPaths(*x)
+	return y
+}
+
+func (c *cloner) copyPaths(x Paths) Paths {
+	y := x
+	y.Extensions = c.cloneAnyMap(x.Extensions)
+	y.Origin = c.cloneOrigin(x.Origin)
+	y.m = c.clonePathItemMap(x.m)
+	return y
+}
+
+func (c *cloner) cloneRequestBody(x *RequestBody) *RequestBody {
+	if x == nil {
+		return nil
+	}
+	if y, ok := c.copies[x]; ok {
+		return y.(*RequestBody)
+	}
+	y := new(RequestBody)
+	c.copies[x] = y
+	*y = c.copyRequestBody(*x)
+	return y
+}
+
+func (c *cloner) copyRequestBody(x RequestBody) RequestBody {
+	y := x
+	y.Extensions = c.cloneAnyMap(x.Extensions)
+	y.Origin = c.cloneOrigin(x.Origin)
+	y.Content = c.cloneContent(x.Content)
+	return y
+}
+
+func (c *cloner) cloneRequestBodyRef(x *RequestBodyRef) *RequestBodyRef {
+	if x == nil {
+		return nil
+	}
+	if y, ok := c.copies[x]; ok {
+		return y.(*RequestBodyRef)
+	}
+	y := new(RequestBodyRef)
+	c.copies[x] = y
+	*y = c.copyRequestBodyRef(*x)
+	return y
+}
+
+func (c *cloner) copyRequestBodyRef(x RequestBodyRef) RequestBodyRef {
+	y := x
+	y.Extensions = c.cloneAnyMap(x.Extensions)
+	y.Origin = c.cloneOrigin(x.Origin)
+	y.Summary = clonePtr(x.Summary)
+	y.Description = clonePtr(x.Description)
+	y.Value = c.cloneRequestBody(x.Value)
+	y.extra = slices.Clone(x.extra)
+	y.refPath = copyURI(x.refPath)
+	return y
+}
+
+func (c *cloner) cloneResponse(x *Response) *Response {
+	if x == nil {
+		return nil
+	}
+	if y, ok := c.copies[x]; ok {
+		return y.(*Response)
+	}
+	y := new(Response)
+	c.copies[x] = y
+	*y = c.copyResponse(*x)
+	return y
+}
+
+func (c *cloner) copyResponse(x Response) Response {
+	y := x
+	y.Extensions = c.cloneAnyMap(x.Extensions)
+	y.Origin = c.cloneOrigin(x.Origin)
+	y.Description = clonePtr(x.Description)
+	y.Headers = c.cloneHeaders(x.Headers)
+	y.Content = c.cloneContent(x.Content)
+	y.Links = c.cloneLinks(x.Links)
+	return y
+}
+
+func (c *cloner) cloneResponseRef(x *ResponseRef) *ResponseRef {
+	if x == nil {
+		return nil
+	}
+	if y, ok := c.copies[x]; ok {
+		return y.(*ResponseRef)
+	}
+	y := new(ResponseRef)
+	c.copies[x] = y
+	*y = c.copyResponseRef(*x)
+	return y
+}
+
+func (c *cloner) copyResponseRef(x ResponseRef) ResponseRef {
+	y := x
+	y.Extensions = c.cloneAnyMap(x.Extensions)
+	y.Origin = c.cloneOrigin(x.Origin)
+	y.Summary = clonePtr(x.Summary)
+	y.Description = clonePtr(x.Description)
+	y.Value = c.cloneResponse(x.Value)
+	y.extra = slices.Clone(x.extra)
+	y.refPath = copyURI(x.refPath)
+	return y
+}
+
+func (c *cloner) cloneResponses(x *Responses) *Responses {
+	if x == nil {
+		return nil
+	}
+	if y, ok := c.copies[x]; ok {
+		return y.(*Responses)
+	}
+	y := new(Responses)
+	c.copies[x] = y
+	*y = c.copyResponses(*x)
+	return y
+}
+
+func (c *cloner) copyResponses(x Responses) Responses {
+	y := x
+	y.Extensions = c.cloneAnyMap(x.Extensions)
+	y.Origin = c.cloneOrigin(x.Origin)
+	y.m = c.cloneResponseRefMap(x.m)
+	return y
+}
+
+func (c *cloner) cloneSchema(x *Schema) *Schema {
+	if x == nil {
+		return nil
+	}
+	if y, ok := c.copies[x]; ok {
+		return y.(*Schema)
+	}
+	y := new(Schema)
+	c.copies[x] = y
+	*y = c.copySchema(*x)
+	return y
+}
+
+func (c *cloner) copySchema(x Schema) Schema {
+	y := x
+	y.Extensions = c.cloneAnyMap(x.Extensions)
+	y.Origin = c.cloneOrigin(x.Origin)
+	y.OneOf = c.cloneSchemaRefs(x.OneOf)
+	y.AnyOf = c.cloneSchemaRefs(x.AnyOf)
+	y.AllOf = c.cloneSchemaRefs(x.AllOf)
+	y.Not = c.cloneSchemaRef(x.Not)
+	y.Type = c.cloneTypesPtr(x.Type)
+	y.Enum = c.cloneAnySlice(x.Enum)
+	y.Default = cloneAny(x.Default)
+	y.Example = cloneAny(x.Example)
+	y.ExternalDocs = c.cloneExternalDocs(x.ExternalDocs)
+	y.ExclusiveMin = c.copyExclusiveBound(x.ExclusiveMin)
+	y.ExclusiveMax = c.copyExclusiveBound(x.ExclusiveMax)
+	y.XML = c.cloneXML(x.XML)
+	y.Min = clonePtr(x.Min)
+	y.Max = clonePtr(x.Max)
+	y.MultipleOf = clonePtr(x.MultipleOf)
+	y.MaxLength = clonePtr(x.MaxLength)
+	y.MaxItems = clonePtr(x.MaxItems)
+	y.Items = c.cloneSchemaRef(x.Items)
+	y.Required = slices.Clone(x.Required)
+	y.Properties = c.cloneSchemas(x.Properties)
+	y.MaxProps = clonePtr(x.MaxProps)
+	y.AdditionalProperties = c.copyBoolSchema(x.AdditionalProperties)
+	y.Discriminator = c.cloneDiscriminator(x.Discriminator)
+	y.Const = cloneAny(x.Const)
+	y.Examples = c.cloneAnySlice(x.Examples)
+	y.PrefixItems = c.cloneSchemaRefs(x.PrefixItems)
+	y.Contains = c.cloneSchemaRef(x.Contains)
+	y.MinContains = clonePtr(x.MinContains)
+	y.MaxContains = clonePtr(x.MaxContains)
+	y.PatternProperties = c.cloneSchemas(x.PatternProperties)
+	y.DependentSchemas = c.cloneSchemas(x.DependentSchemas)
+	y.PropertyNames = c.cloneSchemaRef(x.PropertyNames)
+	y.UnevaluatedItems = c.copyBoolSchema(x.UnevaluatedItems)
+	y.UnevaluatedProperties = c.copyBoolSchema(x.UnevaluatedProperties)
+	y.If = c.cloneSchemaRef(x.If)
+	y.Then = c.cloneSchemaRef(x.Then)
+	y.Else = c.cloneSchemaRef(x.Else)
+	y.DependentRequired = c.clonestringSliceMap(x.DependentRequired)
+	y.Defs = c.cloneSchemas(x.Defs)
+	y.ContentSchema = c.cloneSchemaRef(x.ContentSchema)
+	return y
+}
+
+func (c *cloner) cloneSchemaRef(x *SchemaRef) *SchemaRef {
+	if x == nil {
+		return nil
+	}
+	if y, ok := c.copies[x]; ok {
+		return y.(*SchemaRef)
+	}
+	y := new(SchemaRef)
+	c.copies[x] = y
+	*y = c.copySchemaRef(*x)
+	return y
+}
+
+func (c *cloner) copySchemaRef(x SchemaRef) SchemaRef {
+	y := x
+	y.Extensions = c.cloneAnyMap(x.Extensions)
+	y.Origin = c.cloneOrigin(x.Origin)
+	y.Value = c.cloneSchema(x.Value)
+	y.extra = slices.Clone(x.extra)
+	y.sibling = c.cloneSchema(x.sibling)
+	y.refPath = copyURI(x.refPath)
+	return y
+}
+
+func (c *cloner) cloneSecurityScheme(x *SecurityScheme) *SecurityScheme {
+	if x == nil {
+		return nil
+	}
+	if y, ok := c.copies[x]; ok {
+		return y.(*SecurityScheme)
+	}
+	y := new(SecurityScheme)
+	c.copies[x] = y
+	*y = c.copySecurityScheme(*x)
+	return y
+}
+
+func (c *cloner) copySecurityScheme(x SecurityScheme) SecurityScheme {
+	y := x
+	y.Extensions = c.cloneAnyMap(x.Extensions)
+	y.Origin = c.cloneOrigin(x.Origin)
+	y.Flows = c.cloneOAuthFlows(x.Flows)
+	return y
+}
+
+func (c *cloner) cloneSecuritySchemeRef(x *SecuritySchemeRef) *SecuritySchemeRef {
+	if x == nil {
+		return nil
+	}
+	if y, ok := c.copies[x]; ok {
+		return y.(*SecuritySchemeRef)
+	}
+	y := new(SecuritySchemeRef)
+	c.copies[x] = y
+	*y = c.copySecuritySchemeRef(*x)
+	return y
+}
+
+func (c *cloner) copySecuritySchemeRef(x SecuritySchemeRef) SecuritySchemeRef {
+	y := x
+	y.Extensions = c.cloneAnyMap(x.Extensions)
+	y.Origin = c.cloneOrigin(x.Origin)
+	y.Summary = clonePtr(x.Summary)
+	y.Description = clonePtr(x.Description)
+	y.Value = c.cloneSecurityScheme(x.Value)
+	y.extra = slices.Clone(x.extra)
+	y.refPath = copyURI(x.refPath)
+	return y
+}
+
+func (c *cloner) cloneServer(x *Server) *Server {
+	if x == nil {
+		return nil
+	}
+	if y, ok := c.copies[x]; ok {
+		return y.(*Server)
+	}
+	y := new(Server)
+	c.copies[x] = y
+	*y = c.copyServer(*x)
+	return y
+}
+
+func (c *cloner) copyServer(x Server) Server {
+	y := x
+	y.Extensions = c.cloneAnyMap(x.Extensions)
+	y.Origin = c.cloneOrigin(x.Origin)
+	y.Variables = c.cloneServerVariables(x.Variables)
+	return y
+}
+
+func (c *cloner) cloneServerVariable(x *ServerVariable) *ServerVariable {
+	if x == nil {
+		return nil
+	}
+	if y, ok := c.copies[x]; ok {
+		return y.(*ServerVariable)
+	}
+	y := new(ServerVariable)
+	c.copies[x] = y
+	*y = c.copyServerVariable(*x)
+	return y
+}
+
+func (c *cloner) copyServerVariable(x ServerVariable) ServerVariable {
+	y := x
+	y.Extensions = c.cloneAnyMap(x.Extensions)
+	y.Origin = c.cloneOrigin(x.Origin)
+	y.Enum = slices.Clone(x.Enum)
+	return y
+}
+
+func (c *cloner) cloneT(x *T) *T {
+	if x == nil {
+		return nil
+	}
+	if y, ok := c.copies[x]; ok {
+		return y.(*T)
+	}
+	y := new(T)
+	c.copies[x] = y
+	*y = c.copyT(*x)
+	return y
+}
+
+func (c *cloner) copyT(x T) T {
+	y := x
+	y.Extensions = c.cloneAnyMap(x.Extensions)
+	y.Origin = c.cloneOrigin(x.Origin)
+	y.Components = c.cloneComponents(x.Components)
+	y.Info = c.cloneInfo(x.Info)
+	y.Paths = c.clonePaths(x.Paths)
+	y.Security = c.cloneSecurityRequirements(x.Security)
+	y.Servers = c.cloneServers(x.Servers)
+	y.Tags = c.cloneTags(x.Tags)
+	y.ExternalDocs = c.cloneExternalDocs(x.ExternalDocs)
+	y.Webhooks = c.clonePathItemMap(x.Webhooks)
+	y.visited = visitedComponent{}
+	y.url = copyURI(x.url)
+	y.stringFormats = maps.Clone(x.stringFormats)
+	y.numberFormats = maps.Clone(x.numberFormats)
+	y.integerFormats = maps.Clone(x.integerFormats)
+	return y
+}
+
+func (c *cloner) cloneTag(x *Tag) *Tag {
+	if x == nil {
+		return nil
+	}
+	if y, ok := c.copies[x]; ok {
+		return y.(*Tag)
+	}
+	y := new(Tag)
+	c.copies[x] = y
+	*y = c.copyTag(*x)
+	return y
+}
+
+func (c *cloner) copyTag(x Tag) Tag {
+	y := x
+	y.Extensions = c.cloneAnyMap(x.Extensions)
+	y.Origin = c.cloneOrigin(x.Origin)
+	y.ExternalDocs = c.cloneExternalDocs(x.ExternalDocs)
+	return y
+}
+
+func (c *cloner) cloneXML(x *XML) *XML {
+	if x == nil {
+		return nil
+	}
+	if y, ok := c.copies[x]; ok {
+		return y.(*XML)
+	}
+	y := new(XML)
+	c.copies[x] = y
+	*y = c.copyXML(*x)
+	return y
+}
+
+func (c *cloner) copyXML(x XML) XML {
+	y := x
+	y.Extensions = c.cloneAnyMap(x.Extensions)
+	y.Origin = c.cloneOrigin(x.Origin)
+	return y
+}
+
+func (c *cloner) cloneExternalValue(x *externalValue) *externalValue {
+	if x == nil {
+		return nil
+	}
+	if y, ok := c.copies[x]; ok {
+		return y.(*externalValue)
+	}
+	y := new(externalValue)
+	c.copies[x] = y
+	*y = c.copyExternalValue(*x)
+	return y
+}
+
+func (c *cloner) copyExternalValue(x externalValue) externalValue {
+	y := x
+	y.location = copyURI(x.location)
+	y.data = slices.Clone(x.data)
+	return y
+}
+
+func (c *cloner) cloneAnyMap(x map[string]interface{}) map[string]interface{} {
+	if x == nil {
+		return nil
+	}
+	y := make(map[string]interface{}, len(x))
+	for k, v := range x {
+		y[k] = cloneAny(v)
+	}
+	return y
+}
+
+func (c *cloner) cloneAnySlice(x []interface{}) []interface{} {
+	if x == nil {
+		return nil
+	}
+	y := make([]interface{}, len(x))
+	for i, v := range x {
+		y[i] = cloneAny(v)
+	}
+	return y
+}
+
+func (c *cloner) cloneCallbacks(x Callbacks) Callbacks {
+	if x == nil {
+		return nil
+	}
+	y := make(Callbacks, len(x))
+	for k, v := range x {
+		y[k] = c.cloneCallbackRef(v)
+	}
+	return y
+}
+
+func (c *cloner) cloneContent(x Content) Content {
+	if x == nil {
+		return nil
+	}
+	y := make(Content, len(x))
+	for k, v := range x {
+		y[k] = c.cloneMediaType(v)
+	}
+	return y
+}
+
+func (c *cloner) cloneEncodings(x Encodings) Encodings {
+	if x == nil {
+		return nil
+	}
+	y := make(Encodings, len(x))
+	for k, v := range x {
+		y[k] = c.cloneEncoding(v)
+	}
+	return y
+}
+
+func (c *cloner) cloneExamples(x Examples) Examples {
+	if x == nil {
+		return nil
+	}
+	y := make(Examples, len(x))
+	for k, v := range x {
+		y[k] = c.cloneExampleRef(v)
+	}
+	return y
+}
+
+func (c *cloner) cloneHeaders(x Headers) Headers {
+	if x == nil {
+		return nil
+	}
+	y := make(Headers, len(x))
+	for k, v := range x {
+		y[k] = c.cloneHeaderRef(v)
+	}
+	return y
+}
+
+func (c *cloner) cloneLinks(x Links) Links {
+	if x == nil {
+		return nil
+	}
+	y := make(Links, len(x))
+	for k, v := range x {
+		y[k] = c.cloneLinkRef(v)
+	}
+	return y
+}
+
+func (c *cloner) cloneLocationSliceMap(x map[string][]Location) map[string][]Location {
+	if x == nil {
+		return nil
+	}
+	y := make(map[string][]Location, len(x))
+	for k, v := range x {
+		y[k] = slices.Clone(v)
+	}
+	return y
+}
+
+func (c *cloner) cloneMappingRefMap(x map[string]MappingRef) map[string]MappingRef {
+	if x == nil {
+		return nil
+	}
+	y := make(map[string]MappingRef, len(x))
+	for k, v := range x {
+		y[k] = c.copyMappingRef(v)
+	}
+	return y
+}
+
+func (c *cloner) cloneParameters(x Parameters) Parameters {
+	if x == nil {
+		return nil
+	}
+	y := make(Parameters, len(x))
+	for i, v := range x {
+		y[i] = c.cloneParameterRef(v)
+	}
+	return y
+}
+
+func (c *cloner) cloneParametersMap(x ParametersMap) ParametersMap {
+	if x == nil {
+		return nil
+	}
+	y := make(ParametersMap, len(x))
+	for k, v := range x {
+		y[k] = c.cloneParameterRef(v)
+	}
+	return y
+}
+
+func (c *cloner) clonePathItemMap(x map[string]*PathItem) map[string]*PathItem {
+	if x == nil {
+		return nil
+	}
+	y := make(map[string]*PathItem, len(x))
+	for k, v := range x {
+		y[k] = c.clonePathItem(v)
+	}
+	return y
+}
+
+func (c *cloner) cloneRequestBodies(x RequestBodies) RequestBodies {
+	if x == nil {
+		return nil
+	}
+	y := make(RequestBodies, len(x))
+	for k, v := range x {
+		y[k] = c.cloneRequestBodyRef(v)
+	}
+	return y
+}
+
+func (c *cloner) cloneResponseBodies(x ResponseBodies) ResponseBodies {
+	if x == nil {
+		return nil
+	}
+	y := make(ResponseBodies, len(x))
+	for k, v := range x {
+		y[k] = c.cloneResponseRef(v)
+	}
+	return y
+}
+
+func (c *cloner) cloneResponseRefMap(x map[string]*ResponseRef) map[string]*ResponseRef {
+	if x == nil {
+		return nil
+	}
+	y := make(map[string]*ResponseRef, len(x))
+	for k, v := range x {
+		y[k] = c.cloneResponseRef(v)
+	}
+	return y
+}
+
+func (c *cloner) cloneSchemaRefs(x SchemaRefs) SchemaRefs {
+	if x == nil {
+		return nil
+	}
+	y := make(SchemaRefs, len(x))
+	for i, v := range x {
+		y[i] = c.cloneSchemaRef(v)
+	}
+	return y
+}
+
+func (c *cloner) cloneSchemas(x Schemas) Schemas {
+	if x == nil {
+		return nil
+	}
+	y := make(Schemas, len(x))
+	for k, v := range x {
+		y[k] = c.cloneSchemaRef(v)
+	}
+	return y
+}
+
+func (c *cloner) cloneSecurityRequirement(x SecurityRequirement) SecurityRequirement {
+	if x == nil {
+		return nil
+	}
+	y := make(SecurityRequirement, len(x))
+	for k, v := range x {
+		y[k] = slices.Clone(v)
+	}
+	return y
+}
+
+func (c *cloner) cloneSecurityRequirements(x SecurityRequirements) SecurityRequirements {
+	if x == nil {
+		return nil
+	}
+	y := make(SecurityRequirements, len(x))
+	for i, v := range x {
+		y[i] = c.cloneSecurityRequirement(v)
+	}
+	return y
+}
+
+func (c *cloner) cloneSecurityRequirementsPtr(x *SecurityRequirements) *SecurityRequirements {
+	if x == nil {
+		return nil
+	}
+	y := c.cloneSecurityRequirements(*x)
+	return &y
+}
+
+func (c *cloner) cloneSecuritySchemes(x SecuritySchemes) SecuritySchemes {
+	if x == nil {
+		return nil
+	}
+	y := make(SecuritySchemes, len(x))
+	for k, v := range x {
+		y[k] = c.cloneSecuritySchemeRef(v)
+	}
+	return y
+}
+
+func (c *cloner) cloneServerVariables(x ServerVariables) ServerVariables {
+	if x == nil {
+		return nil
+	}
+	y := make(ServerVariables, len(x))
+	for k, v := range x {
+		y[k] = c.cloneServerVariable(v)
+	}
+	return y
+}
+
+func (c *cloner) cloneServers(x Servers) Servers {
+	if x == nil {
+		return nil
+	}
+	y := make(Servers, len(x))
+	for i, v := range x {
+		y[i] = c.cloneServer(v)
+	}
+	return y
+}
+
+func (c *cloner) cloneServersPtr(x *Servers) *Servers {
+	if x == nil {
+		return nil
+	}
+	y := c.cloneServers(*x)
+	return &y
+}
+
+func (c *cloner) cloneTags(x Tags) Tags {
+	if x == nil {
+		return nil
+	}
+	y := make(Tags, len(x))
+	for i, v := range x {
+		y[i] = c.cloneTag(v)
+	}
+	return y
+}
+
+func (c *cloner) cloneTypesPtr(x *Types) *Types {
+	if x == nil {
+		return nil
+	}
+	y := slices.Clone(*x)
+	return &y
+}
+
+func (c *cloner) clonestringSliceMap(x map[string][]string) map[string][]string {
+	if x == nil {
+		return nil
+	}
+	y := make(map[string][]string, len(x))
+	for k, v := range x {
+		y[k] = slices.Clone(v)
+	}
+	return y
+}
@@ -0,0 +1,108 @@
+package openapi3_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// requireNoSharedMemory walks x and y, a deep copy of x, in parallel and fails
+// if any pointer, map or slice of y is also part of x.
+func requireNoSharedMemory(t *testing.T, x, y any) {
+	t.Helper()
+	seen := make(map[uintptr]bool)
+	var walk func(path string, x, y reflect.Value)
+	walk = func(path string, x, y reflect.Value) {
+		switch x.Kind() {
+		case reflect.Pointer, reflect.Map, reflect.Slice:
+			if x.IsNil() || x.Kind() == reflect.Slice && x.Cap() == 0 {
+				return
+			}
+			require.NotEqual(t, x.Pointer(), y.Pointer(), "shared memory at %s", path)
+			if seen[x.Pointer()] {
+				return
+			}
+			seen[x.Pointer()] = true
+		}
+		switch x.Kind() {
+		case reflect.Pointer:
+			walk(path, x.Elem(), y.Elem())
+		case reflect.Interface:
+			if !x.IsNil() && x.NumMethod() == 0 {
+				walk(path, x.Elem(), y.Elem())
+			}
+		case reflect.Slice:
+			for i := range x.Len() {
+				walk(path+"/"+reflect.ValueOf(i).String(), x.Index(i), y.Index(i))
+			}
+		case reflect.Map:
+			for _, k := range x.MapKeys() {
+				walk(path+"/"+k.String(), x.MapIndex(k), y.MapIndex(k))
+			}
+		case reflect.Struct:
+			for i := range x.NumField() {
+				walk(path+"."+x.Type().Field(i).Name, x.Field(i), y.Field(i))
+			}
+		}
+	}
+	walk("", reflect.ValueOf(x), reflect.ValueOf(y))
+}
+
+func TestClone(t *testing.T) {
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+	loader.IncludeOrigin = true
+	doc, err := loader.LoadFromFile("testdata/bundle/openapi.yaml")
+	require.NoError(t, err)
+	doc.SetStringFormatValidator("color", openapi3.NewRegexpFormatValidator(`^[a-z]+$`))
+
+	clone := doc.Clone()
+	require.NoError(t, clone.Validate(loader.Context))
+	requireNoSharedMemory(t, doc, clone)
+
+	expected, err := doc.MarshalJSON()
+	require.NoError(t, err)
+	actual, err := clone.MarshalJSON()
+	require.NoError(t, err)
+	require.JSONEq(t, string(expected), string(actual))
+
+	// Document-scoped format validators are carried over
+	color := openapi3.NewStringSchema().WithFormat("color")
+	require.NoError(t, color.VisitJSON("red", clone.GetSchemaValidationOptions()...))
+	require.Error(t, color.VisitJSON("Red", clone.GetSchemaValidationOptions()...))
+	require.Equal(t, doc.Origin, clone.Origin)
+
+	owner := func(doc *openapi3.T) *openapi3.Schema {
+		return doc.Paths.Find("/owners").Get.Responses.Status(200).Value.Content.Get("application/json").Schema.Value.Items.Value
+	}
+	owner(clone).Description = "changed"
+	require.Empty(t, owner(doc).Description)
+}
+
+func TestCloneSharedValues(t *testing.T) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData([]byte(dereferenceSpec))
+	require.NoError(t, err)
+
+	// References to the same value still share it in the copy
+	depth := func(doc *openapi3.T) (*openapi3.Schema, *openapi3.Schema) {
+		return doc.Components.Parameters["Depth"].Value.Schema.Value,
+			doc.Components.Schemas["Node"].Value.Properties["depth"].Value
+	}
+	clone := doc.Clone()
+	fromParameter, fromNode := depth(doc)
+	require.Same(t, fromParameter, fromNode)
+	cloneFromParameter, cloneFromNode := depth(clone)
+	require.Same(t, cloneFromParameter, cloneFromNode)
+	require.NotSame(t, fromParameter, cloneFromParameter)
+
+	// Cycles are kept within a copy of a single value
+
+	node := doc.Components.Schemas["Node"].Clone()
+	require.NotSame(t, doc.Components.Schemas["Node"].Value, node.Value)
+	require.Same(t, node.Value, node.Value.Properties["children"].Value.Items.Value)
+	require.Equal(t, "#/components/schemas/Node", node.Value.Properties["children"].Value.Items.Ref)
+}
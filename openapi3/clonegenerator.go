@@ -0,0 +1,248 @@
+//go:build ignore
+
+// The program generates clone.go, invoke `go generate ./...` to run.
+package main
+
+import (
+	"bytes"
+	_ "embed"
+	"go/format"
+	"maps"
+	"net/url"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+//go:embed clone.tmpl
+var tmplData string
+
+// exported lists the types that get a Clone method.
+var exported = []any{
+	openapi3.T{},
+	openapi3.Components{},
+	openapi3.Paths{},
+	openapi3.PathItem{},
+	openapi3.Operation{},
+	openapi3.Responses{},
+	openapi3.Callback{},
+	openapi3.CallbackRef{},
+	openapi3.Example{},
+	openapi3.ExampleRef{},
+	openapi3.Header{},
+	openapi3.HeaderRef{},
+	openapi3.Link{},
+	openapi3.LinkRef{},
+	openapi3.Parameter{},
+	openapi3.ParameterRef{},
+	openapi3.RequestBody{},
+	openapi3.RequestBodyRef{},
+	openapi3.Response{},
+	openapi3.ResponseRef{},
+	openapi3.Schema{},
+	openapi3.SchemaRef{},
+	openapi3.SecurityScheme{},
+	openapi3.SecuritySchemeRef{},
+}
+
+// reset lists the types of fields that are zeroed rather than copied.
+var reset = map[string]bool{
+	// visitedComponent caches a traversal of the original document.
+	"visitedComponent": true,
+}
+
+var urlType = reflect.TypeOf(&url.URL{})
+
+type field struct {
+	Name string
+	Expr string
+}
+
+type structType struct {
+	Name string
+	// Func is the suffix of the clone and copy functions of the type: its
+	// name, capitalized for unexported types.
+	Func    string
+	Pointer bool
+	Fields  []field
+}
+
+type container struct {
+	Name string
+	Type string
+	Kind string
+	Expr string
+}
+
+type generator struct {
+	pkgPath    string
+	structs    map[string]*structType
+	containers map[string]*container
+}
+
+func main() {
+	g := generator{
+		pkgPath:    reflect.TypeOf(openapi3.T{}).PkgPath(),
+		structs:    make(map[string]*structType),
+		containers: make(map[string]*container),
+	}
+	var names []string
+	for _, v := range exported {
+		t := reflect.TypeOf(v)
+		names = append(names, t.Name())
+		g.expr(reflect.PointerTo(t), "x")
+	}
+
+	var structs []*structType
+	for _, name := range slices.Sorted(maps.Keys(g.structs)) {
+		structs = append(structs, g.structs[name])
+	}
+	var containers []*container
+	for _, name := range slices.Sorted(maps.Keys(g.containers)) {
+		containers = append(containers, g.containers[name])
+	}
+
+	var output bytes.Buffer
+	if err := template.Must(template.New("openapi3-clone").Parse(tmplData)).Execute(&output, struct {
+		Package    string
+		Exported   []string
+		Structs    []*structType
+		Containers []*container
+	}{
+		Package:    os.Getenv("GOPACKAGE"), // set by the go:generate directive
+		Exported:   names,
+		Structs:    structs,
+		Containers: containers,
+	}); err != nil {
+		panic(err)
+	}
+
+	formatted, err := format.Source(output.Bytes())
+	if err != nil {
+		panic(err)
+	}
+	if err := os.WriteFile("clone.go", formatted, 0o644); err != nil {
+		panic(err)
+	}
+}
+
+// deep reports whether copying a value of type t with an assignment would
+// share memory with the original.
+func (g *generator) deep(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map:
+		return true
+	case reflect.Interface:
+		return t.NumMethod() == 0
+	case reflect.Struct:
+		if t.PkgPath() != g.pkgPath || reset[t.Name()] {
+			return reset[t.Name()]
+		}
+		for i := range t.NumField() {
+			if g.deep(t.Field(i).Type) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// typeName returns the Go syntax of t within the package.
+func (g *generator) typeName(t reflect.Type) string {
+	return strings.ReplaceAll(t.String(), "openapi3.", "")
+}
+
+// containerName returns the suffix of the clone function of a slice or map type.
+func (g *generator) containerName(t reflect.Type) string {
+	if t.Name() != "" {
+		return t.Name()
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		if t.Elem().Kind() == reflect.Struct {
+			return g.containerName(t.Elem())
+		}
+		return g.containerName(t.Elem()) + "Ptr"
+	case reflect.Slice:
+		return g.containerName(t.Elem()) + "Slice"
+	case reflect.Map:
+		return g.containerName(t.Elem()) + "Map"
+	case reflect.Interface:
+		return "Any"
+	}
+	return strings.ToUpper(t.Kind().String()[:1]) + t.Kind().String()[1:]
+}
+
+// expr returns the expression that copies src, of type t.
+func (g *generator) expr(t reflect.Type, src string) string {
+	if !g.deep(t) {
+		return src
+	}
+	switch {
+	case t == urlType:
+		return "copyURI(" + src + ")"
+	case reset[t.Name()]:
+		return t.Name() + "{}"
+	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+		return "cloneAny(" + src + ")"
+
+	case reflect.Pointer:
+		if t.Elem().Kind() == reflect.Struct {
+			s := g.structType(t.Elem())
+			s.Pointer = true
+			return "c.clone" + s.Func + "(" + src + ")"
+		}
+		if !g.deep(t.Elem()) {
+			return "clonePtr(" + src + ")"
+		}
+		return "c.clone" + g.container(t, "*x") + "(" + src + ")"
+
+	case reflect.Struct:
+		s := g.structType(t)
+		return "c.copy" + s.Func + "(" + src + ")"
+
+	case reflect.Slice, reflect.Map:
+		if !g.deep(t.Elem()) {
+			if t.Kind() == reflect.Slice {
+				return "slices.Clone(" + src + ")"
+			}
+			return "maps.Clone(" + src + ")"
+		}
+		return "c.clone" + g.container(t, "v") + "(" + src + ")"
+	}
+	panic("unsupported type " + t.String())
+}
+
+// container returns the name of the clone function of a pointer, slice or map
+// type, whose elements are copied from elem.
+func (g *generator) container(t reflect.Type, elem string) string {
+	name := g.containerName(t)
+	if _, ok := g.containers[name]; !ok {
+		c := &container{Name: name, Type: g.typeName(t), Kind: t.Kind().String()}
+		g.containers[name] = c
+		c.Expr = g.expr(t.Elem(), elem)
+	}
+	return name
+}
+
+func (g *generator) structType(t reflect.Type) *structType {
+	if s, ok := g.structs[t.Name()]; ok {
+		return s
+	}
+	s := &structType{Name: t.Name(), Func: strings.ToUpper(t.Name()[:1]) + t.Name()[1:]}
+	g.structs[t.Name()] = s
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if g.deep(f.Type) {
+			s.Fields = append(s.Fields, field{Name: f.Name, Expr: g.expr(f.Type, "x."+f.Name)})
+		}
+	}
+	return s
+}
@@ -109,7 +109,7 @@ func (content Content) Validate(ctx context.Context, opts ...ValidationOption) e
 	ctx = WithValidationOptions(ctx, opts...)
 
 	for _, k := range componentNames(content) {
-		if err := content[k].Validate(ctx); err != nil {
+		if err := content[k].validate(ctx, k); err != nil {
 			return err
 		}
 	}
@@ -0,0 +1,363 @@
+package openapi3
+
+// DereferenceOption configures Dereference.
+type DereferenceOption func(*dereferenceOptions)
+
+type dereferenceOptions struct {
+	cycleDepth int
+}
+
+// DereferenceCycleDepth makes Dereference unroll each recursive schema depth
+// more times at its cycle point and then cut the recursion off with an empty
+// schema, rather than keeping a $ref there. The result then contains no $ref
+// at all. A depth of zero or less keeps the default behavior.
+func DereferenceCycleDepth(depth int) DereferenceOption {
+	return func(opts *dereferenceOptions) {
+		opts.cycleDepth = depth
+	}
+}
+
+// Dereference replaces every reference in the document with an inlined copy of
+// its value, so that the document marshals without any $ref. It is meant for
+// consumers, such as documentation renderers and fuzzers, that need the whole
+// tree at hand; doc must have been loaded so that each reference has its Value.
+//
+// Every inlined node is a distinct copy: changing one occurrence does not
+// change the others nor the components it came from. Copies are shallow past
+// the references they inline, and keep the Origin and Extensions of the
+// original values. Components are kept, dereferenced in the same way.
+//
+// A reference whose value is already being inlined further up the tree is a
+// cycle point of a recursive schema. By default it keeps its $ref, which still
+// resolves against the components; see DereferenceCycleDepth to unroll the
+// recursion instead. References that are not schemas always keep their $ref
+// at cycle points. Discriminator mappings, which name schemas rather than
+// reference them, are left as they are.
+func (doc *T) Dereference(opts ...DereferenceOption) {
+	d := dereferencer{inlining: make(map[any]int)}
+	for _, opt := range opts {
+		opt(&d.opts)
+	}
+
+	if c := doc.Components; c != nil {
+		for _, name := range componentNames(c.Schemas) {
+			c.Schemas[name] = d.schemaRef(c.Schemas[name])
+		}
+		for _, name := range componentNames(c.Parameters) {
+			c.Parameters[name] = d.parameterRef(c.Parameters[name])
+		}
+		for _, name := range componentNames(c.Headers) {
+			c.Headers[name] = d.headerRef(c.Headers[name])
+		}
+		for _, name := range componentNames(c.RequestBodies) {
+			c.RequestBodies[name] = d.requestBodyRef(c.RequestBodies[name])
+		}
+		for _, name := range componentNames(c.Responses) {
+			c.Responses[name] = d.responseRef(c.Responses[name])
+		}
+		for _, name := range componentNames(c.SecuritySchemes) {
+			c.SecuritySchemes[name] = d.securitySchemeRef(c.SecuritySchemes[name])
+		}
+		for _, name := range componentNames(c.Examples) {
+			c.Examples[name] = d.exampleRef(c.Examples[name])
+		}
+		for _, name := range componentNames(c.Links) {
+			c.Links[name] = d.linkRef(c.Links[name])
+		}
+		for _, name := range componentNames(c.Callbacks) {
+			c.Callbacks[name] = d.callbackRef(c.Callbacks[name])
+		}
+	}
+	if doc.Paths != nil {
+		for _, path := range doc.Paths.Keys() {
+			doc.Paths.Set(path, d.pathItem(doc.Paths.Value(path)))
+		}
+	}
+	for _, name := range componentNames(doc.Webhooks) {
+		doc.Webhooks[name] = d.pathItem(doc.Webhooks[name])
+	}
+}
+
+type dereferencer struct {
+	opts dereferenceOptions
+	// inlining counts, for each value, how many times it is being inlined on
+	// the path from the root of the tree to the current node.
+	inlining map[any]int
+}
+
+// enter reports whether value can be inlined at this point of the tree, in
+// which case the caller must call leave once done with it.
+func (d *dereferencer) enter(value any, depth int) bool {
+	if d.inlining[value] > depth {
+		return false
+	}
+	d.inlining[value]++
+	return true
+}
+
+func (d *dereferencer) leave(value any) {
+	d.inlining[value]--
+}
+
+func (d *dereferencer) schemaRef(x *SchemaRef) *SchemaRef {
+	if x == nil || x.Value == nil {
+		return x
+	}
+	if !d.enter(x.Value, d.opts.cycleDepth) {
+		if d.opts.cycleDepth > 0 || x.Ref == "" {
+			return &SchemaRef{Origin: x.Origin, Value: &Schema{}}
+		}
+		return x
+	}
+	defer d.leave(x.Value)
+	return &SchemaRef{Origin: x.Origin, Value: d.schema(x.Value)}
+}
+
+func (d *dereferencer) schemaRefs(refs SchemaRefs) SchemaRefs {
+	if refs == nil {
+		return nil
+	}
+	c := make(SchemaRefs, len(refs))
+	for i, x := range refs {
+		c[i] = d.schemaRef(x)
+	}
+	return c
+}
+
+func (d *dereferencer) schemas(schemas Schemas) Schemas {
+	if schemas == nil {
+		return nil
+	}
+	c := make(Schemas, len(schemas))
+	for _, name := range componentNames(schemas) {
+		c[name] = d.schemaRef(schemas[name])
+	}
+	return c
+}
+
+func (d *dereferencer) schema(s *Schema) *Schema {
+	c := *s
+	c.Properties = d.schemas(s.Properties)
+	c.Items = d.schemaRef(s.Items)
+	c.AdditionalProperties.Schema = d.schemaRef(s.AdditionalProperties.Schema)
+	c.Not = d.schemaRef(s.Not)
+	c.AllOf = d.schemaRefs(s.AllOf)
+	c.AnyOf = d.schemaRefs(s.AnyOf)
+	c.OneOf = d.schemaRefs(s.OneOf)
+	c.PrefixItems = d.schemaRefs(s.PrefixItems)
+	c.Contains = d.schemaRef(s.Contains)
+	c.PatternProperties = d.schemas(s.PatternProperties)
+	c.DependentSchemas = d.schemas(s.DependentSchemas)
+	c.Defs = d.schemas(s.Defs)
+	c.PropertyNames = d.schemaRef(s.PropertyNames)
+	c.UnevaluatedItems.Schema = d.schemaRef(s.UnevaluatedItems.Schema)
+	c.UnevaluatedProperties.Schema = d.schemaRef(s.UnevaluatedProperties.Schema)
+	c.If = d.schemaRef(s.If)
+	c.Then = d.schemaRef(s.Then)
+	c.Else = d.schemaRef(s.Else)
+	c.ContentSchema = d.schemaRef(s.ContentSchema)
+	return &c
+}
+
+func (d *dereferencer) parameterRef(x *ParameterRef) *ParameterRef {
+	if x == nil || x.Value == nil {
+		return x
+	}
+	if !d.enter(x.Value, 0) {
+		return x
+	}
+	defer d.leave(x.Value)
+	return &ParameterRef{Origin: x.Origin, Value: d.parameter(x.Value)}
+}
+
+func (d *dereferencer) parameter(p *Parameter) *Parameter {
+	c := *p
+	c.Schema = d.schemaRef(p.Schema)
+	c.Examples = d.examples(p.Examples)
+	c.Content = d.content(p.Content)
+	return &c
+}
+
+func (d *dereferencer) parameters(parameters Parameters) Parameters {
+	if parameters == nil {
+		return nil
+	}
+	c := make(Parameters, len(parameters))
+	for i, x := range parameters {
+		c[i] = d.parameterRef(x)
+	}
+	return c
+}
+
+func (d *dereferencer) headerRef(x *HeaderRef) *HeaderRef {
+	if x == nil || x.Value == nil {
+		return x
+	}
+	if !d.enter(x.Value, 0) {
+		return x
+	}
+	defer d.leave(x.Value)
+	return &HeaderRef{Origin: x.Origin, Value: &Header{Parameter: *d.parameter(&x.Value.Parameter)}}
+}
+
+func (d *dereferencer) headers(headers Headers) Headers {
+	if headers == nil {
+		return nil
+	}
+	c := make(Headers, len(headers))
+	for _, name := range componentNames(headers) {
+		c[name] = d.headerRef(headers[name])
+	}
+	return c
+}
+
+func (d *dereferencer) requestBodyRef(x *RequestBodyRef) *RequestBodyRef {
+	if x == nil || x.Value == nil {
+		return x
+	}
+	if !d.enter(x.Value, 0) {
+		return x
+	}
+	defer d.leave(x.Value)
+	c := *x.Value
+	c.Content = d.content(x.Value.Content)
+	return &RequestBodyRef{Origin: x.Origin, Value: &c}
+}
+
+func (d *dereferencer) responseRef(x *ResponseRef) *ResponseRef {
+	if x == nil || x.Value == nil {
+		return x
+	}
+	if !d.enter(x.Value, 0) {
+		return x
+	}
+	defer d.leave(x.Value)
+	c := *x.Value
+	c.Headers = d.headers(x.Value.Headers)
+	c.Content = d.content(x.Value.Content)
+	if x.Value.Links != nil {
+		c.Links = make(Links, len(x.Value.Links))
+		for _, name := range componentNames(x.Value.Links) {
+			c.Links[name] = d.linkRef(x.Value.Links[name])
+		}
+	}
+	return &ResponseRef{Origin: x.Origin, Value: &c}
+}
+
+func (d *dereferencer) content(content Content) Content {
+	if content == nil {
+		return nil
+	}
+	c := make(Content, len(content))
+	for _, name := range componentNames(content) {
+		mediaType := content[name]
+		if mediaType == nil {
+			c[name] = nil
+			continue
+		}
+		m := *mediaType
+		m.Schema = d.schemaRef(mediaType.Schema)
+		m.ItemSchema = d.schemaRef(mediaType.ItemSchema)
+		m.Examples = d.examples(mediaType.Examples)
+		if mediaType.Encoding != nil {
+			m.Encoding = make(map[string]*Encoding, len(mediaType.Encoding))
+			for _, name := range componentNames(mediaType.Encoding) {
+				encoding := mediaType.Encoding[name]
+				if encoding == nil {
+					m.Encoding[name] = nil
+					continue
+				}
+				e := *encoding
+				e.Headers = d.headers(encoding.Headers)
+				m.Encoding[name] = &e
+			}
+		}
+		c[name] = &m
+	}
+	return c
+}
+
+func (d *dereferencer) examples(examples Examples) Examples {
+	if examples == nil {
+		return nil
+	}
+	c := make(Examples, len(examples))
+	for _, name := range componentNames(examples) {
+		c[name] = d.exampleRef(examples[name])
+	}
+	return c
+}
+
+func (d *dereferencer) exampleRef(x *ExampleRef) *ExampleRef {
+	if x == nil || x.Value == nil {
+		return x
+	}
+	c := *x.Value
+	return &ExampleRef{Origin: x.Origin, Value: &c}
+}
+
+func (d *dereferencer) linkRef(x *LinkRef) *LinkRef {
+	if x == nil || x.Value == nil {
+		return x
+	}
+	c := *x.Value
+	return &LinkRef{Origin: x.Origin, Value: &c}
+}
+
+func (d *dereferencer) securitySchemeRef(x *SecuritySchemeRef) *SecuritySchemeRef {
+	if x == nil || x.Value == nil {
+		return x
+	}
+	c := *x.Value
+	return &SecuritySchemeRef{Origin: x.Origin, Value: &c}
+}
+
+func (d *dereferencer) callbackRef(x *CallbackRef) *CallbackRef {
+	if x == nil || x.Value == nil {
+		return x
+	}
+	if !d.enter(x.Value, 0) {
+		return x
+	}
+	defer d.leave(x.Value)
+	c := *x.Value
+	c.m = make(map[string]*PathItem, x.Value.Len())
+	for _, expr := range x.Value.Keys() {
+		c.m[expr] = d.pathItem(x.Value.Value(expr))
+	}
+	return &CallbackRef{Origin: x.Origin, Value: &c}
+}
+
+func (d *dereferencer) pathItem(pathItem *PathItem) *PathItem {
+	if pathItem == nil {
+		return nil
+	}
+	c := *pathItem
+	c.Ref = ""
+	c.Parameters = d.parameters(pathItem.Parameters)
+	for method, op := range pathItem.Operations() {
+		c.SetOperation(method, d.operation(op))
+	}
+	return &c
+}
+
+func (d *dereferencer) operation(op *Operation) *Operation {
+	c := *op
+	c.Parameters = d.parameters(op.Parameters)
+	c.RequestBody = d.requestBodyRef(op.RequestBody)
+	if op.Responses != nil {
+		responses := *op.Responses
+		responses.m = make(map[string]*ResponseRef, op.Responses.Len())
+		for _, code := range op.Responses.Keys() {
+			responses.m[code] = d.responseRef(op.Responses.Value(code))
+		}
+		c.Responses = &responses
+	}
+	if op.Callbacks != nil {
+		c.Callbacks = make(Callbacks, len(op.Callbacks))
+		for _, name := range componentNames(op.Callbacks) {
+			c.Callbacks[name] = d.callbackRef(op.Callbacks[name])
+		}
+	}
+	return &c
+}
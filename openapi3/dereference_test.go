@@ -0,0 +1,125 @@
+package openapi3_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const dereferenceSpec = `
+openapi: 3.0.3
+info:
+  title: Tree
+  version: 1.0.0
+paths:
+  /nodes:
+    get:
+      parameters:
+      - $ref: '#/components/parameters/Depth'
+      responses:
+        '200':
+          $ref: '#/components/responses/Node'
+components:
+  parameters:
+    Depth:
+      name: depth
+      in: query
+      schema:
+        $ref: '#/components/schemas/Depth'
+  responses:
+    Node:
+      description: A node
+      content:
+        application/json:
+          schema:
+            $ref: '#/components/schemas/Node'
+  schemas:
+    Depth:
+      type: integer
+    Node:
+      type: object
+      properties:
+        depth:
+          $ref: '#/components/schemas/Depth'
+        children:
+          type: array
+          items:
+            $ref: '#/components/schemas/Node'
+`
+
+func TestDereference(t *testing.T) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData([]byte(dereferenceSpec))
+	require.NoError(t, err)
+
+	doc.Dereference()
+	require.NoError(t, doc.Validate(loader.Context))
+
+	op := doc.Paths.Find("/nodes").Get
+	require.Empty(t, op.Parameters[0].Ref)
+	require.Empty(t, op.Parameters[0].Value.Schema.Ref)
+	require.Empty(t, op.Responses.Status(200).Ref)
+
+	node := op.Responses.Status(200).Value.Content.Get("application/json").Schema
+	require.Empty(t, node.Ref)
+	require.Empty(t, node.Value.Properties["depth"].Ref)
+	require.Equal(t, &openapi3.Types{"integer"}, node.Value.Properties["depth"].Value.Type)
+
+	// The recursion keeps a single $ref at its cycle point
+	items := node.Value.Properties["children"].Value.Items
+	require.Equal(t, "#/components/schemas/Node", items.Ref)
+
+	// Inlined nodes are copies
+	node.Value.Properties["depth"].Value.Description = "changed"
+	require.Empty(t, doc.Components.Schemas["Depth"].Value.Description)
+	require.Empty(t, doc.Components.Schemas["Node"].Value.Properties["depth"].Value.Description)
+
+	data, err := doc.MarshalJSON()
+	require.NoError(t, err)
+	// One cycle point each in the Node schema, the Node response and the operation
+	require.Equal(t, 3, strings.Count(string(data), `"$ref"`))
+}
+
+func TestDereferenceCycleDepth(t *testing.T) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData([]byte(dereferenceSpec))
+	require.NoError(t, err)
+
+	doc.Dereference(openapi3.DereferenceCycleDepth(1))
+	require.NoError(t, doc.Validate(loader.Context))
+
+	node := doc.Paths.Find("/nodes").Get.Responses.Status(200).Value.Content.Get("application/json").Schema
+	child := node.Value.Properties["children"].Value.Items
+	require.Empty(t, child.Ref)
+	require.Equal(t, &openapi3.Types{"object"}, child.Value.Type)
+	require.Equal(t, &openapi3.Schema{}, child.Value.Properties["children"].Value.Items.Value)
+
+	data, err := doc.MarshalJSON()
+	require.NoError(t, err)
+	require.NotContains(t, string(data), `"$ref"`)
+}
+
+func TestDereferenceExternalRefs(t *testing.T) {
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+	loader.IncludeOrigin = true
+	doc, err := loader.LoadFromFile("testdata/bundle/openapi.yaml")
+	require.NoError(t, err)
+
+	doc.Dereference()
+	require.NoError(t, doc.Validate(loader.Context))
+
+	pets := doc.Paths.Find("/pets")
+	require.Empty(t, pets.Ref)
+	pet := pets.Get.Responses.Status(200).Value.Content.Get("application/json").Schema.Value.Items
+	require.Empty(t, pet.Ref)
+	require.NotNil(t, pet.Value.Origin)
+	require.Equal(t, "testdata/bundle/schemas/pet.yaml", pet.Value.Origin.Key.File)
+
+	data, err := doc.MarshalJSON()
+	require.NoError(t, err)
+	require.NotContains(t, string(data), `"$ref"`)
+}
@@ -16,6 +16,8 @@ type Example struct {
 	Description   string `json:"description,omitempty" yaml:"description,omitempty"`
 	Value         any    `json:"value,omitempty" yaml:"value,omitempty"`
 	ExternalValue string `json:"externalValue,omitempty" yaml:"externalValue,omitempty"`
+
+	external *externalValue
 }
 
 func NewExample(value any) *Example {
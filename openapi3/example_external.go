@@ -0,0 +1,104 @@
+package openapi3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net/url"
+	"strings"
+)
+
+// ErrUnsupportedExampleContentType is returned by an ExampleDecoder for
+// contents it cannot decode. Such examples are not validated.
+var ErrUnsupportedExampleContentType = errors.New("unsupported example content type")
+
+// ExampleDecoder decodes the contents an Example.ExternalValue points to into
+// the value to validate against schema. contentType is the media type of the
+// content the example is in, empty for a parameter described by a
+// schema. openapi3filter.DecodeExample decodes with the body decoders of
+// openapi3filter.
+type ExampleDecoder func(data []byte, contentType string, schema *SchemaRef) (any, error)
+
+// externalValue holds the contents an Example.ExternalValue points to.
+type externalValue struct {
+	location *url.URL
+	data     []byte
+}
+
+// ExternalValueData returns the contents ExternalValue points to and where
+// they were read from, or nil if the loader did not read them (see
+// Loader.LoadExternalExamples).
+func (example *Example) ExternalValueData() ([]byte, *url.URL) {
+	if example.external == nil {
+		return nil, nil
+	}
+	return example.external.data, copyURI(example.external.location)
+}
+
+// loadExternalValue reads what the ExternalValue of example, found in the
+// document at documentPath, points to.
+func (loader *Loader) loadExternalValue(example *Example, documentPath *url.URL) error {
+	if !loader.LoadExternalExamples || example == nil || example.ExternalValue == "" || example.external != nil {
+		return nil
+	}
+	location, err := loader.resolveRefPath(example.ExternalValue, documentPath)
+	if err != nil {
+		return err
+	}
+	location.Fragment = ""
+	data, err := loader.readURL(location)
+	if err != nil {
+		return fmt.Errorf("failed to read external value %q of example: %w", example.ExternalValue, err)
+	}
+	example.external = &externalValue{location: location, data: data}
+	return nil
+}
+
+// DecodeExample is the ExampleDecoder used when none is set with
+// ExternalExamplesDecoder. It decodes JSON and YAML, which is also how
+// contents without a content type are decoded, and text as a string.
+func DecodeExample(data []byte, contentType string, schema *SchemaRef) (any, error) {
+	mediaType := contentType
+	if parsed, _, err := mime.ParseMediaType(contentType); err == nil {
+		mediaType = parsed
+	}
+	switch {
+	case mediaType == "",
+		mediaType == "application/json", strings.HasSuffix(mediaType, "+json"),
+		mediaType == "application/yaml", mediaType == "application/x-yaml", strings.HasSuffix(mediaType, "+yaml"):
+		var value any
+		if _, err := unmarshal(data, &value, false, nil); err != nil {
+			return nil, err
+		}
+		return value, nil
+	case strings.HasPrefix(mediaType, "text/"):
+		return string(data), nil
+	}
+	return nil, fmt.Errorf("%w %q", ErrUnsupportedExampleContentType, contentType)
+}
+
+// externalExampleValue decodes the external value of example, if the loader
+// read it. ok is false when it did not or it cannot be decoded, in which case
+// there is nothing to validate.
+func externalExampleValue(ctx context.Context, example *Example, contentType string, schema *SchemaRef) (value any, ok bool, err error) {
+	if example == nil || example.external == nil {
+		return nil, false, nil
+	}
+	decode := DecodeExample
+	if vo := getValidationOptions(ctx); vo.exampleDecoder != nil {
+		decode = vo.exampleDecoder
+	}
+	if value, err = decode(example.external.data, contentType, schema); err != nil {
+		if errors.Is(err, ErrUnsupportedExampleContentType) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to decode external value %q: %w", example.ExternalValue, err)
+	}
+	return value, true, nil
+}
+
+// externalValueOrigin returns the origin of the external value of example.
+func externalValueOrigin(example *Example) *Origin {
+	return &Origin{Key: &Location{File: example.external.location.String()}}
+}
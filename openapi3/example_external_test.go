@@ -0,0 +1,74 @@
+package openapi3_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestLoadExternalExamples(t *testing.T) {
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+	loader.LoadExternalExamples = true
+	doc, err := loader.LoadFromFile("testdata/externalExamples/openapi.yaml")
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(loader.Context))
+
+	content := doc.Paths.Value("/pets/{id}").Get.Responses.Value("200").Value.Content
+	data, location := content["application/json"].Examples["rex"].Value.ExternalValueData()
+	require.JSONEq(t, `{"name": "Rex"}`, string(data))
+	require.Equal(t, "testdata/externalExamples/examples/pet.json", location.String())
+
+	// Examples are decoded as the content they are examples of
+	var decoded []string
+	err = doc.Validate(loader.Context, openapi3.ExternalExamplesDecoder(func(data []byte, contentType string, schema *openapi3.SchemaRef) (any, error) {
+		decoded = append(decoded, contentType)
+		return openapi3.DecodeExample(data, contentType, schema)
+	}))
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"", "application/json", "application/json", "text/plain"}, decoded)
+
+	// Decoding errors are reported
+	err = doc.Validate(loader.Context, openapi3.ExternalExamplesDecoder(func(data []byte, contentType string, schema *openapi3.SchemaRef) (any, error) {
+		return nil, errors.New("cannot decode")
+	}))
+	require.ErrorContains(t, err, `example rex: failed to decode external value "examples/pet.json": cannot decode`)
+
+	// Examples of content types that cannot be decoded are not validated
+	err = doc.Validate(loader.Context, openapi3.ExternalExamplesDecoder(func(data []byte, contentType string, schema *openapi3.SchemaRef) (any, error) {
+		return nil, openapi3.ErrUnsupportedExampleContentType
+	}))
+	require.NoError(t, err)
+}
+
+func TestLoadExternalExamplesInvalid(t *testing.T) {
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+	loader.IncludeOrigin = true
+	doc, err := loader.LoadFromFile("testdata/externalExamples/invalid.yaml")
+	require.NoError(t, err)
+
+	// External values are not read by default, and not validated
+	require.NoError(t, doc.Validate(loader.Context))
+
+	loader = openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+	loader.IncludeOrigin = true
+	loader.LoadExternalExamples = true
+	doc, err = loader.LoadFromFile("testdata/externalExamples/invalid.yaml")
+	require.NoError(t, err)
+	err = doc.Validate(loader.Context)
+	require.ErrorContains(t, err, `invalid example: example rex: Error at "/name": property "name" is missing`)
+	var sve *openapi3.SchemaValueError
+	require.ErrorAs(t, err, &sve)
+	require.Equal(t, "testdata/externalExamples/examples/invalid.json", sve.Origin.Key.File)
+
+	// External values are read like external refs
+	loader = openapi3.NewLoader()
+	loader.LoadExternalExamples = true
+	_, err = loader.LoadFromFile("testdata/externalExamples/invalid.yaml")
+	require.EqualError(t, err, `encountered disallowed external reference: "examples/invalid.json"`)
+}
@@ -13,6 +13,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 )
 
 // IncludeOrigin specifies whether to include the origin of the OpenAPI elements.
@@ -45,9 +46,22 @@ type Loader struct {
 	// is false, which on untrusted documents enables local file reads
 	// (`$ref: "/etc/passwd"`) and SSRF (`$ref: "http://169.254.169.254/..."`).
 	// A custom func must apply its own scheme/host allowlist, or re-check
-	// IsExternalRefsAllowed, before reading.
+	// IsExternalRefsAllowed, before reading. ReadFromFS confines reads to an
+	// fs.FS, and URIPolicy is enforced whatever the ReadFromURIFunc.
 	ReadFromURIFunc ReadFromURIFunc
 
+	// URIPolicy, when set, restricts the documents the loader reads, whatever
+	// reads them. Unlike IsExternalRefsAllowed it is also enforced when a
+	// custom ReadFromURIFunc is set.
+	URIPolicy *URIPolicy
+
+	// PrefetchConcurrency, when above 1, has the loader read the external
+	// documents a document refers to, and the ones they refer to, up to that
+	// many at a time ahead of resolving its refs, which are otherwise read
+	// one after the other. A custom ReadFromURIFunc must then be safe for
+	// concurrent use.
+	PrefetchConcurrency int
+
 	// JoinFunc allows overriding how relative $ref paths are resolved against
 	// a base path. When set, it is called instead of the default join logic
 	// that uses path.Dir and path.Join. This is useful when loading specs from
@@ -55,6 +69,15 @@ type Loader struct {
 	// path follows a different convention than filesystem paths.
 	JoinFunc func(basePath *url.URL, relativePath *url.URL) *url.URL
 
+	// LoadExternalExamples enables reading the contents the ExternalValue of
+	// examples point to, as external $refs are read, for Validate to decode
+	// and validate them against their schema (see ExternalExamplesDecoder).
+	LoadExternalExamples bool
+
+	// Overlays are applied, in order, to the root document after it is read
+	// and before it is decoded. They do not apply to the documents it refers to.
+	Overlays []DocumentOverlay
+
 	Context context.Context
 
 	rootDir      string
@@ -64,6 +87,13 @@ type Loader struct {
 
 	visitedDocuments map[string]*T
 
+	// readDocuments holds the locations read so far, for URIPolicy, and
+	// prefetchedDocuments the contents read ahead of their resolution. Both
+	// are guarded by readMu.
+	readMu              sync.Mutex
+	readDocuments       map[string]struct{}
+	prefetchedDocuments map[string][]byte
+
 	// originTrees retains each loaded document's origin tree, keyed by the
 	// document itself so insert and lookup cannot disagree, populated when
 	// IncludeOrigin is set. resolveComponent uses it to re-attach origins to
@@ -74,6 +104,16 @@ type Loader struct {
 	visitedRefs map[string]struct{}
 	visitedPath []string
 	backtrack   map[string][]func(value any)
+
+	// schemaIDs maps the absolute $id of each schema declared in the documents
+	// loaded so far to the location of that schema, so that OpenAPI 3.1+
+	// $refs to an $id resolve in place instead of being fetched.
+	schemaIDs map[string]schemaIDTarget
+}
+
+type schemaIDTarget struct {
+	schema   *Schema
+	location *url.URL
 }
 
 // NewLoader returns an empty Loader
@@ -89,12 +129,15 @@ func (loader *Loader) resetVisitedPathItemRefs() {
 	loader.visitedRefs = make(map[string]struct{})
 	loader.visitedPath = nil
 	loader.backtrack = make(map[string][]func(value any))
+	loader.schemaIDs = nil
+	loader.readDocuments = make(map[string]struct{})
+	loader.prefetchedDocuments = nil
 }
 
 // LoadFromURI loads a spec from a remote URL
 func (loader *Loader) LoadFromURI(location *url.URL) (*T, error) {
 	loader.resetVisitedPathItemRefs()
-	return loader.loadFromURIInternal(location)
+	return loader.loadFromURIInternal(location, true)
 }
 
 // LoadFromFile loads a spec from a local file path
@@ -103,12 +146,14 @@ func (loader *Loader) LoadFromFile(location string) (*T, error) {
 	return loader.LoadFromURI(&url.URL{Path: filepath.ToSlash(location)})
 }
 
-func (loader *Loader) loadFromURIInternal(location *url.URL) (*T, error) {
+// loadFromURIInternal loads the document at location, which is the root
+// document when root is set.
+func (loader *Loader) loadFromURIInternal(location *url.URL, root bool) (*T, error) {
 	data, err := loader.readURL(location)
 	if err != nil {
 		return nil, err
 	}
-	return loader.loadFromDataWithPathInternal(data, location)
+	return loader.loadFromDataWithPathInternal(data, location, root)
 }
 
 func (loader *Loader) allowsExternalRefs(ref string) (err error) {
@@ -160,10 +205,54 @@ func (loader *Loader) rememberOriginTree(doc *T, tree *originTree) {
 }
 
 func (loader *Loader) readURL(location *url.URL) ([]byte, error) {
+	if ctx := loader.Context; ctx != nil {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+	policy := loader.URIPolicy
+	if policy == nil {
+		if data, ok := loader.prefetched(location); ok {
+			return data, nil
+		}
+		return loader.fetchURL(location)
+	}
+
+	// Documents count against the limits of the policy once resolution uses them
+	if err := policy.Check(location); err != nil {
+		return nil, err
+	}
+	loader.readMu.Lock()
+	if loader.readDocuments == nil {
+		loader.readDocuments = make(map[string]struct{})
+	}
+	loader.readMu.Unlock()
+	reserved, err := policy.reserve(location, &loader.readMu, loader.readDocuments)
+	if err != nil {
+		return nil, err
+	}
+	data, ok := loader.prefetched(location)
+	if !ok {
+		if data, err = loader.fetchURL(location); err != nil && reserved {
+			policy.release(location, &loader.readMu, loader.readDocuments)
+		}
+	}
+	return data, err
+}
+
+// fetchURL reads location with the ReadFromURIFunc of the loader, when its
+// URIPolicy allows it. It is safe for concurrent use during a load.
+func (loader *Loader) fetchURL(location *url.URL) ([]byte, error) {
+	read := DefaultReadFromURI
 	if f := loader.ReadFromURIFunc; f != nil {
-		return f(loader, location)
+		read = f
 	}
-	return DefaultReadFromURI(loader, location)
+	if policy := loader.URIPolicy; policy != nil {
+		return policy.read(location, func() ([]byte, error) {
+			return read(loader, location)
+		})
+	}
+	return read(loader, location)
 }
 
 // LoadFromStdin loads a spec from stdin
@@ -188,7 +277,7 @@ func (loader *Loader) LoadFromIoReader(reader io.Reader) (*T, error) {
 func (loader *Loader) LoadFromData(data []byte) (*T, error) {
 	loader.resetVisitedPathItemRefs()
 	doc := &T{}
-	tree, err := unmarshal(data, doc, loader.IncludeOrigin, nil)
+	tree, _, err := loader.unmarshalRoot(data, doc, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -203,10 +292,10 @@ func (loader *Loader) LoadFromData(data []byte) (*T, error) {
 // elements and returns a *T with all resolved data or an error if unable to load data or resolve refs.
 func (loader *Loader) LoadFromDataWithPath(data []byte, location *url.URL) (*T, error) {
 	loader.resetVisitedPathItemRefs()
-	return loader.loadFromDataWithPathInternal(data, location)
+	return loader.loadFromDataWithPathInternal(data, location, true)
 }
 
-func (loader *Loader) loadFromDataWithPathInternal(data []byte, location *url.URL) (*T, error) {
+func (loader *Loader) loadFromDataWithPathInternal(data []byte, location *url.URL, root bool) (*T, error) {
 	if loader.visitedDocuments == nil {
 		loader.visitedDocuments = make(map[string]*T)
 		loader.rootLocation = location.Path
@@ -219,7 +308,13 @@ func (loader *Loader) loadFromDataWithPathInternal(data []byte, location *url.UR
 	doc := &T{}
 	loader.visitedDocuments[uri] = doc
 
-	tree, err := unmarshal(data, doc, loader.IncludeOrigin, location)
+	var tree *originTree
+	var err error
+	if root {
+		tree, data, err = loader.unmarshalRoot(data, doc, location)
+	} else {
+		tree, err = unmarshal(data, doc, loader.IncludeOrigin, location)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -227,6 +322,10 @@ func (loader *Loader) loadFromDataWithPathInternal(data []byte, location *url.UR
 
 	doc.url = copyURI(location)
 
+	if root {
+		// data is the document once overlaid, without the refs overlays removed
+		loader.prefetch(data, location)
+	}
 	if err := loader.ResolveRefsIn(doc, location); err != nil {
 		return nil, err
 	}
@@ -244,6 +343,8 @@ func (loader *Loader) ResolveRefsIn(doc *T, location *url.URL) (err error) {
 		loader.resetVisitedPathItemRefs()
 	}
 
+	loader.indexSchemaIDs(doc, location)
+
 	if components := doc.Components; components != nil {
 		for _, name := range componentNames(components.Headers) {
 			component := components.Headers[name]
@@ -382,6 +483,42 @@ func (loader *Loader) resolveRefPath(ref string, path *url.URL) (*url.URL, error
 	return resolvedPath, nil
 }
 
+// indexSchemaIDs records the schemas of doc that declare an absolute $id.
+// It runs before doc's refs are resolved, so only the schemas written inline
+// in doc are visited, each under its JSON Pointer within doc.
+func (loader *Loader) indexSchemaIDs(doc *T, location *url.URL) {
+	_ = doc.WalkSchemas(func(jsonPointer string, schema *SchemaRef) error {
+		id, err := url.Parse(schema.Value.SchemaID)
+		if err != nil || !id.IsAbs() {
+			return nil
+		}
+		id.Fragment = ""
+		target := copyURI(location)
+		if target == nil {
+			target = new(url.URL)
+		}
+		target.Fragment = jsonPointer
+		if loader.schemaIDs == nil {
+			loader.schemaIDs = make(map[string]schemaIDTarget)
+		}
+		if _, ok := loader.schemaIDs[id.String()]; !ok {
+			loader.schemaIDs[id.String()] = schemaIDTarget{schema: schema.Value, location: target}
+		}
+		return nil
+	})
+}
+
+// schemaByID returns the schema whose $id is ref, if one was indexed.
+// Only whole-resource refs are matched: a ref with a fragment is not.
+func (loader *Loader) schemaByID(ref string) (schemaIDTarget, bool) {
+	u, err := url.Parse(ref)
+	if err != nil || !u.IsAbs() || u.Fragment != "" {
+		return schemaIDTarget{}, false
+	}
+	target, ok := loader.schemaIDs[u.String()]
+	return target, ok
+}
+
 func isSingleRefElement(ref string) bool {
 	return !strings.Contains(ref, "#")
 }
@@ -670,7 +807,7 @@ func (loader *Loader) resolveRefAndDocument(doc *T, ref string, path *url.URL) (
 		return nil, "", nil, err
 	}
 
-	if doc, err = loader.loadFromURIInternal(resolvedPath); err != nil {
+	if doc, err = loader.loadFromURIInternal(resolvedPath, false); err != nil {
 		return nil, "", nil, fmt.Errorf("error resolving reference %q: %w", ref, err)
 	}
 
@@ -1028,6 +1165,13 @@ func (loader *Loader) resolveSchemaRef(doc *T, component *SchemaRef, documentPat
 		if component.Value != nil {
 			return nil
 		}
+		// JSON Schema 2020-12: a $ref may name the $id of a schema declared
+		// elsewhere in the loaded documents.
+		if target, ok := loader.schemaByID(ref); ok {
+			component.Value = target.schema
+			component.setRefPath(target.location)
+			return nil
+		}
 		if !loader.shouldVisitRef(ref, func(value any) {
 			component.Value = value.(*Schema)
 			refPath, _ := loader.resolveRefPath(ref, documentPath)
@@ -1272,6 +1416,9 @@ func applyExampleRefMetadata(value *Example, component *ExampleRef, isOpenAPI31O
 func (loader *Loader) resolveExampleRef(doc *T, component *ExampleRef, documentPath *url.URL) (err error) {
 	isOpenAPI31OrLater := doc.IsOpenAPI31OrLater()
 
+	if component.Ref == "" {
+		return loader.loadExternalValue(component.Value, documentPath)
+	}
 	if ref := component.Ref; ref != "" {
 		if component.Value != nil {
 			return nil
@@ -1287,7 +1434,11 @@ func (loader *Loader) resolveExampleRef(doc *T, component *ExampleRef, documentP
 		loader.visitRef(ref)
 		if isSingleRefElement(ref) {
 			var example Example
-			if _, err = loader.loadSingleElementFromURI(ref, documentPath, &example); err != nil {
+			examplePath, err := loader.loadSingleElementFromURI(ref, documentPath, &example)
+			if err != nil {
+				return err
+			}
+			if err := loader.loadExternalValue(&example, examplePath); err != nil {
 				return err
 			}
 			component.Value = &example
@@ -1496,6 +1647,16 @@ func (loader *Loader) resolvePathItemRef(doc *T, pathItem *PathItem, documentPat
 	return
 }
 
+// EscapeJSONPointerToken escapes a single JSON Pointer reference token per RFC 6901:
+// '~' becomes '~0' and '/' becomes '~1'.
+func EscapeJSONPointerToken(token string) string {
+	if !strings.ContainsAny(token, "~/") {
+		return token
+	}
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+// unescapeRefString is the inverse of EscapeJSONPointerToken.
 func unescapeRefString(ref string) string {
 	return strings.ReplaceAll(strings.ReplaceAll(ref, "~1", "/"), "~0", "~")
 }
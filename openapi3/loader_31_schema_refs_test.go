@@ -174,3 +174,33 @@ func TestResolveSchemaRefsIn31Fields(t *testing.T) {
 	require.Equal(t, "#/components/schemas/NonNegative", contentSchema.ContentSchema.Ref)
 	require.NotNil(t, contentSchema.ContentSchema.Value, "contentSchema $ref should be resolved")
 }
+
+// TestSchemaRefToSchemaID verifies that a $ref naming the $id of a schema declared
+// in the document resolves to that schema instead of being fetched.
+func TestSchemaRefToSchemaID(t *testing.T) {
+	spec := `
+openapi: "3.1.0"
+info:
+  title: Schema ID Test
+  version: "1.0"
+paths: {}
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        tag:
+          $ref: "https://example.com/schemas/tag"
+    Tag:
+      $id: "https://example.com/schemas/tag"
+      type: string
+`
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData([]byte(spec))
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(loader.Context))
+
+	tag := doc.Components.Schemas["Pet"].Value.Properties["tag"]
+	require.Same(t, doc.Components.Schemas["Tag"].Value, tag.Value)
+	require.Equal(t, "#/components/schemas/Tag", tag.RefPath().String())
+}
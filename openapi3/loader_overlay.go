@@ -0,0 +1,155 @@
+package openapi3
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+
+	yaml "github.com/oasdiff/yaml3"
+)
+
+// originKey is the key under which the yaml package records the source location
+// of the mapping nodes it decodes.
+const originKey = "__origin__"
+
+// DocumentOverlay changes an OpenAPI document before the Loader decodes it.
+// It is implemented by the Overlay type of the overlay package.
+type DocumentOverlay interface {
+	// ApplyTo changes doc and returns the result. doc is the document decoded
+	// into generic values: objects are of type map[string]any and arrays of
+	// type []any. When it is read from YAML with Loader.IncludeOrigin set,
+	// objects hold the source location of their node under the "__origin__"
+	// key, which ApplyTo must keep and otherwise ignore.
+	ApplyTo(doc any) (any, error)
+}
+
+// unmarshalRoot decodes the root document data into doc after applying the
+// loader's overlays to it, and returns the data it decoded. The origins of the
+// nodes the overlays keep are those of data, and nodes they add have none.
+func (loader *Loader) unmarshalRoot(data []byte, doc *T, location *url.URL) (*originTree, []byte, error) {
+	if len(loader.Overlays) == 0 {
+		tree, err := unmarshal(data, doc, loader.IncludeOrigin, location)
+		return tree, data, err
+	}
+
+	var file string
+	if location != nil {
+		file = location.String()
+	}
+
+	var v any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if jsonErr := decoder.Decode(&v); jsonErr != nil {
+		yamlDecoder := yaml.NewDecoder(bytes.NewReader(data))
+		yamlDecoder.Origin(loader.IncludeOrigin, file)
+		yamlDecoder.DisableTimestamps(true)
+		v = nil
+		if yamlErr := yamlDecoder.Decode(&v); yamlErr != nil && !errors.Is(yamlErr, io.EOF) {
+			return nil, nil, fmt.Errorf("failed to unmarshal data: json error: %v, yaml error: %v", jsonErr, yamlErr)
+		}
+		v = jsonValue(v)
+	}
+
+	for _, overlay := range loader.Overlays {
+		var err error
+		if v, err = overlay.ApplyTo(v); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var tree *originTree
+	if loader.IncludeOrigin {
+		tree = extractOriginTree(v, file)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := json.Unmarshal(data, doc); err != nil {
+		return nil, nil, unmarshalError(err)
+	}
+	applyOrigins(doc, tree)
+	return tree, data, nil
+}
+
+// jsonValue converts the maps decoded from YAML, whose keys need not be
+// strings, into maps with string keys.
+func jsonValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, e := range v {
+			v[k] = jsonValue(e)
+		}
+		return v
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for k, e := range v {
+			var key string
+			switch k := k.(type) {
+			case string:
+				key = k
+			case int:
+				key = strconv.Itoa(k)
+			case int64:
+				key = strconv.FormatInt(k, 10)
+			case uint64:
+				key = strconv.FormatUint(k, 10)
+			case float64:
+				key = strconv.FormatFloat(k, 'g', -1, 64)
+			default:
+				key = fmt.Sprint(k)
+			}
+			m[key] = jsonValue(e)
+		}
+		return m
+	case []any:
+		for i, e := range v {
+			v[i] = jsonValue(e)
+		}
+		return v
+	}
+	return v
+}
+
+// extractOriginTree removes the "__origin__" entries of v and returns them as
+// an origin tree, as the yaml package does when it decodes a document.
+func extractOriginTree(v any, file string) *originTree {
+	switch v := v.(type) {
+	case map[string]any:
+		tree := &originTree{File: file}
+		if origin, ok := v[originKey]; ok {
+			tree.Origin = origin
+			delete(v, originKey)
+		}
+		for k, e := range v {
+			if child := extractOriginTree(e, file); child != nil {
+				if tree.Fields == nil {
+					tree.Fields = make(map[string]*originTree)
+				}
+				tree.Fields[k] = child
+			}
+		}
+		if tree.Origin == nil && tree.Fields == nil {
+			return nil
+		}
+		return tree
+	case []any:
+		var items []*originTree
+		found := false
+		for _, e := range v {
+			child := extractOriginTree(e, file)
+			items = append(items, child)
+			found = found || child != nil
+		}
+		if !found {
+			return nil
+		}
+		return &originTree{File: file, Items: items}
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package openapi3
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// prefetch reads the external documents data refers to, and the ones they
+// refer to, with up to PrefetchConcurrency reads at a time, for readURL to
+// find them when resolving refs. Read errors are left for the resolution to
+// report, with the context of the ref, and so are the limits of URIPolicy:
+// documents are only counted once used, and no more than its MaxDocuments
+// are read ahead.
+func (loader *Loader) prefetch(data []byte, location *url.URL) {
+	if loader.PrefetchConcurrency < 2 || location == nil {
+		return
+	}
+	if loader.ReadFromURIFunc == nil && !loader.IsExternalRefsAllowed {
+		return
+	}
+	ctx := loaderContext(loader)
+
+	maxDocuments := 0
+	if policy := loader.URIPolicy; policy != nil {
+		maxDocuments = policy.MaxDocuments
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		queued = map[string]struct{}{documentKey(location): {}}
+		tokens = make(chan struct{}, loader.PrefetchConcurrency)
+	)
+	var discover func(data []byte, location *url.URL)
+	discover = func(data []byte, location *url.URL) {
+		for _, ref := range loader.externalRefs(data, location) {
+			key := ref.String()
+			mu.Lock()
+			_, ok := queued[key]
+			full := maxDocuments > 0 && len(queued) >= maxDocuments
+			if !ok && !full {
+				queued[key] = struct{}{}
+			}
+			mu.Unlock()
+			if ok || full {
+				continue
+			}
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				select {
+				case tokens <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
+				data, err := loader.fetchURL(ref)
+				<-tokens
+				if err != nil {
+					return
+				}
+				loader.readMu.Lock()
+				if loader.prefetchedDocuments == nil {
+					loader.prefetchedDocuments = make(map[string][]byte)
+				}
+				loader.prefetchedDocuments[key] = data
+				loader.readMu.Unlock()
+				discover(data, ref)
+			}()
+		}
+	}
+	discover(data, location)
+	wg.Wait()
+}
+
+// prefetched returns the contents of location if prefetch read them.
+func (loader *Loader) prefetched(location *url.URL) ([]byte, bool) {
+	loader.readMu.Lock()
+	defer loader.readMu.Unlock()
+	data, ok := loader.prefetchedDocuments[documentKey(location)]
+	return data, ok
+}
+
+// externalRefs returns the locations of the documents the $refs of data lead
+// to, data being the contents of location. Only refs where the loader resolves
+// them are returned: the ones in example, default, enum and const values and
+// in extensions are not, nor are refs to the $id of a schema of data, the
+// loader resolving them in place.
+func (loader *Loader) externalRefs(data []byte, location *url.URL) []*url.URL {
+	var doc any
+	if _, err := unmarshal(data, &doc, false, location); err != nil {
+		return nil
+	}
+	var refs []string
+	ids := make(map[string]struct{})
+	var collect func(value any, kind refSiteKind)
+	collect = func(value any, kind refSiteKind) {
+		if values, ok := value.([]any); ok {
+			for _, v := range values {
+				collect(v, kind)
+			}
+			return
+		}
+		object, ok := value.(map[string]any)
+		if !ok {
+			return
+		}
+		switch kind {
+		case refSiteNames:
+			for _, v := range object {
+				collect(v, refSiteObject)
+			}
+			return
+		case refSiteCallbacks:
+			for _, v := range object {
+				collect(v, refSiteNames)
+			}
+			return
+		}
+		for key, v := range object {
+			switch s, _ := v.(string); {
+			case key == "$ref":
+				if s != "" && !strings.HasPrefix(s, "#") {
+					refs = append(refs, s)
+				}
+			case kind == refSiteLink:
+				// The parameters and request body of links are values
+			case key == "$id":
+				if s != "" {
+					ids[s] = struct{}{}
+				}
+			case strings.HasPrefix(key, "x-"):
+			case key == "example", key == "default", key == "enum", key == "const", key == "value":
+			case key == "examples":
+				// Schema examples are values, other examples are objects
+				if _, ok := v.(map[string]any); ok {
+					collect(v, refSiteNames)
+				}
+			case key == "links":
+				if links, ok := v.(map[string]any); ok {
+					for _, link := range links {
+						collect(link, refSiteLink)
+					}
+				}
+			case key == "callbacks":
+				collect(v, refSiteCallbacks)
+			case refSiteNamesKeys[key]:
+				if _, ok := v.(map[string]any); ok {
+					collect(v, refSiteNames)
+				} else {
+					collect(v, refSiteObject)
+				}
+			default:
+				collect(v, refSiteObject)
+			}
+		}
+	}
+	collect(doc, refSiteObject)
+
+	var locations []*url.URL
+	for _, ref := range refs {
+		resolved, err := loader.resolvePathWithRef(ref, location)
+		if err != nil {
+			continue
+		}
+		resolved.Fragment = ""
+		if _, ok := ids[resolved.String()]; ok {
+			continue
+		}
+		locations = append(locations, resolved)
+	}
+	return locations
+}
+
+// refSiteKind is what a value of a document holds, for externalRefs to tell
+// the keys of objects from user-chosen names.
+type refSiteKind int
+
+const (
+	// refSiteObject is an OpenAPI object, such as a schema or an operation
+	refSiteObject refSiteKind = iota
+	// refSiteNames is a map of names to OpenAPI objects, such as properties
+	refSiteNames
+	// refSiteCallbacks is a map of names to callbacks, which are maps of
+	// expressions to path items
+	refSiteCallbacks
+	// refSiteLink is a link, whose only ref is its own
+	refSiteLink
+)
+
+// refSiteNamesKeys are the keys of the maps of names to OpenAPI objects, whose
+// names may be any, such as "example" or "x-foo".
+var refSiteNamesKeys = map[string]bool{
+	"$defs":             true,
+	"content":           true,
+	"definitions":       true,
+	"dependentSchemas":  true,
+	"encoding":          true,
+	"headers":           true,
+	"parameters":        true,
+	"pathItems":         true,
+	"paths":             true,
+	"patternProperties": true,
+	"properties":        true,
+	"requestBodies":     true,
+	"responses":         true,
+	"schemas":           true,
+	"securitySchemes":   true,
+	"webhooks":          true,
+}
+
+// documentKey identifies the document at location.
+func documentKey(location *url.URL) string {
+	if location.Fragment == "" {
+		return location.String()
+	}
+	u := *location
+	u.Fragment = ""
+	return u.String()
+}
@@ -0,0 +1,238 @@
+package openapi3_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// schemaServer serves an OpenAPI document referring to n schema documents,
+// the first of which refers to another one, at /openapi.yaml.
+type schemaServer struct {
+	n int
+
+	mu                  sync.Mutex
+	requests            map[string]int
+	inFlight, maxFlight int
+	notModified         int
+}
+
+func (s *schemaServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	if s.requests == nil {
+		s.requests = make(map[string]int)
+	}
+	s.requests[r.URL.Path]++
+	s.inFlight++
+	s.maxFlight = max(s.maxFlight, s.inFlight)
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.inFlight--
+		s.mu.Unlock()
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	etag := `"` + r.URL.Path + `"`
+	if r.Header.Get("If-None-Match") == etag {
+		s.mu.Lock()
+		s.notModified++
+		s.mu.Unlock()
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("ETag", etag)
+
+	var doc strings.Builder
+	switch name := strings.TrimPrefix(r.URL.Path, "/"); name {
+	case "openapi.yaml":
+		doc.WriteString("openapi: 3.0.3\ninfo: {title: t, version: v}\npaths: {}\ncomponents:\n  schemas:\n")
+		for i := range s.n {
+			fmt.Fprintf(&doc, "    S%d: {$ref: 'schemas/s%d.yaml'}\n", i, i)
+		}
+	case "schemas/s0.yaml":
+		doc.WriteString("type: object\nproperties:\n  next: {$ref: 'next.yaml'}\n")
+	case "schemas/next.yaml":
+		doc.WriteString("type: string\n")
+	default:
+		doc.WriteString("type: integer\n")
+	}
+	w.Write([]byte(doc.String()))
+}
+
+func TestLoaderPrefetchConcurrency(t *testing.T) {
+	for _, concurrency := range []int{0, 4} {
+		t.Run(fmt.Sprint(concurrency), func(t *testing.T) {
+			s := &schemaServer{n: 8}
+			srv := httptest.NewServer(s)
+			defer srv.Close()
+			root, err := url.Parse(srv.URL + "/openapi.yaml")
+			require.NoError(t, err)
+
+			loader := openapi3.NewLoader()
+			loader.ReadFromURIFunc = openapi3.ReadFromHTTP(srv.Client())
+			loader.PrefetchConcurrency = concurrency
+			doc, err := loader.LoadFromURI(root)
+			require.NoError(t, err)
+			require.NoError(t, doc.Validate(loader.Context))
+			require.Equal(t, &openapi3.Types{"string"}, doc.Components.Schemas["S0"].Value.Properties["next"].Value.Type)
+			require.Equal(t, &openapi3.Types{"integer"}, doc.Components.Schemas["S7"].Value.Type)
+
+			if concurrency == 0 {
+				require.Equal(t, 1, s.maxFlight)
+				return
+			}
+			require.Greater(t, s.maxFlight, 1)
+			require.LessOrEqual(t, s.maxFlight, concurrency)
+			// Each document is read once, the transitive one included
+			require.Len(t, s.requests, 10)
+			for path, n := range s.requests {
+				require.Equal(t, 1, n, path)
+			}
+		})
+	}
+}
+
+func TestLoaderPrefetchURIPolicy(t *testing.T) {
+	s := &schemaServer{n: 8}
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+	root, err := url.Parse(srv.URL + "/openapi.yaml")
+	require.NoError(t, err)
+
+	// Documents count against MaxDocuments in the order refs are resolved, whichever were prefetched
+	for range 5 {
+		loader := openapi3.NewLoader()
+		loader.ReadFromURIFunc = openapi3.ReadFromHTTP(srv.Client())
+		loader.URIPolicy = &openapi3.URIPolicy{MaxDocuments: 5}
+		loader.PrefetchConcurrency = 4
+		_, err = loader.LoadFromURI(root)
+		require.ErrorIs(t, err, openapi3.ErrURIDisallowed)
+		require.ErrorContains(t, err, fmt.Sprintf(`disallowed URI "%s/schemas/s3.yaml": more than 5 documents`, srv.URL))
+	}
+}
+
+func TestLoaderContext(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer srv.Close()
+	root, err := url.Parse(srv.URL + "/openapi.yaml")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+	defer cancel()
+	loader := openapi3.NewLoader()
+	loader.Context = ctx
+	loader.ReadFromURIFunc = openapi3.ReadFromHTTP(srv.Client())
+	_, err = loader.LoadFromURI(root)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.EqualValues(t, 1, requests.Load())
+
+	// Nothing is read once the context is done
+	_, err = loader.LoadFromURI(root)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.EqualValues(t, 1, requests.Load())
+}
+
+func TestURIDiskCache(t *testing.T) {
+	s := &schemaServer{n: 2}
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+	root, err := url.Parse(srv.URL + "/openapi.yaml")
+	require.NoError(t, err)
+	dir := t.TempDir()
+
+	load := func() *openapi3.T {
+		loader := openapi3.NewLoader()
+		loader.ReadFromURIFunc = openapi3.URIDiskCache(dir, srv.Client())
+		doc, err := loader.LoadFromURI(root)
+		require.NoError(t, err)
+		require.NoError(t, doc.Validate(loader.Context))
+		return doc
+	}
+
+	first := load()
+	require.Zero(t, s.notModified)
+
+	// A new loader, as in another process, revalidates what it has on disk
+	second := load()
+	require.Equal(t, 4, s.notModified)
+	require.Equal(t, first.Components.Schemas["S0"].Value.Properties["next"].Value.Type, second.Components.Schemas["S0"].Value.Properties["next"].Value.Type)
+}
+
+func TestLoaderPrefetchRefSites(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.URL.Path)
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/openapi.yaml":
+			w.Write([]byte(`
+openapi: 3.0.3
+info: {title: t, version: v}
+paths:
+  /pets:
+    get:
+      x-internal: {$ref: 'extension.yaml'}
+      responses:
+        "200":
+          description: A pet
+          content:
+            application/json:
+              schema: {$ref: 'pet.yaml'}
+              example: {$ref: 'example.yaml'}
+            application/xml:
+              examples:
+                rex:
+                  value: {$ref: 'examples.yaml'}
+`))
+		case "/pet.yaml":
+			w.Write([]byte(`
+type: object
+default: {$ref: 'default.yaml'}
+properties:
+  example: {$ref: 'name.yaml'}
+`))
+		case "/name.yaml":
+			w.Write([]byte("type: string\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	root, err := url.Parse(srv.URL + "/openapi.yaml")
+	require.NoError(t, err)
+
+	loader := openapi3.NewLoader()
+	loader.ReadFromURIFunc = openapi3.ReadFromHTTP(srv.Client())
+	loader.PrefetchConcurrency = 4
+	// Refs in values and extensions do not use up the documents
+	loader.URIPolicy = &openapi3.URIPolicy{MaxDocuments: 3}
+	doc, err := loader.LoadFromURI(root)
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(loader.Context))
+	pet := doc.Paths.Value("/pets").Get.Responses.Status(200).Value.Content["application/json"].Schema.Value
+	require.Equal(t, &openapi3.Types{"string"}, pet.Properties["example"].Value.Type)
+	require.ElementsMatch(t, []string{"/openapi.yaml", "/pet.yaml", "/name.yaml"}, requests)
+}
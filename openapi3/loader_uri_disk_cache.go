@@ -0,0 +1,125 @@
+package openapi3
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// URIDiskCache returns a ReadFromURIFunc which reads remote HTTP URIs with the
+// given http.Client, as ReadFromHTTP does, and keeps what it reads in the
+// directory dir so that it outlives the process. A URI found in the cache is
+// requested conditionally with the ETag and Last-Modified of the cached
+// contents, which are used when the server answers 304 Not Modified.
+// Responses with neither are not cached. Other URIs are not supported:
+// combine with ReadFromURIs to read local files too.
+func URIDiskCache(dir string, cl *http.Client) ReadFromURIFunc {
+	return func(loader *Loader, location *url.URL) ([]byte, error) {
+		if location.Scheme == "" || location.Host == "" {
+			return nil, ErrURINotSupported
+		}
+		uri := location.String()
+		filename := filepath.Join(dir, diskCacheKey(uri)+".json")
+		cached := readDiskCacheEntry(filename, uri)
+
+		req, err := http.NewRequestWithContext(loaderContext(loader), "GET", uri, nil)
+		if err != nil {
+			return nil, err
+		}
+		if cached != nil {
+			if cached.ETag != "" {
+				req.Header.Set("If-None-Match", cached.ETag)
+			}
+			if cached.LastModified != "" {
+				req.Header.Set("If-Modified-Since", cached.LastModified)
+			}
+		}
+		resp, err := cl.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode == http.StatusNotModified && cached != nil {
+			return cached.Body, nil
+		}
+		if resp.StatusCode > 399 {
+			return nil, fmt.Errorf("error loading %q: request returned status code %d", uri, resp.StatusCode)
+		}
+		body, err := readDocument(loader, location, resp.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		entry := &diskCacheEntry{
+			URI:          uri,
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+			Body:         body,
+		}
+		if entry.ETag != "" || entry.LastModified != "" {
+			// The cache only saves requests: failing to write it is not an error
+			_ = writeDiskCacheEntry(dir, filename, entry)
+		}
+		return body, nil
+	}
+}
+
+type diskCacheEntry struct {
+	URI          string `json:"uri" yaml:"uri"`
+	ETag         string `json:"etag,omitempty" yaml:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty" yaml:"lastModified,omitempty"`
+	Body         []byte `json:"body" yaml:"body"`
+}
+
+func diskCacheKey(uri string) string {
+	sum := sha256.Sum256([]byte(uri))
+	return hex.EncodeToString(sum[:])
+}
+
+// readDiskCacheEntry returns the entry for uri in filename, or nil.
+func readDiskCacheEntry(filename, uri string) *diskCacheEntry {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil
+	}
+	var entry diskCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.URI != uri {
+		return nil
+	}
+	return &entry
+}
+
+// writeDiskCacheEntry writes entry to filename in dir atomically, so that
+// concurrent readers and writers only ever see whole entries.
+func writeDiskCacheEntry(dir, filename string, entry *diskCacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	if err := os.Rename(f.Name(), filename); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package openapi3
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// ErrURIDisallowed is wrapped by the errors of URIPolicy.
+var ErrURIDisallowed = errors.New("disallowed URI")
+
+// URIPolicy restricts the documents a Loader reads. It is enforced by the
+// loader around its ReadFromURIFunc, whichever it is, for the root document
+// when the loader reads it and for every document a $ref leads to. Empty
+// fields put no restriction.
+type URIPolicy struct {
+	// AllowedSchemes lists the URI schemes that may be read, such as "file"
+	// or "https". A location without a scheme is a "file".
+	AllowedSchemes []string
+
+	// AllowedHosts lists the hosts, without port, that may be read from.
+	// Locations without a host, such as files, are not concerned.
+	AllowedHosts []string
+
+	// AllowedPathPrefixes lists the paths that may be read, with what they
+	// contain. A prefix matches whole path segments: "specs" allows
+	// "specs/openapi.yaml" but not "specs-private/openapi.yaml". Paths are
+	// compared once cleaned, as the loader resolved them: relative ones stay
+	// relative to the working directory.
+	AllowedPathPrefixes []string
+
+	// MaxDocumentSize is the maximum size of a document, in bytes. ReadFromHTTP,
+	// ReadFromFile and ReadFromFS stop reading past it, other readers are
+	// checked once they have read a document.
+	MaxDocumentSize int64
+
+	// MaxDocuments is the maximum number of distinct documents read by a load.
+	// Documents read ahead with PrefetchConcurrency only count once used.
+	MaxDocuments int
+}
+
+// Check returns an error wrapping ErrURIDisallowed when the policy does not
+// allow reading location. It does not check limits.
+func (policy *URIPolicy) Check(location *url.URL) error {
+	scheme := strings.ToLower(location.Scheme)
+	if scheme == "" {
+		scheme = "file"
+	}
+	if len(policy.AllowedSchemes) != 0 && !slices.ContainsFunc(policy.AllowedSchemes, func(allowed string) bool {
+		return strings.EqualFold(allowed, scheme)
+	}) {
+		return fmt.Errorf("%w %q: scheme %q is not allowed", ErrURIDisallowed, location.String(), scheme)
+	}
+
+	if host := location.Hostname(); host != "" && len(policy.AllowedHosts) != 0 && !slices.ContainsFunc(policy.AllowedHosts, func(allowed string) bool {
+		return strings.EqualFold(allowed, host)
+	}) {
+		return fmt.Errorf("%w %q: host %q is not allowed", ErrURIDisallowed, location.String(), host)
+	}
+
+	if len(policy.AllowedPathPrefixes) != 0 {
+		p := path.Clean(location.Path)
+		if !slices.ContainsFunc(policy.AllowedPathPrefixes, func(prefix string) bool {
+			prefix = path.Clean(prefix)
+			return p == prefix || strings.HasPrefix(p, strings.TrimSuffix(prefix, "/")+"/")
+		}) {
+			return fmt.Errorf("%w %q: path %q is not allowed", ErrURIDisallowed, location.String(), p)
+		}
+	}
+	return nil
+}
+
+// read reads location with read, when the policy allows it, and within its
+// size limit.
+func (policy *URIPolicy) read(location *url.URL, read func() ([]byte, error)) ([]byte, error) {
+	if err := policy.Check(location); err != nil {
+		return nil, err
+	}
+	data, err := read()
+	if err != nil {
+		return nil, err
+	}
+	if policy.MaxDocumentSize > 0 && int64(len(data)) > policy.MaxDocumentSize {
+		return nil, documentSizeError(location, policy.MaxDocumentSize)
+	}
+	return data, nil
+}
+
+// reserve adds location to the documents read, which mu guards, within the
+// limit of the policy. It reports whether location was added, rather than
+// already read.
+func (policy *URIPolicy) reserve(location *url.URL, mu *sync.Mutex, documents map[string]struct{}) (bool, error) {
+	uri := location.String()
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := documents[uri]; ok {
+		return false, nil
+	}
+	if policy.MaxDocuments > 0 && len(documents) >= policy.MaxDocuments {
+		return false, fmt.Errorf("%w %q: more than %d documents", ErrURIDisallowed, uri, policy.MaxDocuments)
+	}
+	documents[uri] = struct{}{}
+	return true, nil
+}
+
+// release removes location, which could not be read, from the documents read.
+func (policy *URIPolicy) release(location *url.URL, mu *sync.Mutex, documents map[string]struct{}) {
+	mu.Lock()
+	delete(documents, location.String())
+	mu.Unlock()
+}
+
+// documentSizeError is the error of a document at location larger than limit.
+func documentSizeError(location *url.URL, limit int64) error {
+	return fmt.Errorf("%w %q: document is larger than %d bytes", ErrURIDisallowed, location.String(), limit)
+}
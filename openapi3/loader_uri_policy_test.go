@@ -0,0 +1,211 @@
+package openapi3_test
+
+import (
+	"net/http"
+	"net/url"
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestReadFromFS(t *testing.T) {
+	loader := openapi3.NewLoader()
+	loader.ReadFromURIFunc = openapi3.ReadFromFS(os.DirFS("testdata"))
+	doc, err := loader.LoadFromFile("recursiveRef/openapi.yml")
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(loader.Context))
+	require.Equal(t, "bar", doc.Components.Schemas["Foo"].Value.Properties["bar"].Value.Example)
+
+	// Absolute paths are rooted at the file system
+	_, err = loader.LoadFromFile("/recursiveRef/openapi.yml")
+	require.NoError(t, err)
+}
+
+func TestReadFromFSConfinement(t *testing.T) {
+	fsys := fstest.MapFS{
+		"specs/openapi.yaml": {Data: []byte(`
+openapi: 3.0.3
+info: {title: t, version: v}
+paths: {}
+components:
+  schemas:
+    Pet:
+      $ref: ./pet.yaml
+`)},
+		"specs/pet.yaml":  {Data: []byte(`type: object`)},
+		"specs/up.yaml":   {Data: []byte(`{openapi: 3.0.3, info: {title: t, version: v}, paths: {}, components: {schemas: {A: {$ref: '../../secret.yaml'}}}}`)},
+		"specs/abs.yaml":  {Data: []byte(`{openapi: 3.0.3, info: {title: t, version: v}, paths: {}, components: {schemas: {A: {$ref: '/etc/passwd'}}}}`)},
+		"specs/http.yaml": {Data: []byte(`{openapi: 3.0.3, info: {title: t, version: v}, paths: {}, components: {schemas: {A: {$ref: 'http://169.254.169.254/a.yaml'}}}}`)},
+	}
+
+	loader := openapi3.NewLoader()
+	loader.ReadFromURIFunc = openapi3.ReadFromFS(fsys)
+	doc, err := loader.LoadFromFile("specs/openapi.yaml")
+	require.NoError(t, err)
+	require.Equal(t, &openapi3.Types{"object"}, doc.Components.Schemas["Pet"].Value.Type)
+
+	_, err = loader.LoadFromFile("specs/up.yaml")
+	require.ErrorContains(t, err, `cannot read "../secret.yaml": path is outside of the file system`)
+
+	_, err = loader.LoadFromFile("specs/abs.yaml")
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	_, err = loader.LoadFromFile("specs/http.yaml")
+	require.ErrorIs(t, err, openapi3.ErrURINotSupported)
+}
+
+func TestURIPolicy(t *testing.T) {
+	// A reader serving anything: the policy is enforced regardless
+	served := map[string]string{
+		"https://api.example.com/specs/openapi.yaml": `{openapi: 3.0.3, info: {title: t, version: v}, paths: {}, components: {schemas: {A: {$ref: 'a.yaml'}, B: {$ref: 'b.yaml'}}}}`,
+		"https://api.example.com/specs/a.yaml":       `{type: string}`,
+		"https://api.example.com/specs/b.yaml":       `{type: integer, description: a description long enough for the document to be larger than the root document, which is 124 bytes}`,
+	}
+	read := func(loader *openapi3.Loader, location *url.URL) ([]byte, error) {
+		if data, ok := served[location.String()]; ok {
+			return []byte(data), nil
+		}
+		return nil, os.ErrNotExist
+	}
+	root, err := url.Parse("https://api.example.com/specs/openapi.yaml")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		policy openapi3.URIPolicy
+		err    string
+	}{
+		{
+			name: "no restriction",
+		},
+		{
+			name: "allowed",
+			policy: openapi3.URIPolicy{
+				AllowedSchemes:      []string{"https"},
+				AllowedHosts:        []string{"API.example.com"},
+				AllowedPathPrefixes: []string{"/specs/"},
+				MaxDocumentSize:     1024,
+				MaxDocuments:        3,
+			},
+		},
+		{
+			name:   "scheme",
+			policy: openapi3.URIPolicy{AllowedSchemes: []string{"file"}},
+			err:    `disallowed URI "https://api.example.com/specs/openapi.yaml": scheme "https" is not allowed`,
+		},
+		{
+			name:   "host",
+			policy: openapi3.URIPolicy{AllowedHosts: []string{"example.com"}},
+			err:    `disallowed URI "https://api.example.com/specs/openapi.yaml": host "api.example.com" is not allowed`,
+		},
+		{
+			name:   "path prefix",
+			policy: openapi3.URIPolicy{AllowedPathPrefixes: []string{"/spec"}},
+			err:    `disallowed URI "https://api.example.com/specs/openapi.yaml": path "/specs/openapi.yaml" is not allowed`,
+		},
+		{
+			name:   "document size",
+			policy: openapi3.URIPolicy{MaxDocumentSize: 124},
+			err:    `disallowed URI "https://api.example.com/specs/b.yaml": document is larger than 124 bytes`,
+		},
+		{
+			name:   "documents",
+			policy: openapi3.URIPolicy{MaxDocuments: 2},
+			err:    `disallowed URI "https://api.example.com/specs/b.yaml": more than 2 documents`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			loader := openapi3.NewLoader()
+			loader.ReadFromURIFunc = read
+			loader.URIPolicy = &test.policy
+			doc, err := loader.LoadFromURI(root)
+			if test.err != "" {
+				require.ErrorIs(t, err, openapi3.ErrURIDisallowed)
+				require.ErrorContains(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, &openapi3.Types{"integer"}, doc.Components.Schemas["B"].Value.Type)
+		})
+	}
+}
+
+// endlessReader is an endless document, counting the bytes read from it.
+type endlessReader struct {
+	read int64
+}
+
+func (r *endlessReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = ' '
+	}
+	r.read += int64(len(p))
+	return len(p), nil
+}
+
+func (r *endlessReader) Close() error { return nil }
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestURIPolicyDocumentSizeLimitsReads(t *testing.T) {
+	body := &endlessReader{}
+	client := &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: body, Request: req}, nil
+	})}
+	loader := openapi3.NewLoader()
+	loader.ReadFromURIFunc = openapi3.ReadFromHTTP(client)
+	loader.URIPolicy = &openapi3.URIPolicy{MaxDocumentSize: 1024}
+	root, err := url.Parse("https://api.example.com/openapi.yaml")
+	require.NoError(t, err)
+	_, err = loader.LoadFromURI(root)
+	require.ErrorIs(t, err, openapi3.ErrURIDisallowed)
+	require.ErrorContains(t, err, `disallowed URI "https://api.example.com/openapi.yaml": document is larger than 1024 bytes`)
+	require.LessOrEqual(t, body.read, int64(1025))
+
+	body = &endlessReader{}
+	loader = openapi3.NewLoader()
+	loader.ReadFromURIFunc = openapi3.URIDiskCache(t.TempDir(), client)
+	loader.URIPolicy = &openapi3.URIPolicy{MaxDocumentSize: 1024}
+	_, err = loader.LoadFromURI(root)
+	require.ErrorContains(t, err, `disallowed URI "https://api.example.com/openapi.yaml": document is larger than 1024 bytes`)
+	require.LessOrEqual(t, body.read, int64(1025))
+
+	fsys := fstest.MapFS{"openapi.yaml": {Data: make([]byte, 2048)}}
+	loader = openapi3.NewLoader()
+	loader.ReadFromURIFunc = openapi3.ReadFromFS(fsys)
+	loader.URIPolicy = &openapi3.URIPolicy{MaxDocumentSize: 1024}
+	_, err = loader.LoadFromFile("openapi.yaml")
+	require.ErrorIs(t, err, openapi3.ErrURIDisallowed)
+	require.ErrorContains(t, err, `disallowed URI "openapi.yaml": document is larger than 1024 bytes`)
+}
+
+func TestURIPolicyCheck(t *testing.T) {
+	policy := &openapi3.URIPolicy{
+		AllowedSchemes:      []string{"file", "https"},
+		AllowedPathPrefixes: []string{"specs", "/srv/specs"},
+	}
+	for location, allowed := range map[string]bool{
+		"specs/openapi.yaml":                  true,
+		"file:///srv/specs/openapi.yaml":      true,
+		"https://example.com/srv/specs/a.yml": true,
+		"specs-private/openapi.yaml":          false,
+		"specs/../../etc/passwd":              false,
+		"/etc/passwd":                         false,
+		"http://example.com/srv/specs/a.yml":  false,
+	} {
+		u, err := url.Parse(location)
+		require.NoError(t, err)
+		if allowed {
+			require.NoError(t, policy.Check(u), location)
+		} else {
+			require.ErrorIs(t, policy.Check(u), openapi3.ErrURIDisallowed, location)
+		}
+	}
+}
@@ -1,14 +1,17 @@
 package openapi3
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"net/http"
 	"net/url"
 	"os"
 	"path"
 	"path/filepath"
+	"strings"
 	"sync"
 )
 
@@ -47,13 +50,15 @@ var DefaultReadFromURI = URIMapCache(ReadFromURIs(ReadFromHTTP(http.DefaultClien
 
 // ReadFromHTTP returns a ReadFromURIFunc which uses the given http.Client to
 // read the contents from a remote HTTP URI. This client may be customized to
-// implement timeouts, RFC 7234 caching, etc.
+// implement timeouts, RFC 7234 caching, etc. Requests are made with the
+// Context of the loader, cancelling them cancels the load. No more than the
+// URIPolicy.MaxDocumentSize of the loader is read.
 func ReadFromHTTP(cl *http.Client) ReadFromURIFunc {
 	return func(loader *Loader, location *url.URL) ([]byte, error) {
 		if location.Scheme == "" || location.Host == "" {
 			return nil, ErrURINotSupported
 		}
-		req, err := http.NewRequest("GET", location.String(), nil)
+		req, err := http.NewRequestWithContext(loaderContext(loader), "GET", location.String(), nil)
 		if err != nil {
 			return nil, err
 		}
@@ -65,22 +70,87 @@ func ReadFromHTTP(cl *http.Client) ReadFromURIFunc {
 		if resp.StatusCode > 399 {
 			return nil, fmt.Errorf("error loading %q: request returned status code %d", location.String(), resp.StatusCode)
 		}
-		return io.ReadAll(resp.Body)
+		return readDocument(loader, location, resp.Body)
 	}
 }
 
+// readDocument reads the document at location from r, reading no more than
+// the URIPolicy.MaxDocumentSize of loader, if any.
+func readDocument(loader *Loader, location *url.URL, r io.Reader) ([]byte, error) {
+	if loader == nil || loader.URIPolicy == nil || loader.URIPolicy.MaxDocumentSize <= 0 {
+		return io.ReadAll(r)
+	}
+	limit := loader.URIPolicy.MaxDocumentSize
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, documentSizeError(location, limit)
+	}
+	return data, nil
+}
+
+// loaderContext returns the Context of loader, or the background one.
+func loaderContext(loader *Loader) context.Context {
+	if loader == nil || loader.Context == nil {
+		return context.Background()
+	}
+	return loader.Context
+}
+
 func is_file(location *url.URL) bool {
 	return location.Path != "" &&
 		location.Host == "" &&
 		(location.Scheme == "" || location.Scheme == "file")
 }
 
-// ReadFromFile is a ReadFromURIFunc which reads local file URIs.
+// ReadFromFile is a ReadFromURIFunc which reads local file URIs, no more than
+// the URIPolicy.MaxDocumentSize of the loader.
 func ReadFromFile(loader *Loader, location *url.URL) ([]byte, error) {
 	if !is_file(location) {
 		return nil, ErrURINotSupported
 	}
-	return os.ReadFile(path.Clean(filepath.FromSlash(location.Path)))
+	f, err := os.Open(path.Clean(filepath.FromSlash(location.Path)))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return readDocument(loader, location, f)
+}
+
+// ReadFromFS returns a ReadFromURIFunc which reads local file URIs from fsys,
+// such as an embed.FS, an os.DirFS or a zip.Reader, and nothing else. Paths
+// are taken relative to the root of fsys, absolute ones included, and may not
+// lead out of it: "/openapi.yaml" and "openapi.yaml" are the same file and
+// "../secret.yaml" is an error. Other URIs are left to the next reader with
+// ErrURINotSupported, so on its own ReadFromFS never goes to the network.
+//
+// As for any ReadFromURIFunc, Loader.IsExternalRefsAllowed is not enforced:
+// the documents of fsys are the ones refs may lead to. No more than the
+// URIPolicy.MaxDocumentSize of the loader is read.
+func ReadFromFS(fsys fs.FS) ReadFromURIFunc {
+	return func(loader *Loader, location *url.URL) ([]byte, error) {
+		if !is_file(location) {
+			return nil, ErrURINotSupported
+		}
+		name := path.Clean(location.Path)
+		if path.IsAbs(name) {
+			name = strings.TrimPrefix(name, "/")
+			if name == "" {
+				name = "."
+			}
+		}
+		if !fs.ValidPath(name) {
+			return nil, fmt.Errorf("cannot read %q: path is outside of the file system", location.Path)
+		}
+		f, err := fsys.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return readDocument(loader, location, f)
+	}
 }
 
 // URIMapCache returns a ReadFromURIFunc that caches the contents read from URI
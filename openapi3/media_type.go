@@ -117,7 +117,12 @@ func (mediaType *MediaType) UnmarshalJSON(data []byte) error {
 // Validate returns an error if MediaType does not comply with the OpenAPI spec.
 func (mediaType *MediaType) Validate(ctx context.Context, opts ...ValidationOption) error {
 	ctx = WithValidationOptions(ctx, opts...)
+	return mediaType.validate(ctx, "")
+}
 
+// validate validates the MediaType of contentType, which external examples
+// are decoded as.
+func (mediaType *MediaType) validate(ctx context.Context, contentType string) error {
 	if mediaType == nil {
 		return nil
 	}
@@ -143,10 +148,21 @@ func (mediaType *MediaType) Validate(ctx context.Context, opts ...ValidationOpti
 					if err := v.Validate(ctx); err != nil {
 						return &MediaTypeExampleValidationError{ExampleName: k, Cause: err}
 					}
-					if err := validateExampleValue(ctx, v.Value.Value, schema.Value); err != nil {
+					value, origin := v.Value.Value, exampleValueOrigin(v.Value, mediaType.Origin)
+					if v.Value.ExternalValue != "" {
+						external, ok, err := externalExampleValue(ctx, v.Value, contentType, schema)
+						if err != nil {
+							return &MediaTypeExampleValidationError{ExampleName: k, Cause: err}
+						}
+						if !ok {
+							continue
+						}
+						value, origin = external, externalValueOrigin(v.Value)
+					}
+					if err := validateExampleValue(ctx, value, schema.Value); err != nil {
 						return newSchemaValueError("example",
 							&MediaTypeExampleValidationError{ExampleName: k, Cause: err},
-							exampleValueOrigin(v.Value, mediaType.Origin))
+							origin)
 					}
 				}
 			}
@@ -398,10 +398,21 @@ func (parameter *Parameter) Validate(ctx context.Context, opts ...ValidationOpti
 				if err := v.Validate(ctx); err != nil {
 					return &ParameterExampleValidationError{ExampleName: k, Cause: err}
 				}
-				if err := validateExampleValue(ctx, v.Value.Value, schema.Value); err != nil {
+				value, origin := v.Value.Value, exampleValueOrigin(v.Value, parameter.Origin)
+				if v.Value.ExternalValue != "" {
+					external, ok, err := externalExampleValue(ctx, v.Value, "", schema)
+					if err != nil {
+						return &ParameterExampleValidationError{ExampleName: k, Cause: err}
+					}
+					if !ok {
+						continue
+					}
+					value, origin = external, externalValueOrigin(v.Value)
+				}
+				if err := validateExampleValue(ctx, value, schema.Value); err != nil {
 					return newSchemaValueError("example",
 						&ParameterExampleValidationError{ExampleName: k, Cause: err},
-						exampleValueOrigin(v.Value, parameter.Origin))
+						origin)
 				}
 			}
 		}
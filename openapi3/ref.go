@@ -7,6 +7,7 @@ import (
 )
 
 //go:generate go run refsgenerator.go
+//go:generate go run clonegenerator.go
 
 // Ref represents the common fields of an OpenAPI Reference Object.
 // Summary and Description are supported by OpenAPI 3.1 and later.
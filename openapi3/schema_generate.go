@@ -0,0 +1,627 @@
+package openapi3
+
+import (
+	"encoding/base64"
+	"fmt"
+	"maps"
+	"math"
+	"math/rand/v2"
+	"net/netip"
+	"reflect"
+	"regexp/syntax"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// generateAttempts is how many values Generate makes up before giving up
+	// on a schema none of them is valid against.
+	generateAttempts = 32
+	// generateMaxDepth is the depth of nested values past which optional
+	// properties and array items are left out, so that recursive schemas end,
+	// and the number of times a schema may be nested in itself.
+	generateMaxDepth = 6
+)
+
+// ValueGenerator generates random values valid against schemas, for mocks,
+// fixtures and property-based tests. Its random source is shared by the
+// values it generates: its values depend on its seed and on the schemas it was
+// given, in order, so that what a test generated can be generated again with
+// the same seed and calls. It is safe for concurrent use, but concurrent calls
+// generate values in no particular order.
+type ValueGenerator struct {
+	mu            sync.Mutex
+	rand          *rand.Rand
+	asreq, asrep  bool
+	examples      bool
+	stringFormats map[string]func(r *rand.Rand) string
+
+	// stack holds the schemas of the value being generated, and err why it
+	// could not be
+	stack []*Schema
+	err   error
+}
+
+// ValueGeneratorOption configures a ValueGenerator.
+type ValueGeneratorOption func(*ValueGenerator)
+
+// GenerateAsRequest makes a ValueGenerator leave out readOnly properties, and
+// check its values with VisitAsRequest.
+func GenerateAsRequest() ValueGeneratorOption {
+	return func(g *ValueGenerator) { g.asreq, g.asrep = true, false }
+}
+
+// GenerateAsResponse makes a ValueGenerator leave out writeOnly properties, and
+// check its values with VisitAsResponse.
+func GenerateAsResponse() ValueGeneratorOption {
+	return func(g *ValueGenerator) { g.asreq, g.asrep = false, true }
+}
+
+// GenerateExamples makes a ValueGenerator use the example, the first of the
+// examples or the default of a schema that has one, rather than make up a value.
+func GenerateExamples() ValueGeneratorOption {
+	return func(g *ValueGenerator) { g.examples = true }
+}
+
+// GenerateStringFormat makes a ValueGenerator generate the strings of a format
+// with generate, instead of its built-in generator or from the regexp the
+// format is defined with.
+func GenerateStringFormat(format string, generate func(r *rand.Rand) string) ValueGeneratorOption {
+	return func(g *ValueGenerator) { g.stringFormats[format] = generate }
+}
+
+// NewValueGenerator returns a ValueGenerator whose random values are drawn from
+// seed.
+func NewValueGenerator(seed uint64, opts ...ValueGeneratorOption) *ValueGenerator {
+	g := &ValueGenerator{
+		rand:          rand.New(rand.NewPCG(seed, seed)),
+		stringFormats: make(map[string]func(r *rand.Rand) string),
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// Generate returns a random value valid against schema: a map[string]any for
+// objects, a []any for arrays, a string, an int64 for integers, a float64 for
+// numbers, a bool or nil. Strings follow the pattern or the format of their
+// schema: date, date-time, time, duration, email, hostname, ipv4, ipv6, uri,
+// url, uuid, byte and the formats defined with NewRegexpFormatValidator. The
+// value of a discriminator is that of the oneOf or anyOf schema chosen.
+//
+// Values are checked with VisitJSON and generated again when they are not
+// valid, as may happen with "not", uniqueItems or constraints that contradict
+// each other. Generate returns the error of the last value when none of them is,
+// or when a schema requires itself more than a few times over, such as a
+// property that is required by the schema it refers to.
+func (g *ValueGenerator) Generate(schema *Schema) (any, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var opts []SchemaValidationOption
+	switch {
+	case g.asreq:
+		opts = append(opts, VisitAsRequest())
+	case g.asrep:
+		opts = append(opts, VisitAsResponse())
+	}
+	var err error
+	for range generateAttempts {
+		g.stack, g.err = g.stack[:0], nil
+		value := g.generate(schema, 0)
+		if err = g.err; err != nil {
+			continue
+		}
+		// Validating as a request or a response sets defaults: keep value as is
+		if err = schema.VisitJSON(deepCopyJSONValue(value), opts...); err == nil {
+			return value, nil
+		}
+	}
+	return nil, fmt.Errorf("cannot generate a value valid against the schema: %w", err)
+}
+
+func (g *ValueGenerator) generate(schema *Schema, depth int) any {
+	if schema == nil {
+		return g.generateScalar()
+	}
+	nullable := schema.Nullable || schema.Type.IncludesNull()
+	switch {
+	case schema.Const != nil:
+		return deepCopyJSONValue(schema.Const)
+	case g.examples && schema.Example != nil:
+		return deepCopyJSONValue(schema.Example)
+	case g.examples && len(schema.Examples) != 0:
+		return deepCopyJSONValue(schema.Examples[0])
+	case g.examples && schema.Default != nil:
+		return deepCopyJSONValue(schema.Default)
+	case len(schema.Enum) != 0:
+		return deepCopyJSONValue(schema.Enum[g.rand.IntN(len(schema.Enum))])
+	case nullable && g.rand.IntN(8) == 0:
+		return nil
+	}
+
+	// A schema nested in itself ends, with null when it may
+	if n := countSchema(g.stack, schema); n >= generateMaxDepth {
+		if !nullable && g.err == nil {
+			g.err = fmt.Errorf("schema is nested in itself more than %d times", generateMaxDepth)
+		}
+		return nil
+	}
+	g.stack = append(g.stack, schema)
+	defer func() { g.stack = g.stack[:len(g.stack)-1] }()
+
+	if len(schema.AllOf) != 0 {
+		var value any
+		for _, s := range schema.AllOf {
+			value = mergeGeneratedValues(value, g.generate(s.Value, depth))
+		}
+		if len(schema.Properties) != 0 || schema.Type.Is(TypeObject) {
+			value = mergeGeneratedValues(value, g.generateObject(schema, depth))
+		}
+		return value
+	}
+	if len(schema.OneOf) != 0 {
+		return g.generateOf(schema, schema.OneOf, depth)
+	}
+	if len(schema.AnyOf) != 0 {
+		return g.generateOf(schema, schema.AnyOf, depth)
+	}
+
+	switch g.generateType(schema) {
+	case TypeObject:
+		return g.generateObject(schema, depth)
+	case TypeArray:
+		return g.generateArray(schema, depth)
+	case TypeString:
+		return g.generateString(schema)
+	case TypeInteger:
+		return g.generateInteger(schema)
+	case TypeNumber:
+		return g.generateNumber(schema)
+	case TypeBoolean:
+		return g.rand.IntN(2) == 0
+	}
+	return g.generateScalar()
+}
+
+// countSchema returns how many times schema is in stack.
+func countSchema(stack []*Schema, schema *Schema) int {
+	var n int
+	for _, s := range stack {
+		if s == schema {
+			n++
+		}
+	}
+	return n
+}
+
+// generateType returns one of the types of schema but null, or the type its
+// keywords imply.
+func (g *ValueGenerator) generateType(schema *Schema) string {
+	var types []string
+	for _, typ := range schema.Type.Slice() {
+		if typ != TypeNull {
+			types = append(types, typ)
+		}
+	}
+	switch {
+	case len(types) != 0:
+		return types[g.rand.IntN(len(types))]
+	case len(schema.Properties) != 0 || schema.MinProps != 0 || schema.AdditionalProperties.Schema != nil:
+		return TypeObject
+	case schema.Items != nil || len(schema.PrefixItems) != 0 || schema.MinItems != 0:
+		return TypeArray
+	case schema.Pattern != "" || schema.Format != "" || schema.MinLength != 0 || schema.MaxLength != nil:
+		return TypeString
+	case schema.Min != nil || schema.Max != nil || schema.MultipleOf != nil:
+		return TypeNumber
+	}
+	return ""
+}
+
+// generateScalar returns a value for a schema without constraints.
+func (g *ValueGenerator) generateScalar() any {
+	switch g.rand.IntN(3) {
+	case 0:
+		return g.randomString(1, 8)
+	case 1:
+		return int64(g.rand.IntN(201) - 100)
+	}
+	return g.rand.IntN(2) == 0
+}
+
+// generateOf returns a value of one of the schemas of a oneOf or an anyOf,
+// with the discriminator of schema set for it.
+func (g *ValueGenerator) generateOf(schema *Schema, of SchemaRefs, depth int) any {
+	ref := of[g.rand.IntN(len(of))]
+	value := g.generate(ref.Value, depth)
+	if d := schema.Discriminator; d != nil {
+		if object, ok := value.(map[string]any); ok {
+			object[d.PropertyName] = discriminatorValue(d, ref.Ref)
+		}
+	}
+	return value
+}
+
+// discriminatorValue returns the value of discriminator d for the schema ref
+// points to: its key in the mapping, or otherwise the name of the schema.
+func discriminatorValue(d *Discriminator, ref string) string {
+	for _, key := range componentNames(d.Mapping) {
+		if d.Mapping[key].Ref == ref {
+			return key
+		}
+	}
+	return ref[strings.LastIndexByte(ref, '/')+1:]
+}
+
+func (g *ValueGenerator) generateObject(schema *Schema, depth int) map[string]any {
+	object := make(map[string]any)
+	var optional []string
+	for _, name := range componentNames(schema.Properties) {
+		property := schema.Properties[name].Value
+		if property == nil || g.asreq && property.ReadOnly || g.asrep && property.WriteOnly {
+			continue
+		}
+		if !slices.Contains(schema.Required, name) && (depth >= generateMaxDepth || g.rand.IntN(2) == 0) {
+			optional = append(optional, name)
+			continue
+		}
+		object[name] = g.generate(property, depth+1)
+	}
+	for _, name := range optional {
+		if uint64(len(object)) >= schema.MinProps {
+			break
+		}
+		object[name] = g.generate(schema.Properties[name].Value, depth+1)
+	}
+	if additional := schema.AdditionalProperties; additional.Has == nil || *additional.Has || additional.Schema != nil {
+		for i := 0; uint64(len(object)) < schema.MinProps; i++ {
+			name := fmt.Sprintf("property%d", i)
+			if _, ok := object[name]; ok {
+				continue
+			}
+			var value any
+			if additional.Schema != nil {
+				value = g.generate(additional.Schema.Value, depth+1)
+			} else {
+				value = g.generateScalar()
+			}
+			object[name] = value
+		}
+	}
+	return object
+}
+
+func (g *ValueGenerator) generateArray(schema *Schema, depth int) []any {
+	n := max(schema.MinItems, uint64(len(schema.PrefixItems)))
+	if depth < generateMaxDepth {
+		upper := n + 4
+		if schema.MaxItems != nil {
+			upper = min(upper, *schema.MaxItems)
+		}
+		if upper > n {
+			n += g.rand.Uint64N(upper - n + 1)
+		}
+	}
+	items := make([]any, 0, n)
+	for i := range n {
+		item := schema.Items
+		if i < uint64(len(schema.PrefixItems)) {
+			item = schema.PrefixItems[i]
+		}
+		var itemSchema *Schema
+		if item != nil {
+			itemSchema = item.Value
+		}
+		value := g.generate(itemSchema, depth+1)
+		for attempt := 0; schema.UniqueItems && attempt < 8 && slices.ContainsFunc(items, func(v any) bool { return reflect.DeepEqual(v, value) }); attempt++ {
+			value = g.generate(itemSchema, depth+1)
+		}
+		items = append(items, value)
+	}
+	return items
+}
+
+func (g *ValueGenerator) generateString(schema *Schema) string {
+	if schema.Pattern != "" {
+		if s, ok := g.generatePattern(schema.Pattern); ok {
+			return s
+		}
+	}
+	if schema.Format != "" {
+		if generate := g.stringFormats[schema.Format]; generate != nil {
+			return generate(g.rand)
+		}
+		if generate := stringFormatGenerators[schema.Format]; generate != nil {
+			return generate(g)
+		}
+		if validator, ok := SchemaStringFormats[schema.Format].(stringRegexpFormatValidator); ok {
+			if s, ok := g.generatePattern(validator.re.String()); ok {
+				return s
+			}
+		}
+	}
+	maxLength := schema.MinLength + 16
+	if schema.MaxLength != nil {
+		maxLength = min(maxLength, *schema.MaxLength)
+	}
+	return g.randomString(schema.MinLength, maxLength)
+}
+
+const generateAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// randomString returns a random alphanumeric string of minLength to maxLength
+// characters.
+func (g *ValueGenerator) randomString(minLength, maxLength uint64) string {
+	n := minLength
+	if maxLength > minLength {
+		n += g.rand.Uint64N(maxLength - minLength + 1)
+	}
+	var sb strings.Builder
+	for range n {
+		sb.WriteByte(generateAlphabet[g.rand.IntN(len(generateAlphabet))])
+	}
+	return sb.String()
+}
+
+// randomTime returns a random time between 1970 and 2100.
+func (g *ValueGenerator) randomTime() time.Time {
+	return time.Unix(g.rand.Int64N(4102444800), 0).UTC()
+}
+
+var stringFormatGenerators = map[string]func(g *ValueGenerator) string{
+	"date":      func(g *ValueGenerator) string { return g.randomTime().Format(time.DateOnly) },
+	"date-time": func(g *ValueGenerator) string { return g.randomTime().Format(time.RFC3339) },
+	"time":      func(g *ValueGenerator) string { return g.randomTime().Format("15:04:05Z07:00") },
+	"duration":  func(g *ValueGenerator) string { return fmt.Sprintf("P%dDT%dH", g.rand.IntN(31), g.rand.IntN(24)) },
+	"email": func(g *ValueGenerator) string {
+		return strings.ToLower(g.randomString(1, 12)) + "@example.com"
+	},
+	"hostname": func(g *ValueGenerator) string { return strings.ToLower(g.randomString(1, 12)) + ".example.com" },
+	"ipv4": func(g *ValueGenerator) string {
+		var ip [4]byte
+		for i := range ip {
+			ip[i] = byte(g.rand.UintN(256))
+		}
+		return netip.AddrFrom4(ip).String()
+	},
+	"ipv6": func(g *ValueGenerator) string {
+		var ip [16]byte
+		for i := range ip {
+			ip[i] = byte(g.rand.UintN(256))
+		}
+		return netip.AddrFrom16(ip).String()
+	},
+	"uri": func(g *ValueGenerator) string { return "https://example.com/" + g.randomString(0, 12) },
+	"url": func(g *ValueGenerator) string { return "https://example.com/" + g.randomString(0, 12) },
+	"uuid": func(g *ValueGenerator) string {
+		var u [16]byte
+		for i := range u {
+			u[i] = byte(g.rand.UintN(256))
+		}
+		u[6] = u[6]&0x0f | 0x40 // version 4
+		u[8] = u[8]&0x3f | 0x80 // variant RFC 4122
+		return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16])
+	},
+	"byte": func(g *ValueGenerator) string {
+		data := make([]byte, g.rand.IntN(16))
+		for i := range data {
+			data[i] = byte(g.rand.UintN(256))
+		}
+		return base64.StdEncoding.EncodeToString(data)
+	},
+}
+
+// generatePattern returns a random string matching pattern.
+func (g *ValueGenerator) generatePattern(pattern string) (string, bool) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", false
+	}
+	var sb strings.Builder
+	if !g.generateRegexp(re.Simplify(), &sb) {
+		return "", false
+	}
+	return sb.String(), true
+}
+
+func (g *ValueGenerator) generateRegexp(re *syntax.Regexp, sb *strings.Builder) bool {
+	switch re.Op {
+	case syntax.OpNoMatch:
+		return false
+	case syntax.OpLiteral:
+		sb.WriteString(string(re.Rune))
+	case syntax.OpCharClass:
+		sb.WriteRune(g.generateCharClass(re.Rune))
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		sb.WriteByte(generateAlphabet[g.rand.IntN(len(generateAlphabet))])
+	case syntax.OpCapture:
+		return g.generateRegexp(re.Sub[0], sb)
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		lower, upper := 0, 3
+		switch re.Op {
+		case syntax.OpPlus:
+			lower, upper = 1, 4
+		case syntax.OpQuest:
+			upper = 1
+		case syntax.OpRepeat:
+			lower, upper = re.Min, re.Max
+			if upper < 0 {
+				upper = lower + 3
+			}
+		}
+		for range lower + g.rand.IntN(upper-lower+1) {
+			if !g.generateRegexp(re.Sub[0], sb) {
+				return false
+			}
+		}
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			if !g.generateRegexp(sub, sb) {
+				return false
+			}
+		}
+	case syntax.OpAlternate:
+		return g.generateRegexp(re.Sub[g.rand.IntN(len(re.Sub))], sb)
+	}
+	// Empty matches and assertions such as ^ or $ add nothing
+	return true
+}
+
+// generateCharClass returns a random rune of a character class, given as
+// pairs of bounds, preferably a printable ASCII one.
+func (g *ValueGenerator) generateCharClass(ranges []rune) rune {
+	var printable []rune
+	for i := 0; i+1 < len(ranges); i += 2 {
+		if lo, hi := max(ranges[i], ' '), min(ranges[i+1], '~'); lo <= hi {
+			printable = append(printable, lo, hi)
+		}
+	}
+	if len(printable) != 0 {
+		ranges = printable
+	}
+	var total int
+	for i := 0; i+1 < len(ranges); i += 2 {
+		total += int(ranges[i+1]-ranges[i]) + 1
+	}
+	if total == 0 {
+		return utf8.RuneError
+	}
+	n := g.rand.IntN(total)
+	for i := 0; i+1 < len(ranges); i += 2 {
+		size := int(ranges[i+1]-ranges[i]) + 1
+		if n < size {
+			return ranges[i] + rune(n)
+		}
+		n -= size
+	}
+	return utf8.RuneError
+}
+
+// numberBounds returns the bounds of the values of a number schema, narrowed
+// to the range of its format, and whether they are exclusive.
+func numberBounds(schema *Schema) (lower, upper float64, lowerExclusive, upperExclusive bool) {
+	lower, upper = math.Inf(-1), math.Inf(1)
+	if schema.Min != nil {
+		lower, lowerExclusive = *schema.Min, schema.ExclusiveMin.IsTrue()
+	}
+	if v := schema.ExclusiveMin.Value; v != nil && *v >= lower {
+		lower, lowerExclusive = *v, true
+	}
+	if schema.Max != nil {
+		upper, upperExclusive = *schema.Max, schema.ExclusiveMax.IsTrue()
+	}
+	if v := schema.ExclusiveMax.Value; v != nil && *v <= upper {
+		upper, upperExclusive = *v, true
+	}
+	switch schema.Format {
+	case "int32":
+		lower, upper = max(lower, math.MinInt32), min(upper, math.MaxInt32)
+	case "int64":
+		lower, upper = max(lower, math.MinInt64), min(upper, math.MaxInt64)
+	}
+	// Values are drawn from a window of a thousand around the bounds given
+	switch {
+	case math.IsInf(lower, -1) && math.IsInf(upper, 1):
+		lower, upper = -1000, 1000
+	case math.IsInf(lower, -1):
+		lower = upper - 1000
+	case math.IsInf(upper, 1):
+		upper = lower + 1000
+	}
+	return
+}
+
+func (g *ValueGenerator) generateInteger(schema *Schema) int64 {
+	lower, upper, lowerExclusive, upperExclusive := numberBounds(schema)
+	step := 1.0
+	if schema.MultipleOf != nil && *schema.MultipleOf > 0 {
+		step = *schema.MultipleOf
+	}
+	lo, hi := math.Ceil(lower/step), math.Floor(upper/step)
+	if lowerExclusive && lo*step == lower {
+		lo++
+	}
+	if upperExclusive && hi*step == upper {
+		hi--
+	}
+	if lo > hi {
+		lo, hi = lower/step, lower/step
+	}
+	switch value := g.pickInRange(lo, hi) * step; {
+	case value >= math.MaxInt64:
+		return math.MaxInt64
+	case value <= math.MinInt64:
+		return math.MinInt64
+	default:
+		return int64(value)
+	}
+}
+
+func (g *ValueGenerator) generateNumber(schema *Schema) float64 {
+	lower, upper, lowerExclusive, upperExclusive := numberBounds(schema)
+	if schema.MultipleOf != nil && *schema.MultipleOf > 0 {
+		step := *schema.MultipleOf
+		lo, hi := math.Ceil(lower/step), math.Floor(upper/step)
+		if lowerExclusive && lo*step == lower {
+			lo++
+		}
+		if upperExclusive && hi*step == upper {
+			hi--
+		}
+		if lo <= hi {
+			return g.pickInRange(lo, hi) * step
+		}
+		return lower
+	}
+	value := lower + g.rand.Float64()*(upper-lower)
+	switch g.rand.IntN(8) {
+	case 0:
+		value = lower
+	case 1:
+		value = upper
+	}
+	if lowerExclusive && value <= lower {
+		value = math.Nextafter(lower, upper)
+	}
+	if upperExclusive && value >= upper {
+		value = math.Nextafter(upper, lower)
+	}
+	return value
+}
+
+// pickInRange returns a random integer between lo and hi, which are more
+// likely than the others as edge cases.
+func (g *ValueGenerator) pickInRange(lo, hi float64) float64 {
+	switch g.rand.IntN(8) {
+	case 0:
+		return lo
+	case 1:
+		return hi
+	}
+	return lo + math.Floor(g.rand.Float64()*(hi-lo+1))
+}
+
+// mergeGeneratedValues merges the values generated for the schemas of an allOf.
+func mergeGeneratedValues(a, b any) any {
+	objectA, okA := a.(map[string]any)
+	objectB, okB := b.(map[string]any)
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	case okA && okB:
+		merged := make(map[string]any, len(objectA)+len(objectB))
+		maps.Copy(merged, objectA)
+		for k, v := range objectB {
+			merged[k] = mergeGeneratedValues(merged[k], v)
+		}
+		return merged
+	}
+	return a
+}
@@ -0,0 +1,256 @@
+package openapi3_test
+
+import (
+	"math/rand/v2"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestValueGenerator(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		schema string
+		check  func(t *testing.T, value any)
+	}{
+		{
+			name:   "bounded integer",
+			schema: `{"type": "integer", "format": "int32", "minimum": 10, "maximum": 20, "exclusiveMaximum": true, "multipleOf": 3}`,
+			check: func(t *testing.T, value any) {
+				require.Contains(t, []any{int64(12), int64(15), int64(18)}, value)
+			},
+		},
+		{
+			name:   "bounded number",
+			schema: `{"type": "number", "minimum": 0, "maximum": 1, "exclusiveMinimum": true}`,
+		},
+		{
+			name:   "pattern",
+			schema: `{"type": "string", "pattern": "^[A-Z]{2}-\\d{3,5}(-[a-f]+)?$"}`,
+			check: func(t *testing.T, value any) {
+				require.Regexp(t, `^[A-Z]{2}-\d{3,5}(-[a-f]+)?$`, value)
+			},
+		},
+		{
+			name:   "length",
+			schema: `{"type": "string", "minLength": 3, "maxLength": 5}`,
+		},
+		{
+			name:   "enum",
+			schema: `{"type": "string", "enum": ["a", "b"]}`,
+		},
+		{
+			name:   "const",
+			schema: `{"const": 42}`,
+			check: func(t *testing.T, value any) {
+				require.Equal(t, float64(42), value)
+			},
+		},
+		{
+			name:   "nullable",
+			schema: `{"type": "string", "format": "uuid", "nullable": true}`,
+			check: func(t *testing.T, value any) {
+				if value != nil {
+					require.Regexp(t, openapi3.FormatOfStringForUUIDOfRFC4122, value)
+				}
+			},
+		},
+		{
+			name:   "unique items",
+			schema: `{"type": "array", "minItems": 3, "maxItems": 3, "uniqueItems": true, "items": {"type": "integer", "minimum": 0, "maximum": 3}}`,
+		},
+		{
+			name:   "formats",
+			schema: `{"type": "object", "required": ["date", "dateTime", "email", "ipv4", "ipv6", "byte"], "properties": {"date": {"type": "string", "format": "date"}, "dateTime": {"type": "string", "format": "date-time"}, "email": {"type": "string", "format": "email"}, "ipv4": {"type": "string", "format": "ipv4"}, "ipv6": {"type": "string", "format": "ipv6"}, "byte": {"type": "string", "format": "byte"}}}`,
+			check: func(t *testing.T, value any) {
+				object := value.(map[string]any)
+				require.Regexp(t, openapi3.FormatOfStringDate, object["date"])
+				require.Regexp(t, openapi3.FormatOfStringDateTime, object["dateTime"])
+				require.Regexp(t, openapi3.FormatOfStringForEmail, object["email"])
+				require.Regexp(t, `^\d+\.\d+\.\d+\.\d+$`, object["ipv4"])
+				require.Contains(t, object["ipv6"], ":")
+				require.Regexp(t, openapi3.FormatOfStringByte, object["byte"])
+			},
+		},
+		{
+			name:   "min properties",
+			schema: `{"type": "object", "minProperties": 2, "additionalProperties": {"type": "boolean"}, "properties": {"a": {"type": "string"}}}`,
+		},
+		{
+			name:   "all of",
+			schema: `{"allOf": [{"type": "object", "required": ["a"], "properties": {"a": {"type": "string"}}}, {"type": "object", "required": ["b"], "properties": {"b": {"type": "integer"}}}]}`,
+			check: func(t *testing.T, value any) {
+				require.Contains(t, value, "a")
+				require.Contains(t, value, "b")
+			},
+		},
+		{
+			name:   "not",
+			schema: `{"type": "integer", "minimum": 0, "maximum": 5, "not": {"enum": [0, 5]}}`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var schema openapi3.Schema
+			require.NoError(t, schema.UnmarshalJSON([]byte(test.schema)))
+			for seed := range uint64(50) {
+				value, err := openapi3.NewValueGenerator(seed).Generate(&schema)
+				require.NoError(t, err)
+				require.NoError(t, schema.VisitJSON(value))
+				if test.check != nil {
+					test.check(t, value)
+				}
+			}
+		})
+	}
+}
+
+func TestValueGeneratorSeed(t *testing.T) {
+	var schema openapi3.Schema
+	require.NoError(t, schema.UnmarshalJSON([]byte(`{"type": "array", "items": {"type": "object", "properties": {"id": {"type": "integer"}, "name": {"type": "string"}}}}`)))
+
+	generate := func(seed uint64) []any {
+		g := openapi3.NewValueGenerator(seed)
+		var values []any
+		for range 10 {
+			value, err := g.Generate(&schema)
+			require.NoError(t, err)
+			values = append(values, value)
+		}
+		return values
+	}
+	require.Equal(t, generate(1), generate(1))
+	require.NotEqual(t, generate(1), generate(2))
+}
+
+func TestValueGeneratorDiscriminator(t *testing.T) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData([]byte(`
+openapi: 3.0.3
+info: {title: Pets, version: 1.0.0}
+paths: {}
+components:
+  schemas:
+    Pet:
+      oneOf:
+        - $ref: "#/components/schemas/Cat"
+        - $ref: "#/components/schemas/Dog"
+      discriminator:
+        propertyName: petType
+        mapping:
+          cat: "#/components/schemas/Cat"
+          dog: "#/components/schemas/Dog"
+    Cat:
+      type: object
+      required: [petType, name]
+      properties:
+        petType: {type: string}
+        name: {type: string}
+        id: {type: integer, readOnly: true}
+        password: {type: string, writeOnly: true}
+    Dog:
+      type: object
+      required: [petType, bark]
+      properties:
+        petType: {type: string}
+        bark: {type: boolean}
+`))
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(loader.Context))
+	schema := doc.Components.Schemas["Pet"].Value
+
+	petTypes := make(map[any]bool)
+	for seed := range uint64(20) {
+		value, err := openapi3.NewValueGenerator(seed, openapi3.GenerateAsResponse()).Generate(schema)
+		require.NoError(t, err)
+		pet := value.(map[string]any)
+		petTypes[pet["petType"]] = true
+		require.NotContains(t, pet, "password")
+
+		value, err = openapi3.NewValueGenerator(seed, openapi3.GenerateAsRequest()).Generate(schema)
+		require.NoError(t, err)
+		require.NotContains(t, value, "id")
+	}
+	require.Equal(t, map[any]bool{"cat": true, "dog": true}, petTypes)
+}
+
+func TestValueGeneratorFormats(t *testing.T) {
+	openapi3.DefineStringFormatValidator("sku", openapi3.NewRegexpFormatValidator(`^SKU-[0-9]{6}$`))
+	defer delete(openapi3.SchemaStringFormats, "sku")
+
+	var schema openapi3.Schema
+	require.NoError(t, schema.UnmarshalJSON([]byte(`{"type": "object", "required": ["sku", "color"], "properties": {"sku": {"type": "string", "format": "sku"}, "color": {"type": "string", "format": "color"}}}`)))
+	g := openapi3.NewValueGenerator(7, openapi3.GenerateStringFormat("color", func(r *rand.Rand) string {
+		return []string{"red", "green", "blue"}[r.IntN(3)]
+	}))
+	value, err := g.Generate(&schema)
+	require.NoError(t, err)
+	object := value.(map[string]any)
+	require.Regexp(t, regexp.MustCompile(`^SKU-[0-9]{6}$`), object["sku"])
+	require.Contains(t, []any{"red", "green", "blue"}, object["color"])
+}
+
+func TestValueGeneratorError(t *testing.T) {
+	var schema openapi3.Schema
+	require.NoError(t, schema.UnmarshalJSON([]byte(`{"type": "integer", "minimum": 5, "maximum": 4}`)))
+	_, err := openapi3.NewValueGenerator(0).Generate(&schema)
+	require.ErrorContains(t, err, "cannot generate a value valid against the schema: ")
+}
+
+func TestValueGeneratorRecursive(t *testing.T) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData([]byte(`
+openapi: 3.0.3
+info: {title: Tree, version: 1.0.0}
+paths: {}
+components:
+  schemas:
+    Node:
+      type: object
+      required: [name, children]
+      properties:
+        name: {type: string}
+        children:
+          type: array
+          items: {$ref: "#/components/schemas/Node"}
+        parent: {$ref: "#/components/schemas/Node"}
+    Loop:
+      type: object
+      required: [next]
+      properties:
+        next: {$ref: "#/components/schemas/Loop"}
+    NullableLoop:
+      type: object
+      nullable: true
+      required: [next]
+      properties:
+        next: {$ref: "#/components/schemas/NullableLoop"}
+`))
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(loader.Context))
+
+	g := openapi3.NewValueGenerator(3)
+	for _, name := range []string{"Node", "NullableLoop"} {
+		schema := doc.Components.Schemas[name].Value
+		value, err := g.Generate(schema)
+		require.NoError(t, err, name)
+		require.NoError(t, schema.VisitJSON(value), name)
+	}
+
+	// A required property refers to its own schema, which is not nullable
+	_, err = g.Generate(doc.Components.Schemas["Loop"].Value)
+	require.EqualError(t, err, "cannot generate a value valid against the schema: schema is nested in itself more than 6 times")
+}
+
+func TestValueGeneratorExamples(t *testing.T) {
+	var schema openapi3.Schema
+	require.NoError(t, schema.UnmarshalJSON([]byte(`{"type": "object", "required": ["name", "tag", "age"], "properties": {"name": {"type": "string", "example": "Rex"}, "tag": {"type": "string", "default": "dog"}, "age": {"type": "integer", "minimum": 1}}}`)))
+	value, err := openapi3.NewValueGenerator(0, openapi3.GenerateExamples()).Generate(&schema)
+	require.NoError(t, err)
+	object := value.(map[string]any)
+	require.Equal(t, "Rex", object["name"])
+	require.Equal(t, "dog", object["tag"])
+	require.GreaterOrEqual(t, object["age"], int64(1))
+}
@@ -0,0 +1,637 @@
+package openapi3
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/oasdiff/yaml"
+)
+
+// SplitPart is a part of a document that Split can move to a file of its own.
+type SplitPart struct {
+	// Collection is "paths", "webhooks" or the name of a collection of the
+	// components, such as "schemas" or "responses".
+	Collection string
+	// Name is the path template, the webhook name or the component name.
+	Name string
+}
+
+// SplitLayout returns the slash-separated path, relative to the directory of
+// the root document, of the file that holds part. An empty path keeps part in
+// the root document. A file named ".json" is written as JSON, any other as YAML.
+type SplitLayout func(part SplitPart) string
+
+var splitFileNameRegExp = regexp.MustCompile(`[^A-Za-z0-9._@{}-]`)
+
+// DefaultSplitLayout is the default SplitLayout. Components go to
+// "components/<collection>/<name>.yaml", path items to "paths/<path>.yaml" and
+// webhooks to "webhooks/<name>.yaml", where the slashes of a path are replaced
+// by "@" and the leading one is dropped: "/pets/{id}" goes to
+// "paths/pets@{id}.yaml".
+func DefaultSplitLayout(part SplitPart) string {
+	switch part.Collection {
+	case "paths", "webhooks":
+		name := strings.ReplaceAll(strings.TrimPrefix(part.Name, "/"), "/", "@")
+		name = splitFileNameRegExp.ReplaceAllString(name, "_")
+		if name == "" {
+			name = "_"
+		}
+		return part.Collection + "/" + name + ".yaml"
+	}
+	return "components/" + part.Collection + "/" + part.Name + ".yaml"
+}
+
+// Split is the inverse of Bundle and InternalizeRefs: it lays doc out as a tree
+// of files, in which each path item, webhook and component is moved to the
+// file layout names, and returns the contents of these files keyed by their
+// slash-separated path relative to the directory of the root document, which
+// is keyed by root. A nil layout stands for DefaultSplitLayout.
+//
+// The root document keeps a $ref to each moved part, so that components keep
+// their names, and every local $ref, including discriminator mappings, is
+// rewritten to a relative $ref into the file that now holds its location.
+// Components that are themselves a $ref stay in the root document. A file
+// already taken by an earlier part gets the first free suffix among "_2",
+// "_3", ... before its extension; parts are taken in document order.
+//
+// Loading the root document with Loader.IsExternalRefsAllowed gives back doc,
+// and bundling it gives back its serialization. doc must only hold local
+// $refs, as Bundle leaves it, and is not modified.
+func (doc *T) Split(root string, layout SplitLayout) (map[string][]byte, error) {
+	if layout == nil {
+		layout = DefaultSplitLayout
+	}
+	root = path.Clean(root)
+	if !filepath.IsLocal(filepath.FromSlash(root)) {
+		return nil, fmt.Errorf("root document %q is not a local path", root)
+	}
+
+	s := splitter{
+		doc:   doc.Clone(),
+		root:  root,
+		files: map[string]struct{}{root: {}},
+		parts: make(map[SplitPart]string),
+	}
+	if err := s.plan(layout); err != nil {
+		return nil, err
+	}
+
+	out := make(map[string][]byte, len(s.order)+1)
+	for _, p := range s.order {
+		data, err := s.part(p)
+		if err != nil {
+			return nil, err
+		}
+		out[s.parts[p.part]] = data
+	}
+	data, err := s.document()
+	if err != nil {
+		return nil, err
+	}
+	out[root] = data
+	return out, nil
+}
+
+// splitPart is a part moved to a file, with its value and the $ref that
+// replaces it in the root document.
+type splitPart struct {
+	part  SplitPart
+	value any
+	walk  func(s *splitter)
+	stub  func(ref string)
+}
+
+type splitSite struct {
+	ref string
+	set func(ref string)
+}
+
+type splitter struct {
+	doc   *T
+	root  string
+	order []splitPart
+	// files holds the files taken so far, parts the file of each moved part.
+	files map[string]struct{}
+	parts map[SplitPart]string
+	sites []splitSite
+}
+
+func (s *splitter) plan(layout SplitLayout) error {
+	if c := s.doc.Components; c != nil {
+		for _, name := range componentNames(c.Schemas) {
+			if x := c.Schemas[name]; x != nil && x.Ref == "" && x.Value != nil {
+				s.add(SplitPart{"schemas", name}, x.Value, func(s *splitter) { s.schema(x.Value) }, func(ref string) {
+					c.Schemas[name] = &SchemaRef{Ref: ref}
+				})
+			}
+		}
+		for _, name := range componentNames(c.Parameters) {
+			if x := c.Parameters[name]; x != nil && x.Ref == "" && x.Value != nil {
+				s.add(SplitPart{"parameters", name}, x.Value, func(s *splitter) { s.parameter(x.Value) }, func(ref string) {
+					c.Parameters[name] = &ParameterRef{Ref: ref}
+				})
+			}
+		}
+		for _, name := range componentNames(c.Headers) {
+			if x := c.Headers[name]; x != nil && x.Ref == "" && x.Value != nil {
+				s.add(SplitPart{"headers", name}, x.Value, func(s *splitter) { s.parameter(&x.Value.Parameter) }, func(ref string) {
+					c.Headers[name] = &HeaderRef{Ref: ref}
+				})
+			}
+		}
+		for _, name := range componentNames(c.RequestBodies) {
+			if x := c.RequestBodies[name]; x != nil && x.Ref == "" && x.Value != nil {
+				s.add(SplitPart{"requestBodies", name}, x.Value, func(s *splitter) { s.content(x.Value.Content) }, func(ref string) {
+					c.RequestBodies[name] = &RequestBodyRef{Ref: ref}
+				})
+			}
+		}
+		for _, name := range componentNames(c.Responses) {
+			if x := c.Responses[name]; x != nil && x.Ref == "" && x.Value != nil {
+				s.add(SplitPart{"responses", name}, x.Value, func(s *splitter) { s.response(x.Value) }, func(ref string) {
+					c.Responses[name] = &ResponseRef{Ref: ref}
+				})
+			}
+		}
+		for _, name := range componentNames(c.SecuritySchemes) {
+			if x := c.SecuritySchemes[name]; x != nil && x.Ref == "" && x.Value != nil {
+				s.add(SplitPart{"securitySchemes", name}, x.Value, func(*splitter) {}, func(ref string) {
+					c.SecuritySchemes[name] = &SecuritySchemeRef{Ref: ref}
+				})
+			}
+		}
+		for _, name := range componentNames(c.Examples) {
+			if x := c.Examples[name]; x != nil && x.Ref == "" && x.Value != nil {
+				s.add(SplitPart{"examples", name}, x.Value, func(*splitter) {}, func(ref string) {
+					c.Examples[name] = &ExampleRef{Ref: ref}
+				})
+			}
+		}
+		for _, name := range componentNames(c.Links) {
+			if x := c.Links[name]; x != nil && x.Ref == "" && x.Value != nil {
+				s.add(SplitPart{"links", name}, x.Value, func(*splitter) {}, func(ref string) {
+					c.Links[name] = &LinkRef{Ref: ref}
+				})
+			}
+		}
+		for _, name := range componentNames(c.Callbacks) {
+			if x := c.Callbacks[name]; x != nil && x.Ref == "" && x.Value != nil {
+				s.add(SplitPart{"callbacks", name}, x.Value, func(s *splitter) { s.callback(x.Value) }, func(ref string) {
+					c.Callbacks[name] = &CallbackRef{Ref: ref}
+				})
+			}
+		}
+	}
+	if paths := s.doc.Paths; paths != nil {
+		for _, name := range paths.Keys() {
+			if x := paths.Value(name); x != nil && x.Ref == "" {
+				s.add(SplitPart{"paths", name}, x, func(s *splitter) { s.pathItemValue(x) }, func(ref string) {
+					paths.Set(name, &PathItem{Ref: ref})
+				})
+			}
+		}
+	}
+	for _, name := range componentNames(s.doc.Webhooks) {
+		if x := s.doc.Webhooks[name]; x != nil && x.Ref == "" {
+			s.add(SplitPart{"webhooks", name}, x, func(s *splitter) { s.pathItemValue(x) }, func(ref string) {
+				s.doc.Webhooks[name] = &PathItem{Ref: ref}
+			})
+		}
+	}
+
+	for _, p := range s.order {
+		file := layout(p.part)
+		if file == "" {
+			continue
+		}
+		file = path.Clean(file)
+		if !filepath.IsLocal(filepath.FromSlash(file)) {
+			return fmt.Errorf("file %q of %s %q is not a local path", file, p.part.Collection, p.part.Name)
+		}
+		ext := path.Ext(file)
+		base := strings.TrimSuffix(file, ext)
+		for i := 2; ; i++ {
+			if _, ok := s.files[file]; !ok {
+				break
+			}
+			file = base + "_" + strconv.Itoa(i) + ext
+		}
+		s.files[file] = struct{}{}
+		s.parts[p.part] = file
+	}
+	order := s.order[:0]
+	for _, p := range s.order {
+		if _, ok := s.parts[p.part]; ok {
+			order = append(order, p)
+		}
+	}
+	s.order = order
+	return nil
+}
+
+func (s *splitter) add(part SplitPart, value any, walk func(s *splitter), stub func(ref string)) {
+	s.order = append(s.order, splitPart{part: part, value: value, walk: walk, stub: stub})
+}
+
+// part returns the contents of the file that holds p.
+func (s *splitter) part(p splitPart) ([]byte, error) {
+	file := s.parts[p.part]
+	s.sites = s.sites[:0]
+	p.walk(s)
+	restore, err := s.rewrite(file)
+	defer restore()
+	if err != nil {
+		return nil, err
+	}
+	return marshalSplitFile(file, p.value)
+}
+
+// document returns the contents of the root document.
+func (s *splitter) document() ([]byte, error) {
+	s.sites = s.sites[:0]
+	moved := make(map[any]bool, len(s.order))
+	for _, p := range s.order {
+		moved[p.value] = true
+	}
+	doc := s.doc
+	if c := doc.Components; c != nil {
+		for _, name := range componentNames(c.Schemas) {
+			if x := c.Schemas[name]; x != nil && !moved[x.Value] {
+				s.schemaRef(x)
+			}
+		}
+		for _, name := range componentNames(c.Parameters) {
+			if x := c.Parameters[name]; x != nil && !moved[x.Value] {
+				s.parameterRef(x)
+			}
+		}
+		for _, name := range componentNames(c.Headers) {
+			if x := c.Headers[name]; x != nil && !moved[x.Value] {
+				s.headerRef(x)
+			}
+		}
+		for _, name := range componentNames(c.RequestBodies) {
+			if x := c.RequestBodies[name]; x != nil && !moved[x.Value] {
+				s.requestBodyRef(x)
+			}
+		}
+		for _, name := range componentNames(c.Responses) {
+			if x := c.Responses[name]; x != nil && !moved[x.Value] {
+				s.responseRef(x)
+			}
+		}
+		for _, name := range componentNames(c.SecuritySchemes) {
+			if x := c.SecuritySchemes[name]; x != nil && !moved[x.Value] {
+				s.securitySchemeRef(x)
+			}
+		}
+		for _, name := range componentNames(c.Examples) {
+			if x := c.Examples[name]; x != nil && !moved[x.Value] {
+				s.exampleRef(x)
+			}
+		}
+		for _, name := range componentNames(c.Links) {
+			if x := c.Links[name]; x != nil && !moved[x.Value] {
+				s.linkRef(x)
+			}
+		}
+		for _, name := range componentNames(c.Callbacks) {
+			if x := c.Callbacks[name]; x != nil && !moved[x.Value] {
+				s.callbackRef(x)
+			}
+		}
+	}
+	if doc.Paths != nil {
+		for _, name := range doc.Paths.Keys() {
+			if x := doc.Paths.Value(name); !moved[x] {
+				s.pathItem(x)
+			}
+		}
+	}
+	for _, name := range componentNames(doc.Webhooks) {
+		if x := doc.Webhooks[name]; !moved[x] {
+			s.pathItem(x)
+		}
+	}
+
+	if _, err := s.rewrite(s.root); err != nil {
+		return nil, err
+	}
+	for _, p := range s.order {
+		p.stub(s.relativeRef(s.root, s.parts[p.part], ""))
+	}
+	return marshalSplitFile(s.root, doc)
+}
+
+// rewrite points the $refs collected in file at their new location and
+// returns a function that restores them.
+func (s *splitter) rewrite(file string) (func(), error) {
+	sites := s.sites
+	restore := func() {
+		for _, site := range sites {
+			site.set(site.ref)
+		}
+	}
+	for _, site := range sites {
+		if !strings.HasPrefix(site.ref, "#") {
+			restore()
+			return func() {}, fmt.Errorf("cannot split a document with a non-local $ref %q", site.ref)
+		}
+		target, fragment := s.locate(strings.TrimPrefix(site.ref, "#"))
+		site.set(s.relativeRef(file, target, fragment))
+	}
+	return restore, nil
+}
+
+// locate returns the file and the fragment in it of a location of the root document.
+func (s *splitter) locate(pointer string) (string, string) {
+	tokens := strings.Split(pointer, "/")
+	if len(tokens) < 2 || tokens[0] != "" {
+		return s.root, pointer
+	}
+	var part SplitPart
+	var rest []string
+	switch tokens[1] {
+	case "components":
+		if len(tokens) < 4 {
+			return s.root, pointer
+		}
+		part, rest = SplitPart{tokens[2], splitToken(tokens[3])}, tokens[4:]
+	case "paths", "webhooks":
+		if len(tokens) < 3 {
+			return s.root, pointer
+		}
+		part, rest = SplitPart{tokens[1], splitToken(tokens[2])}, tokens[3:]
+	default:
+		return s.root, pointer
+	}
+	file, ok := s.parts[part]
+	if !ok {
+		return s.root, pointer
+	}
+	if len(rest) == 0 {
+		return file, ""
+	}
+	return file, "/" + strings.Join(rest, "/")
+}
+
+func splitToken(token string) string {
+	if t, err := url.PathUnescape(token); err == nil {
+		token = t
+	}
+	return unescapeRefString(token)
+}
+
+// relativeRef returns the $ref, from file, to fragment in target.
+func (s *splitter) relativeRef(file, target, fragment string) string {
+	if target == file && fragment != "" {
+		return "#" + fragment
+	}
+	ref := path.Base(target)
+	if rel, err := filepath.Rel(filepath.Dir(filepath.FromSlash(file)), filepath.FromSlash(target)); err == nil {
+		ref = filepath.ToSlash(rel)
+	}
+	if fragment != "" {
+		ref += "#" + fragment
+	}
+	return ref
+}
+
+func marshalSplitFile(file string, v any) ([]byte, error) {
+	if path.Ext(file) == ".json" {
+		return json.MarshalIndent(v, "", "  ")
+	}
+	return yaml.Marshal(v)
+}
+
+// site records a $ref found while walking the current file.
+func (s *splitter) site(ref string, set func(string)) {
+	s.sites = append(s.sites, splitSite{ref: ref, set: set})
+}
+
+// The walkers below stop at $refs, since the value behind a $ref is written
+// in the file of its own location.
+
+func (s *splitter) schemaRef(x *SchemaRef) {
+	if x == nil {
+		return
+	}
+	if x.Ref != "" {
+		s.site(x.Ref, func(ref string) { x.Ref = ref })
+		return
+	}
+	s.schema(x.Value)
+}
+
+func (s *splitter) schema(v *Schema) {
+	if v == nil {
+		return
+	}
+	for _, name := range componentNames(v.Properties) {
+		s.schemaRef(v.Properties[name])
+	}
+	for _, list := range []SchemaRefs{v.AllOf, v.AnyOf, v.OneOf, v.PrefixItems} {
+		for _, x := range list {
+			s.schemaRef(x)
+		}
+	}
+	for _, x := range []*SchemaRef{
+		v.Items, v.AdditionalProperties.Schema, v.Not, v.Contains, v.PropertyNames,
+		v.UnevaluatedItems.Schema, v.UnevaluatedProperties.Schema,
+		v.If, v.Then, v.Else, v.ContentSchema,
+	} {
+		s.schemaRef(x)
+	}
+	for _, name := range componentNames(v.PatternProperties) {
+		s.schemaRef(v.PatternProperties[name])
+	}
+	for _, name := range componentNames(v.DependentSchemas) {
+		s.schemaRef(v.DependentSchemas[name])
+	}
+	for _, name := range componentNames(v.Defs) {
+		s.schemaRef(v.Defs[name])
+	}
+
+	// Discriminator mapping values that are not $refs name schemas of the
+	// components, which keep their names.
+	if d := v.Discriminator; d != nil {
+		for _, k := range componentNames(d.Mapping) {
+			if m := d.Mapping[k]; strings.HasPrefix(m.Ref, "#") {
+				s.site(m.Ref, func(ref string) {
+					m := d.Mapping[k]
+					m.Ref = ref
+					d.Mapping[k] = m
+				})
+			}
+		}
+	}
+}
+
+func (s *splitter) parameterRef(x *ParameterRef) {
+	if x == nil {
+		return
+	}
+	if x.Ref != "" {
+		s.site(x.Ref, func(ref string) { x.Ref = ref })
+		return
+	}
+	if x.Value != nil {
+		s.parameter(x.Value)
+	}
+}
+
+func (s *splitter) parameter(p *Parameter) {
+	s.schemaRef(p.Schema)
+	s.content(p.Content)
+	s.examples(p.Examples)
+}
+
+func (s *splitter) headerRef(x *HeaderRef) {
+	if x == nil {
+		return
+	}
+	if x.Ref != "" {
+		s.site(x.Ref, func(ref string) { x.Ref = ref })
+		return
+	}
+	if x.Value != nil {
+		s.parameter(&x.Value.Parameter)
+	}
+}
+
+func (s *splitter) requestBodyRef(x *RequestBodyRef) {
+	if x == nil {
+		return
+	}
+	if x.Ref != "" {
+		s.site(x.Ref, func(ref string) { x.Ref = ref })
+		return
+	}
+	if x.Value != nil {
+		s.content(x.Value.Content)
+	}
+}
+
+func (s *splitter) responseRef(x *ResponseRef) {
+	if x == nil {
+		return
+	}
+	if x.Ref != "" {
+		s.site(x.Ref, func(ref string) { x.Ref = ref })
+		return
+	}
+	if x.Value != nil {
+		s.response(x.Value)
+	}
+}
+
+func (s *splitter) response(r *Response) {
+	for _, name := range componentNames(r.Headers) {
+		s.headerRef(r.Headers[name])
+	}
+	s.content(r.Content)
+	for _, name := range componentNames(r.Links) {
+		s.linkRef(r.Links[name])
+	}
+}
+
+func (s *splitter) content(content Content) {
+	for _, name := range componentNames(content) {
+		mediaType := content[name]
+		if mediaType == nil {
+			continue
+		}
+		s.schemaRef(mediaType.Schema)
+		s.schemaRef(mediaType.ItemSchema)
+		s.examples(mediaType.Examples)
+		for _, name := range componentNames(mediaType.Encoding) {
+			if encoding := mediaType.Encoding[name]; encoding != nil {
+				for _, name := range componentNames(encoding.Headers) {
+					s.headerRef(encoding.Headers[name])
+				}
+			}
+		}
+	}
+}
+
+func (s *splitter) examples(examples Examples) {
+	for _, name := range componentNames(examples) {
+		s.exampleRef(examples[name])
+	}
+}
+
+func (s *splitter) exampleRef(x *ExampleRef) {
+	if x != nil && x.Ref != "" {
+		s.site(x.Ref, func(ref string) { x.Ref = ref })
+	}
+}
+
+func (s *splitter) linkRef(x *LinkRef) {
+	if x != nil && x.Ref != "" {
+		s.site(x.Ref, func(ref string) { x.Ref = ref })
+	}
+}
+
+func (s *splitter) securitySchemeRef(x *SecuritySchemeRef) {
+	if x != nil && x.Ref != "" {
+		s.site(x.Ref, func(ref string) { x.Ref = ref })
+	}
+}
+
+func (s *splitter) callbackRef(x *CallbackRef) {
+	if x == nil {
+		return
+	}
+	if x.Ref != "" {
+		s.site(x.Ref, func(ref string) { x.Ref = ref })
+		return
+	}
+	if x.Value != nil {
+		s.callback(x.Value)
+	}
+}
+
+func (s *splitter) callback(callback *Callback) {
+	for _, expr := range callback.Keys() {
+		s.pathItem(callback.Value(expr))
+	}
+}
+
+func (s *splitter) pathItem(pathItem *PathItem) {
+	if pathItem == nil {
+		return
+	}
+	if pathItem.Ref != "" {
+		s.site(pathItem.Ref, func(ref string) { pathItem.Ref = ref })
+		return
+	}
+	s.pathItemValue(pathItem)
+}
+
+func (s *splitter) pathItemValue(pathItem *PathItem) {
+	for _, p := range pathItem.Parameters {
+		s.parameterRef(p)
+	}
+	operations := pathItem.Operations()
+	for _, method := range componentNames(operations) {
+		op := operations[method]
+		for _, p := range op.Parameters {
+			s.parameterRef(p)
+		}
+		s.requestBodyRef(op.RequestBody)
+		if op.Responses != nil {
+			for _, code := range op.Responses.Keys() {
+				s.responseRef(op.Responses.Value(code))
+			}
+		}
+		for _, name := range componentNames(op.Callbacks) {
+			s.callbackRef(op.Callbacks[name])
+		}
+	}
+}
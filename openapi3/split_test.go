@@ -0,0 +1,132 @@
+package openapi3_test
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// writeSplit writes the files returned by Split to dir.
+func writeSplit(t *testing.T, dir string, files map[string][]byte) {
+	t.Helper()
+	for name, data := range files {
+		filename := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(filename), 0o755))
+		require.NoError(t, os.WriteFile(filename, data, 0o644))
+	}
+}
+
+func TestSplit(t *testing.T) {
+	ctx := t.Context()
+
+	for _, filename := range []string{
+		"testdata/bundle/openapi.yaml",
+		"testdata/testref.openapi.yml",
+		"testdata/recursiveRef/openapi.yml",
+		"testdata/spec.yaml",
+		"testdata/callbacks.yml",
+		"testdata/issue959/openapi.yml",
+		"testdata/discriminator.yml",
+	} {
+		t.Run(filename, func(t *testing.T) {
+			loader := openapi3.NewLoader()
+			loader.IsExternalRefsAllowed = true
+			doc, err := loader.LoadFromFile(filename)
+			require.NoError(t, err)
+			require.NoError(t, doc.Bundle())
+			expected, err := doc.MarshalJSON()
+			require.NoError(t, err)
+
+			files, err := doc.Split("openapi.yaml", nil)
+			require.NoError(t, err)
+			require.Contains(t, files, "openapi.yaml")
+
+			// Splitting leaves the document unchanged
+			actual, err := doc.MarshalJSON()
+			require.NoError(t, err)
+			require.JSONEq(t, string(expected), string(actual))
+
+			dir := t.TempDir()
+			writeSplit(t, dir, files)
+
+			loader = openapi3.NewLoader()
+			loader.IsExternalRefsAllowed = true
+			split, err := loader.LoadFromFile(filepath.Join(dir, "openapi.yaml"))
+			require.NoError(t, err)
+			require.NoError(t, split.Validate(ctx))
+
+			require.NoError(t, split.Bundle())
+			actual, err = split.MarshalJSON()
+			require.NoError(t, err)
+			require.JSONEq(t, string(expected), string(actual))
+		})
+	}
+}
+
+func TestSplitLayout(t *testing.T) {
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+	doc, err := loader.LoadFromFile("testdata/bundle/openapi.yaml")
+	require.NoError(t, err)
+	require.NoError(t, doc.Bundle())
+
+	files, err := doc.Split("openapi.yaml", nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"components/parameters/PageLimit.yaml",
+		"components/schemas/Color.yaml",
+		"components/schemas/Owner.yaml",
+		"components/schemas/Pet.yaml",
+		"components/schemas/Pet_2.yaml",
+		"components/schemas/Tag.yaml",
+		"openapi.yaml",
+		"paths/owners.yaml",
+		"paths/pets.yaml",
+	}, slices.Sorted(maps.Keys(files)))
+	require.Contains(t, string(files["openapi.yaml"]), `$ref: paths/pets.yaml`)
+	require.Contains(t, string(files["paths/owners.yaml"]), `$ref: ../components/schemas/Owner.yaml`)
+
+	// A custom layout keeps schemas in the root document and writes JSON
+	files, err = doc.Split("api/openapi.json", func(part openapi3.SplitPart) string {
+		if part.Collection == "schemas" {
+			return ""
+		}
+		return "api/" + strings.ToLower(openapi3.DefaultSplitLayout(part))
+	})
+	require.NoError(t, err)
+	require.Contains(t, files, "api/openapi.json")
+	require.Contains(t, files, "api/paths/pets.yaml")
+	require.NotContains(t, files, "api/components/schemas/pet.yaml")
+	require.Contains(t, string(files["api/paths/owners.yaml"]), `$ref: ../openapi.json#/components/schemas/Owner`)
+
+	dir := t.TempDir()
+	writeSplit(t, dir, files)
+	loader = openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+	split, err := loader.LoadFromFile(filepath.Join(dir, "api", "openapi.json"))
+	require.NoError(t, err)
+	require.NoError(t, split.Validate(t.Context()))
+}
+
+func TestSplitErrors(t *testing.T) {
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+	doc, err := loader.LoadFromFile("testdata/bundle/openapi.yaml")
+	require.NoError(t, err)
+
+	_, err = doc.Split("openapi.yaml", nil)
+	require.EqualError(t, err, `cannot split a document with a non-local $ref "parameters.yaml#/components/parameters/Limit"`)
+
+	require.NoError(t, doc.Bundle())
+	_, err = doc.Split("../openapi.yaml", nil)
+	require.EqualError(t, err, `root document "../openapi.yaml" is not a local path`)
+	_, err = doc.Split("openapi.yaml", func(part openapi3.SplitPart) string { return "/tmp/" + part.Name })
+	require.Error(t, err)
+}
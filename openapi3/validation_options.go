@@ -19,6 +19,7 @@ type ValidationOptions struct {
 	multiErrorEnabled                                bool
 	regexCompilerFunc                                RegexCompilerFunc
 	extraSiblingFieldsAllowed                        map[string]struct{}
+	exampleDecoder                                   ExampleDecoder
 }
 
 type validationOptionsKey struct{}
@@ -115,6 +116,15 @@ func DisableExamplesValidation() ValidationOption {
 	}
 }
 
+// ExternalExamplesDecoder sets how the contents the ExternalValue of examples
+// point to, read by a Loader with LoadExternalExamples, are decoded before they
+// are validated. By default JSON and YAML are decoded, and text as a string.
+func ExternalExamplesDecoder(decoder ExampleDecoder) ValidationOption {
+	return func(options *ValidationOptions) {
+		options.exampleDecoder = decoder
+	}
+}
+
 // AllowExtensionsWithRef allows extensions (fields starting with 'x-')
 // as siblings for $ref fields. This is the default.
 // Non-extension fields are prohibited unless allowed explicitly with the
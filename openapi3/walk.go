@@ -0,0 +1,541 @@
+package openapi3
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Visitor receives the nodes of a document from Walk.
+//
+// Enter is called for a node before its children are walked, and Leave after.
+// Returning SkipSubtree from Enter skips the children of the node, Leave is
+// still called. Any other non-nil error stops the walk and is returned by Walk.
+type Visitor interface {
+	Enter(c *WalkCursor) error
+	Leave(c *WalkCursor) error
+}
+
+// VisitorFunc is a Visitor that only needs Enter.
+type VisitorFunc func(c *WalkCursor) error
+
+// Enter calls f(c).
+func (f VisitorFunc) Enter(c *WalkCursor) error { return f(c) }
+
+// Leave does nothing.
+func (f VisitorFunc) Leave(c *WalkCursor) error { return nil }
+
+// WalkCursor describes the node Walk is visiting.
+type WalkCursor struct {
+	// JSONPointer is the RFC 6901 JSON Pointer of the node within the
+	// document, such as "/paths/~1pets/get/responses/200". As for WalkSchemas
+	// it is file-agnostic: a node reached through a $ref has the pointer of the
+	// place it is reached from.
+	JSONPointer string
+	// Value is the node: *T, *Info, *Contact, *License, *Server,
+	// *ServerVariable, *Tag, *ExternalDocs, *Components, *Paths, *PathItem,
+	// *Operation, *Responses, *MediaType, *Encoding, or one of the *...Ref types
+	// for the objects that can be references, such as *SchemaRef or
+	// *ResponseRef. The value a *...Ref refers to is not a node of its own:
+	// its children are those of the *...Ref.
+	Value any
+	// Parent is the cursor of the parent node, nil for the document.
+	Parent *WalkCursor
+
+	set func(value any)
+}
+
+// Replace replaces the node in its parent with value, which must have the
+// type of Value, and makes it the Value of the cursor. When called from Enter,
+// Walk then walks the children of value. A nil value removes the node from a
+// map or empties its place otherwise. Replace panics for the document itself.
+func (c *WalkCursor) Replace(value any) {
+	if c.set == nil {
+		panic("openapi3: cannot replace the document being walked")
+	}
+	c.set(value)
+}
+
+// WalkRefs tells Walk which values reached through a $ref to walk into.
+type WalkRefs int
+
+const (
+	// WalkRefsOnce walks into each distinct value the first time it is
+	// reached, whether through a $ref or inline, as WalkSchemas does. This is
+	// the default.
+	WalkRefsOnce WalkRefs = iota
+	// WalkRefsInline never walks into a value reached through a $ref. The
+	// $ref itself is still visited. Values defined in the components are
+	// walked from there.
+	WalkRefsInline
+	// WalkRefsAlways walks into the value of every $ref, as if it was inlined,
+	// except at the cycle points of recursive definitions.
+	WalkRefsAlways
+)
+
+// WalkOption configures Walk.
+type WalkOption func(*walkOptions)
+
+type walkOptions struct {
+	refs WalkRefs
+}
+
+// WalkFollowRefs sets which values reached through a $ref Walk walks into.
+func WalkFollowRefs(refs WalkRefs) WalkOption {
+	return func(opts *walkOptions) {
+		opts.refs = refs
+	}
+}
+
+// Walk visits every node of the document: the document itself, its info,
+// servers, tags, components, paths, webhooks, operations, parameters, request
+// bodies, responses, headers, media types, encodings, examples, links,
+// callbacks, security schemes and schemas. The children of a node are walked
+// in the order of its fields and in sorted key order for maps, after the
+// components for the document, so the traversal is deterministic and
+// components are reached at their definition first.
+//
+// Where a $ref leads is decided by WalkFollowRefs. Visitors can replace nodes
+// in place with WalkCursor.Replace, which makes Walk a building block for
+// transformers as well as for linters or documentation generators.
+func Walk(doc *T, v Visitor, opts ...WalkOption) error {
+	if doc == nil {
+		return nil
+	}
+	w := walker{v: v, seen: make(map[any]struct{}), inlining: make(map[any]int)}
+	for _, opt := range opts {
+		opt(&w.opts)
+	}
+	err := walkNode(&w, nil, "", doc, nil, w.document)
+	if errors.Is(err, SkipSubtree) {
+		err = nil
+	}
+	return err
+}
+
+type walker struct {
+	v        Visitor
+	opts     walkOptions
+	seen     map[any]struct{}
+	inlining map[any]int
+}
+
+// walkNode visits value, unless it is nil, and walks its children with children.
+func walkNode[V comparable](w *walker, parent *WalkCursor, pointer string, value V, set func(V), children func(c *WalkCursor, value V) error) error {
+	var zero V
+	if value == zero {
+		return nil
+	}
+	c := &WalkCursor{JSONPointer: pointer, Value: value, Parent: parent}
+	if set != nil {
+		c.set = func(value any) {
+			v, _ := value.(V)
+			if value != nil && v == zero {
+				panic(fmt.Sprintf("openapi3: cannot replace a %T with a %T", c.Value, value))
+			}
+			set(v)
+			c.Value = v
+		}
+	}
+
+	err := w.v.Enter(c)
+	if err == nil {
+		if value, _ := c.Value.(V); value != zero {
+			err = children(c, value)
+		}
+	} else if errors.Is(err, SkipSubtree) {
+		err = nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := w.v.Leave(c); err != nil && !errors.Is(err, SkipSubtree) {
+		return err
+	}
+	return nil
+}
+
+// walkMap walks the entries of m in sorted key order.
+func walkMap[V comparable](w *walker, parent *WalkCursor, pointer string, m map[string]V, walk func(w *walker, parent *WalkCursor, pointer string, value V, set func(V)) error) error {
+	for _, name := range componentNames(m) {
+		set := func(value V) {
+			var zero V
+			if value == zero {
+				delete(m, name)
+				return
+			}
+			m[name] = value
+		}
+		if err := walk(w, parent, pointer+"/"+EscapeJSONPointerToken(name), m[name], set); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// walkSlice walks the elements of s.
+func walkSlice[V comparable](w *walker, parent *WalkCursor, pointer string, s []V, walk func(w *walker, parent *WalkCursor, pointer string, value V, set func(V)) error) error {
+	for i := range s {
+		if err := walk(w, parent, pointer+"/"+strconv.Itoa(i), s[i], func(value V) { s[i] = value }); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// follow walks into value, the value of a $ref or of an inline definition
+// when ref is empty, unless the policy of the walk rules it out.
+func (w *walker) follow(ref string, value any, walk func() error) error {
+	switch w.opts.refs {
+	case WalkRefsInline:
+		if ref != "" {
+			return nil
+		}
+	case WalkRefsAlways:
+		if w.inlining[value] > 0 {
+			return nil
+		}
+		w.inlining[value]++
+		defer func() { w.inlining[value]-- }()
+	default:
+		if _, ok := w.seen[value]; ok {
+			return nil
+		}
+		w.seen[value] = struct{}{}
+	}
+	return walk()
+}
+
+func (w *walker) document(c *WalkCursor, doc *T) error {
+	if err := walkNode(w, c, "/components", doc.Components, func(v *Components) { doc.Components = v }, w.components); err != nil {
+		return err
+	}
+	if err := walkNode(w, c, "/info", doc.Info, func(v *Info) { doc.Info = v }, w.info); err != nil {
+		return err
+	}
+	if err := walkSlice(w, c, "/servers", doc.Servers, walkServer); err != nil {
+		return err
+	}
+	if err := walkNode(w, c, "/paths", doc.Paths, func(v *Paths) { doc.Paths = v }, w.paths); err != nil {
+		return err
+	}
+	if err := walkMap(w, c, "/webhooks", doc.Webhooks, walkPathItem); err != nil {
+		return err
+	}
+	if err := walkSlice(w, c, "/tags", doc.Tags, walkTag); err != nil {
+		return err
+	}
+	return walkNode(w, c, "/externalDocs", doc.ExternalDocs, func(v *ExternalDocs) { doc.ExternalDocs = v }, noChildren)
+}
+
+func noChildren[V any](*WalkCursor, V) error { return nil }
+
+func (w *walker) components(c *WalkCursor, components *Components) error {
+	p := c.JSONPointer
+	if err := walkMap(w, c, p+"/schemas", components.Schemas, walkSchemaRef); err != nil {
+		return err
+	}
+	if err := walkMap(w, c, p+"/parameters", components.Parameters, walkParameterRef); err != nil {
+		return err
+	}
+	if err := walkMap(w, c, p+"/headers", components.Headers, walkHeaderRef); err != nil {
+		return err
+	}
+	if err := walkMap(w, c, p+"/requestBodies", components.RequestBodies, walkRequestBodyRef); err != nil {
+		return err
+	}
+	if err := walkMap(w, c, p+"/responses", components.Responses, walkResponseRef); err != nil {
+		return err
+	}
+	if err := walkMap(w, c, p+"/securitySchemes", components.SecuritySchemes, walkSecuritySchemeRef); err != nil {
+		return err
+	}
+	if err := walkMap(w, c, p+"/examples", components.Examples, walkExampleRef); err != nil {
+		return err
+	}
+	if err := walkMap(w, c, p+"/links", components.Links, walkLinkRef); err != nil {
+		return err
+	}
+	return walkMap(w, c, p+"/callbacks", components.Callbacks, walkCallbackRef)
+}
+
+func (w *walker) info(c *WalkCursor, info *Info) error {
+	if err := walkNode(w, c, c.JSONPointer+"/contact", info.Contact, func(v *Contact) { info.Contact = v }, noChildren); err != nil {
+		return err
+	}
+	return walkNode(w, c, c.JSONPointer+"/license", info.License, func(v *License) { info.License = v }, noChildren)
+}
+
+func walkServer(w *walker, parent *WalkCursor, pointer string, server *Server, set func(*Server)) error {
+	return walkNode(w, parent, pointer, server, set, func(c *WalkCursor, server *Server) error {
+		return walkMap(w, c, pointer+"/variables", server.Variables, func(w *walker, parent *WalkCursor, pointer string, v *ServerVariable, set func(*ServerVariable)) error {
+			return walkNode(w, parent, pointer, v, set, noChildren)
+		})
+	})
+}
+
+func walkTag(w *walker, parent *WalkCursor, pointer string, tag *Tag, set func(*Tag)) error {
+	return walkNode(w, parent, pointer, tag, set, func(c *WalkCursor, tag *Tag) error {
+		return walkNode(w, c, pointer+"/externalDocs", tag.ExternalDocs, func(v *ExternalDocs) { tag.ExternalDocs = v }, noChildren)
+	})
+}
+
+func (w *walker) paths(c *WalkCursor, paths *Paths) error {
+	for _, path := range paths.Keys() {
+		set := func(value *PathItem) {
+			if value == nil {
+				paths.Delete(path)
+				return
+			}
+			paths.Set(path, value)
+		}
+		if err := walkPathItem(w, c, c.JSONPointer+"/"+EscapeJSONPointerToken(path), paths.Value(path), set); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func walkPathItem(w *walker, parent *WalkCursor, pointer string, pathItem *PathItem, set func(*PathItem)) error {
+	return walkNode(w, parent, pointer, pathItem, set, func(c *WalkCursor, pathItem *PathItem) error {
+		for _, op := range []struct {
+			method string
+			field  **Operation
+		}{
+			{"connect", &pathItem.Connect},
+			{"delete", &pathItem.Delete},
+			{"get", &pathItem.Get},
+			{"head", &pathItem.Head},
+			{"options", &pathItem.Options},
+			{"patch", &pathItem.Patch},
+			{"post", &pathItem.Post},
+			{"put", &pathItem.Put},
+			{"trace", &pathItem.Trace},
+		} {
+			if err := walkNode(w, c, pointer+"/"+op.method, *op.field, func(v *Operation) { *op.field = v }, w.operation); err != nil {
+				return err
+			}
+		}
+		if err := walkSlice(w, c, pointer+"/parameters", pathItem.Parameters, walkParameterRef); err != nil {
+			return err
+		}
+		return walkSlice(w, c, pointer+"/servers", pathItem.Servers, walkServer)
+	})
+}
+
+func (w *walker) operation(c *WalkCursor, op *Operation) error {
+	p := c.JSONPointer
+	if err := walkSlice(w, c, p+"/parameters", op.Parameters, walkParameterRef); err != nil {
+		return err
+	}
+	if err := walkRequestBodyRef(w, c, p+"/requestBody", op.RequestBody, func(v *RequestBodyRef) { op.RequestBody = v }); err != nil {
+		return err
+	}
+	if err := walkNode(w, c, p+"/responses", op.Responses, func(v *Responses) { op.Responses = v }, w.responses); err != nil {
+		return err
+	}
+	if err := walkMap(w, c, p+"/callbacks", op.Callbacks, walkCallbackRef); err != nil {
+		return err
+	}
+	if op.Servers != nil {
+		if err := walkSlice(w, c, p+"/servers", *op.Servers, walkServer); err != nil {
+			return err
+		}
+	}
+	return walkNode(w, c, p+"/externalDocs", op.ExternalDocs, func(v *ExternalDocs) { op.ExternalDocs = v }, noChildren)
+}
+
+func (w *walker) responses(c *WalkCursor, responses *Responses) error {
+	for _, code := range responses.Keys() {
+		set := func(value *ResponseRef) {
+			if value == nil {
+				responses.Delete(code)
+				return
+			}
+			responses.Set(code, value)
+		}
+		if err := walkResponseRef(w, c, c.JSONPointer+"/"+EscapeJSONPointerToken(code), responses.Value(code), set); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func walkSchemaRef(w *walker, parent *WalkCursor, pointer string, x *SchemaRef, set func(*SchemaRef)) error {
+	return walkNode(w, parent, pointer, x, set, func(c *WalkCursor, x *SchemaRef) error {
+		if x.Value == nil {
+			return nil
+		}
+		return w.follow(x.Ref, x.Value, func() error { return w.schema(c, x.Value) })
+	})
+}
+
+func (w *walker) schema(c *WalkCursor, s *Schema) error {
+	p := c.JSONPointer
+	if err := walkMap(w, c, p+"/properties", s.Properties, walkSchemaRef); err != nil {
+		return err
+	}
+	for _, x := range []struct {
+		name  string
+		field **SchemaRef
+	}{
+		{"items", &s.Items},
+		{"additionalProperties", &s.AdditionalProperties.Schema},
+		{"not", &s.Not},
+		{"contains", &s.Contains},
+		{"propertyNames", &s.PropertyNames},
+		{"unevaluatedItems", &s.UnevaluatedItems.Schema},
+		{"unevaluatedProperties", &s.UnevaluatedProperties.Schema},
+		{"if", &s.If},
+		{"then", &s.Then},
+		{"else", &s.Else},
+		{"contentSchema", &s.ContentSchema},
+	} {
+		if err := walkSchemaRef(w, c, p+"/"+x.name, *x.field, func(v *SchemaRef) { *x.field = v }); err != nil {
+			return err
+		}
+	}
+	for _, x := range []struct {
+		name string
+		refs SchemaRefs
+	}{
+		{"allOf", s.AllOf},
+		{"anyOf", s.AnyOf},
+		{"oneOf", s.OneOf},
+		{"prefixItems", s.PrefixItems},
+	} {
+		if err := walkSlice(w, c, p+"/"+x.name, x.refs, walkSchemaRef); err != nil {
+			return err
+		}
+	}
+	if err := walkMap(w, c, p+"/patternProperties", s.PatternProperties, walkSchemaRef); err != nil {
+		return err
+	}
+	if err := walkMap(w, c, p+"/dependentSchemas", s.DependentSchemas, walkSchemaRef); err != nil {
+		return err
+	}
+	if err := walkMap(w, c, p+"/$defs", s.Defs, walkSchemaRef); err != nil {
+		return err
+	}
+	return walkNode(w, c, p+"/externalDocs", s.ExternalDocs, func(v *ExternalDocs) { s.ExternalDocs = v }, noChildren)
+}
+
+func walkParameterRef(w *walker, parent *WalkCursor, pointer string, x *ParameterRef, set func(*ParameterRef)) error {
+	return walkNode(w, parent, pointer, x, set, func(c *WalkCursor, x *ParameterRef) error {
+		if x.Value == nil {
+			return nil
+		}
+		return w.follow(x.Ref, x.Value, func() error { return w.parameter(c, x.Value) })
+	})
+}
+
+func (w *walker) parameter(c *WalkCursor, p *Parameter) error {
+	if err := walkSchemaRef(w, c, c.JSONPointer+"/schema", p.Schema, func(v *SchemaRef) { p.Schema = v }); err != nil {
+		return err
+	}
+	if err := walkMap(w, c, c.JSONPointer+"/content", p.Content, walkMediaType); err != nil {
+		return err
+	}
+	return walkMap(w, c, c.JSONPointer+"/examples", p.Examples, walkExampleRef)
+}
+
+func walkHeaderRef(w *walker, parent *WalkCursor, pointer string, x *HeaderRef, set func(*HeaderRef)) error {
+	return walkNode(w, parent, pointer, x, set, func(c *WalkCursor, x *HeaderRef) error {
+		if x.Value == nil {
+			return nil
+		}
+		return w.follow(x.Ref, x.Value, func() error { return w.parameter(c, &x.Value.Parameter) })
+	})
+}
+
+func walkRequestBodyRef(w *walker, parent *WalkCursor, pointer string, x *RequestBodyRef, set func(*RequestBodyRef)) error {
+	return walkNode(w, parent, pointer, x, set, func(c *WalkCursor, x *RequestBodyRef) error {
+		if x.Value == nil {
+			return nil
+		}
+		return w.follow(x.Ref, x.Value, func() error {
+			return walkMap(w, c, pointer+"/content", x.Value.Content, walkMediaType)
+		})
+	})
+}
+
+func walkResponseRef(w *walker, parent *WalkCursor, pointer string, x *ResponseRef, set func(*ResponseRef)) error {
+	return walkNode(w, parent, pointer, x, set, func(c *WalkCursor, x *ResponseRef) error {
+		if x.Value == nil {
+			return nil
+		}
+		return w.follow(x.Ref, x.Value, func() error {
+			r := x.Value
+			if err := walkMap(w, c, pointer+"/headers", r.Headers, walkHeaderRef); err != nil {
+				return err
+			}
+			if err := walkMap(w, c, pointer+"/content", r.Content, walkMediaType); err != nil {
+				return err
+			}
+			return walkMap(w, c, pointer+"/links", r.Links, walkLinkRef)
+		})
+	})
+}
+
+func walkMediaType(w *walker, parent *WalkCursor, pointer string, mediaType *MediaType, set func(*MediaType)) error {
+	return walkNode(w, parent, pointer, mediaType, set, func(c *WalkCursor, m *MediaType) error {
+		if err := walkSchemaRef(w, c, pointer+"/schema", m.Schema, func(v *SchemaRef) { m.Schema = v }); err != nil {
+			return err
+		}
+		if err := walkSchemaRef(w, c, pointer+"/itemSchema", m.ItemSchema, func(v *SchemaRef) { m.ItemSchema = v }); err != nil {
+			return err
+		}
+		if err := walkMap(w, c, pointer+"/examples", m.Examples, walkExampleRef); err != nil {
+			return err
+		}
+		return walkMap(w, c, pointer+"/encoding", m.Encoding, func(w *walker, parent *WalkCursor, pointer string, e *Encoding, set func(*Encoding)) error {
+			return walkNode(w, parent, pointer, e, set, func(c *WalkCursor, e *Encoding) error {
+				return walkMap(w, c, pointer+"/headers", e.Headers, walkHeaderRef)
+			})
+		})
+	})
+}
+
+func walkSecuritySchemeRef(w *walker, parent *WalkCursor, pointer string, x *SecuritySchemeRef, set func(*SecuritySchemeRef)) error {
+	return walkNode(w, parent, pointer, x, set, noChildren)
+}
+
+func walkExampleRef(w *walker, parent *WalkCursor, pointer string, x *ExampleRef, set func(*ExampleRef)) error {
+	return walkNode(w, parent, pointer, x, set, noChildren)
+}
+
+func walkLinkRef(w *walker, parent *WalkCursor, pointer string, x *LinkRef, set func(*LinkRef)) error {
+	return walkNode(w, parent, pointer, x, set, func(c *WalkCursor, x *LinkRef) error {
+		if x.Value == nil {
+			return nil
+		}
+		return w.follow(x.Ref, x.Value, func() error {
+			link := x.Value
+			return walkServer(w, c, pointer+"/server", link.Server, func(v *Server) { link.Server = v })
+		})
+	})
+}
+
+func walkCallbackRef(w *walker, parent *WalkCursor, pointer string, x *CallbackRef, set func(*CallbackRef)) error {
+	return walkNode(w, parent, pointer, x, set, func(c *WalkCursor, x *CallbackRef) error {
+		if x.Value == nil {
+			return nil
+		}
+		return w.follow(x.Ref, x.Value, func() error {
+			callback := x.Value
+			for _, expr := range callback.Keys() {
+				set := func(value *PathItem) {
+					if value == nil {
+						callback.Delete(expr)
+						return
+					}
+					callback.Set(expr, value)
+				}
+				if err := walkPathItem(w, c, pointer+"/"+EscapeJSONPointerToken(expr), callback.Value(expr), set); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+}
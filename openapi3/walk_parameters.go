@@ -49,13 +49,13 @@ type parameterWalker struct {
 func (w *parameterWalker) document(doc *T) error {
 	if c := doc.Components; c != nil {
 		for _, name := range slices.Sorted(maps.Keys(c.Parameters)) {
-			if err := w.parameter("/components/parameters/"+escapeRefString(name), c.Parameters[name]); err != nil {
+			if err := w.parameter("/components/parameters/"+EscapeJSONPointerToken(name), c.Parameters[name]); err != nil {
 				return err
 			}
 		}
 		for _, name := range slices.Sorted(maps.Keys(c.Callbacks)) {
 			if cbr := c.Callbacks[name]; cbr != nil && cbr.Value != nil {
-				if err := w.callback("/components/callbacks/"+escapeRefString(name), cbr.Value); err != nil {
+				if err := w.callback("/components/callbacks/"+EscapeJSONPointerToken(name), cbr.Value); err != nil {
 					return err
 				}
 			}
@@ -64,13 +64,13 @@ func (w *parameterWalker) document(doc *T) error {
 	if doc.Paths != nil {
 		items := doc.Paths.Map()
 		for _, path := range slices.Sorted(maps.Keys(items)) {
-			if err := w.pathItem("/paths/"+escapeRefString(path), items[path]); err != nil {
+			if err := w.pathItem("/paths/"+EscapeJSONPointerToken(path), items[path]); err != nil {
 				return err
 			}
 		}
 	}
 	for _, name := range slices.Sorted(maps.Keys(doc.Webhooks)) {
-		if err := w.pathItem("/webhooks/"+escapeRefString(name), doc.Webhooks[name]); err != nil {
+		if err := w.pathItem("/webhooks/"+EscapeJSONPointerToken(name), doc.Webhooks[name]); err != nil {
 			return err
 		}
 	}
@@ -97,7 +97,7 @@ func (w *parameterWalker) pathItem(ptr string, item *PathItem) error {
 		}
 		for _, name := range slices.Sorted(maps.Keys(op.Callbacks)) {
 			if cbr := op.Callbacks[name]; cbr != nil && cbr.Value != nil {
-				if err := w.callback(opPtr+"/callbacks/"+escapeRefString(name), cbr.Value); err != nil {
+				if err := w.callback(opPtr+"/callbacks/"+EscapeJSONPointerToken(name), cbr.Value); err != nil {
 					return err
 				}
 			}
@@ -109,7 +109,7 @@ func (w *parameterWalker) pathItem(ptr string, item *PathItem) error {
 func (w *parameterWalker) callback(ptr string, cb *Callback) error {
 	items := cb.Map()
 	for _, expr := range slices.Sorted(maps.Keys(items)) {
-		if err := w.pathItem(ptr+"/"+escapeRefString(expr), items[expr]); err != nil {
+		if err := w.pathItem(ptr+"/"+EscapeJSONPointerToken(expr), items[expr]); err != nil {
 			return err
 		}
 	}
@@ -12,6 +12,9 @@ import (
 // descend into the current schema's sub-schemas. The schema itself has already
 // been visited. Any other non-nil error stops the walk and is returned by
 // WalkSchemas. It mirrors filepath.SkipDir.
+//
+// Returned by Visitor.Enter, it tells Walk not to walk the children of the
+// current node.
 var SkipSubtree = errors.New("skip schema subtree")
 
 // WalkSchemasFunc is called once for each schema visited by WalkSchemas.
@@ -59,47 +62,38 @@ type schemaWalker struct {
 	seen map[*Schema]struct{}
 }
 
-// escapeRefString escapes a single JSON Pointer reference token per RFC 6901:
-// '~' becomes '~0' and '/' becomes '~1'. It is the inverse of unescapeRefString.
-func escapeRefString(s string) string {
-	if !strings.ContainsAny(s, "~/") {
-		return s
-	}
-	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
-}
-
 func (w *schemaWalker) document(doc *T) error {
 	if c := doc.Components; c != nil {
 		for _, name := range slices.Sorted(maps.Keys(c.Schemas)) {
-			if err := w.schemaRef("/components/schemas/"+escapeRefString(name), c.Schemas[name]); err != nil {
+			if err := w.schemaRef("/components/schemas/"+EscapeJSONPointerToken(name), c.Schemas[name]); err != nil {
 				return err
 			}
 		}
 		for _, name := range slices.Sorted(maps.Keys(c.Parameters)) {
-			if err := w.parameter("/components/parameters/"+escapeRefString(name), c.Parameters[name]); err != nil {
+			if err := w.parameter("/components/parameters/"+EscapeJSONPointerToken(name), c.Parameters[name]); err != nil {
 				return err
 			}
 		}
 		for _, name := range slices.Sorted(maps.Keys(c.Headers)) {
-			if err := w.header("/components/headers/"+escapeRefString(name), c.Headers[name]); err != nil {
+			if err := w.header("/components/headers/"+EscapeJSONPointerToken(name), c.Headers[name]); err != nil {
 				return err
 			}
 		}
 		for _, name := range slices.Sorted(maps.Keys(c.RequestBodies)) {
 			if rbr := c.RequestBodies[name]; rbr != nil && rbr.Value != nil {
-				if err := w.content("/components/requestBodies/"+escapeRefString(name)+"/content", rbr.Value.Content); err != nil {
+				if err := w.content("/components/requestBodies/"+EscapeJSONPointerToken(name)+"/content", rbr.Value.Content); err != nil {
 					return err
 				}
 			}
 		}
 		for _, name := range slices.Sorted(maps.Keys(c.Responses)) {
-			if err := w.response("/components/responses/"+escapeRefString(name), c.Responses[name]); err != nil {
+			if err := w.response("/components/responses/"+EscapeJSONPointerToken(name), c.Responses[name]); err != nil {
 				return err
 			}
 		}
 		for _, name := range slices.Sorted(maps.Keys(c.Callbacks)) {
 			if cbr := c.Callbacks[name]; cbr != nil && cbr.Value != nil {
-				if err := w.callback("/components/callbacks/"+escapeRefString(name), cbr.Value); err != nil {
+				if err := w.callback("/components/callbacks/"+EscapeJSONPointerToken(name), cbr.Value); err != nil {
 					return err
 				}
 			}
@@ -108,13 +102,13 @@ func (w *schemaWalker) document(doc *T) error {
 	if doc.Paths != nil {
 		items := doc.Paths.Map()
 		for _, path := range slices.Sorted(maps.Keys(items)) {
-			if err := w.pathItem("/paths/"+escapeRefString(path), items[path]); err != nil {
+			if err := w.pathItem("/paths/"+EscapeJSONPointerToken(path), items[path]); err != nil {
 				return err
 			}
 		}
 	}
 	for _, name := range slices.Sorted(maps.Keys(doc.Webhooks)) {
-		if err := w.pathItem("/webhooks/"+escapeRefString(name), doc.Webhooks[name]); err != nil {
+		if err := w.pathItem("/webhooks/"+EscapeJSONPointerToken(name), doc.Webhooks[name]); err != nil {
 			return err
 		}
 	}
@@ -156,14 +150,14 @@ func (w *schemaWalker) operation(ptr string, op *Operation) error {
 	if op.Responses != nil {
 		responses := op.Responses.Map()
 		for _, code := range slices.Sorted(maps.Keys(responses)) {
-			if err := w.response(ptr+"/responses/"+escapeRefString(code), responses[code]); err != nil {
+			if err := w.response(ptr+"/responses/"+EscapeJSONPointerToken(code), responses[code]); err != nil {
 				return err
 			}
 		}
 	}
 	for _, name := range slices.Sorted(maps.Keys(op.Callbacks)) {
 		if cbr := op.Callbacks[name]; cbr != nil && cbr.Value != nil {
-			if err := w.callback(ptr+"/callbacks/"+escapeRefString(name), cbr.Value); err != nil {
+			if err := w.callback(ptr+"/callbacks/"+EscapeJSONPointerToken(name), cbr.Value); err != nil {
 				return err
 			}
 		}
@@ -174,7 +168,7 @@ func (w *schemaWalker) operation(ptr string, op *Operation) error {
 func (w *schemaWalker) callback(ptr string, cb *Callback) error {
 	items := cb.Map()
 	for _, expr := range slices.Sorted(maps.Keys(items)) {
-		if err := w.pathItem(ptr+"/"+escapeRefString(expr), items[expr]); err != nil {
+		if err := w.pathItem(ptr+"/"+EscapeJSONPointerToken(expr), items[expr]); err != nil {
 			return err
 		}
 	}
@@ -206,7 +200,7 @@ func (w *schemaWalker) response(ptr string, rr *ResponseRef) error {
 		return nil
 	}
 	for _, name := range slices.Sorted(maps.Keys(rr.Value.Headers)) {
-		if err := w.header(ptr+"/headers/"+escapeRefString(name), rr.Value.Headers[name]); err != nil {
+		if err := w.header(ptr+"/headers/"+EscapeJSONPointerToken(name), rr.Value.Headers[name]); err != nil {
 			return err
 		}
 	}
@@ -219,10 +213,10 @@ func (w *schemaWalker) content(ptr string, content Content) error {
 		if media == nil {
 			continue
 		}
-		if err := w.schemaRef(ptr+"/"+escapeRefString(mediaType)+"/schema", media.Schema); err != nil {
+		if err := w.schemaRef(ptr+"/"+EscapeJSONPointerToken(mediaType)+"/schema", media.Schema); err != nil {
 			return err
 		}
-		if err := w.schemaRef(ptr+"/"+escapeRefString(mediaType)+"/itemSchema", media.ItemSchema); err != nil {
+		if err := w.schemaRef(ptr+"/"+EscapeJSONPointerToken(mediaType)+"/itemSchema", media.ItemSchema); err != nil {
 			return err
 		}
 	}
@@ -257,7 +251,7 @@ func (w *schemaWalker) schemaRef(ptr string, sr *SchemaRef) error {
 	}
 
 	for _, name := range slices.Sorted(maps.Keys(s.Properties)) {
-		if err := w.schemaRef(ptr+"/properties/"+escapeRefString(name), s.Properties[name]); err != nil {
+		if err := w.schemaRef(ptr+"/properties/"+EscapeJSONPointerToken(name), s.Properties[name]); err != nil {
 			return err
 		}
 	}
@@ -288,12 +282,12 @@ func (w *schemaWalker) schemaRef(ptr string, sr *SchemaRef) error {
 		return err
 	}
 	for _, name := range slices.Sorted(maps.Keys(s.PatternProperties)) {
-		if err := w.schemaRef(ptr+"/patternProperties/"+escapeRefString(name), s.PatternProperties[name]); err != nil {
+		if err := w.schemaRef(ptr+"/patternProperties/"+EscapeJSONPointerToken(name), s.PatternProperties[name]); err != nil {
 			return err
 		}
 	}
 	for _, name := range slices.Sorted(maps.Keys(s.DependentSchemas)) {
-		if err := w.schemaRef(ptr+"/dependentSchemas/"+escapeRefString(name), s.DependentSchemas[name]); err != nil {
+		if err := w.schemaRef(ptr+"/dependentSchemas/"+EscapeJSONPointerToken(name), s.DependentSchemas[name]); err != nil {
 			return err
 		}
 	}
@@ -310,7 +304,7 @@ func (w *schemaWalker) schemaRef(ptr string, sr *SchemaRef) error {
 		return err
 	}
 	for _, name := range slices.Sorted(maps.Keys(s.Defs)) {
-		if err := w.schemaRef(ptr+"/$defs/"+escapeRefString(name), s.Defs[name]); err != nil {
+		if err := w.schemaRef(ptr+"/$defs/"+EscapeJSONPointerToken(name), s.Defs[name]); err != nil {
 			return err
 		}
 	}
@@ -0,0 +1,318 @@
+package openapi3_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// walkAllSpec has one of each kind of node Walk visits.
+const walkAllSpec = `
+openapi: 3.1.0
+info:
+  title: All
+  version: 1.0.0
+  contact: {name: me}
+  license: {name: MIT}
+servers:
+- url: https://{env}.example.com
+  variables:
+    env: {default: api}
+tags:
+- name: pets
+  externalDocs: {url: https://example.com/pets}
+paths:
+  /pets/{id}:
+    parameters:
+    - $ref: '#/components/parameters/ID'
+    post:
+      requestBody:
+        content:
+          multipart/form-data:
+            schema:
+              type: object
+              properties:
+                photo: {type: string}
+            encoding:
+              photo:
+                headers:
+                  X-Rate:
+                    schema: {type: integer}
+      responses:
+        '201':
+          description: created
+          links:
+            self:
+              operationId: getPet
+              server: {url: https://example.com}
+          content:
+            application/json:
+              examples:
+                rex: {value: {name: rex}}
+      callbacks:
+        done:
+          '{$request.body#/url}':
+            post:
+              responses:
+                '200': {description: ok}
+webhooks:
+  newPet:
+    post:
+      responses:
+        '200': {description: ok}
+components:
+  parameters:
+    ID:
+      name: id
+      in: path
+      required: true
+      schema: {type: string}
+  securitySchemes:
+    key: {type: apiKey, name: key, in: header}
+`
+
+func collectWalk(t *testing.T, doc *openapi3.T, opts ...openapi3.WalkOption) []string {
+	t.Helper()
+	var visited []string
+	err := openapi3.Walk(doc, openapi3.VisitorFunc(func(c *openapi3.WalkCursor) error {
+		visited = append(visited, fmt.Sprintf("%s %T", c.JSONPointer, c.Value))
+		return nil
+	}), opts...)
+	require.NoError(t, err)
+	return visited
+}
+
+func TestWalk(t *testing.T) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData([]byte(walkAllSpec))
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(loader.Context))
+
+	require.Equal(t, []string{
+		` *openapi3.T`,
+		`/components *openapi3.Components`,
+		`/components/parameters/ID *openapi3.ParameterRef`,
+		`/components/parameters/ID/schema *openapi3.SchemaRef`,
+		`/components/securitySchemes/key *openapi3.SecuritySchemeRef`,
+		`/info *openapi3.Info`,
+		`/info/contact *openapi3.Contact`,
+		`/info/license *openapi3.License`,
+		`/servers/0 *openapi3.Server`,
+		`/servers/0/variables/env *openapi3.ServerVariable`,
+		`/paths *openapi3.Paths`,
+		`/paths/~1pets~1{id} *openapi3.PathItem`,
+		`/paths/~1pets~1{id}/post *openapi3.Operation`,
+		`/paths/~1pets~1{id}/post/requestBody *openapi3.RequestBodyRef`,
+		`/paths/~1pets~1{id}/post/requestBody/content/multipart~1form-data *openapi3.MediaType`,
+		`/paths/~1pets~1{id}/post/requestBody/content/multipart~1form-data/schema *openapi3.SchemaRef`,
+		`/paths/~1pets~1{id}/post/requestBody/content/multipart~1form-data/schema/properties/photo *openapi3.SchemaRef`,
+		`/paths/~1pets~1{id}/post/requestBody/content/multipart~1form-data/encoding/photo *openapi3.Encoding`,
+		`/paths/~1pets~1{id}/post/requestBody/content/multipart~1form-data/encoding/photo/headers/X-Rate *openapi3.HeaderRef`,
+		`/paths/~1pets~1{id}/post/requestBody/content/multipart~1form-data/encoding/photo/headers/X-Rate/schema *openapi3.SchemaRef`,
+		`/paths/~1pets~1{id}/post/responses *openapi3.Responses`,
+		`/paths/~1pets~1{id}/post/responses/201 *openapi3.ResponseRef`,
+		`/paths/~1pets~1{id}/post/responses/201/content/application~1json *openapi3.MediaType`,
+		`/paths/~1pets~1{id}/post/responses/201/content/application~1json/examples/rex *openapi3.ExampleRef`,
+		`/paths/~1pets~1{id}/post/responses/201/links/self *openapi3.LinkRef`,
+		`/paths/~1pets~1{id}/post/responses/201/links/self/server *openapi3.Server`,
+		`/paths/~1pets~1{id}/post/callbacks/done *openapi3.CallbackRef`,
+		`/paths/~1pets~1{id}/post/callbacks/done/{$request.body#~1url} *openapi3.PathItem`,
+		`/paths/~1pets~1{id}/post/callbacks/done/{$request.body#~1url}/post *openapi3.Operation`,
+		`/paths/~1pets~1{id}/post/callbacks/done/{$request.body#~1url}/post/responses *openapi3.Responses`,
+		`/paths/~1pets~1{id}/post/callbacks/done/{$request.body#~1url}/post/responses/200 *openapi3.ResponseRef`,
+		`/paths/~1pets~1{id}/parameters/0 *openapi3.ParameterRef`,
+		`/webhooks/newPet *openapi3.PathItem`,
+		`/webhooks/newPet/post *openapi3.Operation`,
+		`/webhooks/newPet/post/responses *openapi3.Responses`,
+		`/webhooks/newPet/post/responses/200 *openapi3.ResponseRef`,
+		`/tags/0 *openapi3.Tag`,
+		`/tags/0/externalDocs *openapi3.ExternalDocs`,
+	}, collectWalk(t, doc))
+}
+
+func TestWalkFollowRefs(t *testing.T) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData([]byte(dereferenceSpec))
+	require.NoError(t, err)
+
+	components := []string{
+		` *openapi3.T`,
+		`/components *openapi3.Components`,
+		`/components/schemas/Depth *openapi3.SchemaRef`,
+		`/components/schemas/Node *openapi3.SchemaRef`,
+		`/components/schemas/Node/properties/children *openapi3.SchemaRef`,
+		`/components/schemas/Node/properties/children/items *openapi3.SchemaRef`,
+		`/components/schemas/Node/properties/depth *openapi3.SchemaRef`,
+		`/components/parameters/Depth *openapi3.ParameterRef`,
+		`/components/parameters/Depth/schema *openapi3.SchemaRef`,
+		`/components/responses/Node *openapi3.ResponseRef`,
+		`/components/responses/Node/content/application~1json *openapi3.MediaType`,
+		`/components/responses/Node/content/application~1json/schema *openapi3.SchemaRef`,
+	}
+	paths := []string{
+		`/info *openapi3.Info`,
+		`/paths *openapi3.Paths`,
+		`/paths/~1nodes *openapi3.PathItem`,
+		`/paths/~1nodes/get *openapi3.Operation`,
+		`/paths/~1nodes/get/parameters/0 *openapi3.ParameterRef`,
+		`/paths/~1nodes/get/responses *openapi3.Responses`,
+		`/paths/~1nodes/get/responses/200 *openapi3.ResponseRef`,
+	}
+
+	// Referenced values are walked where they are defined
+	once := append(append([]string{}, components...), paths...)
+	require.Equal(t, once, collectWalk(t, doc))
+	require.Equal(t, once, collectWalk(t, doc, openapi3.WalkFollowRefs(openapi3.WalkRefsOnce)))
+	require.Equal(t, once, collectWalk(t, doc, openapi3.WalkFollowRefs(openapi3.WalkRefsInline)))
+
+	// Referenced values are walked everywhere, the recursion of Node stopping
+	// where it refers to itself
+	require.Equal(t, []string{
+		` *openapi3.T`,
+		`/components *openapi3.Components`,
+		`/components/schemas/Depth *openapi3.SchemaRef`,
+		`/components/schemas/Node *openapi3.SchemaRef`,
+		`/components/schemas/Node/properties/children *openapi3.SchemaRef`,
+		`/components/schemas/Node/properties/children/items *openapi3.SchemaRef`,
+		`/components/schemas/Node/properties/depth *openapi3.SchemaRef`,
+		`/components/parameters/Depth *openapi3.ParameterRef`,
+		`/components/parameters/Depth/schema *openapi3.SchemaRef`,
+		`/components/responses/Node *openapi3.ResponseRef`,
+		`/components/responses/Node/content/application~1json *openapi3.MediaType`,
+		`/components/responses/Node/content/application~1json/schema *openapi3.SchemaRef`,
+		`/components/responses/Node/content/application~1json/schema/properties/children *openapi3.SchemaRef`,
+		`/components/responses/Node/content/application~1json/schema/properties/children/items *openapi3.SchemaRef`,
+		`/components/responses/Node/content/application~1json/schema/properties/depth *openapi3.SchemaRef`,
+		`/info *openapi3.Info`,
+		`/paths *openapi3.Paths`,
+		`/paths/~1nodes *openapi3.PathItem`,
+		`/paths/~1nodes/get *openapi3.Operation`,
+		`/paths/~1nodes/get/parameters/0 *openapi3.ParameterRef`,
+		`/paths/~1nodes/get/parameters/0/schema *openapi3.SchemaRef`,
+		`/paths/~1nodes/get/responses *openapi3.Responses`,
+		`/paths/~1nodes/get/responses/200 *openapi3.ResponseRef`,
+		`/paths/~1nodes/get/responses/200/content/application~1json *openapi3.MediaType`,
+		`/paths/~1nodes/get/responses/200/content/application~1json/schema *openapi3.SchemaRef`,
+		`/paths/~1nodes/get/responses/200/content/application~1json/schema/properties/children *openapi3.SchemaRef`,
+		`/paths/~1nodes/get/responses/200/content/application~1json/schema/properties/children/items *openapi3.SchemaRef`,
+		`/paths/~1nodes/get/responses/200/content/application~1json/schema/properties/depth *openapi3.SchemaRef`,
+	}, collectWalk(t, doc, openapi3.WalkFollowRefs(openapi3.WalkRefsAlways)))
+}
+
+type recordingVisitor struct {
+	events []string
+	skip   string
+}
+
+func (v *recordingVisitor) Enter(c *openapi3.WalkCursor) error {
+	v.events = append(v.events, "enter "+c.JSONPointer)
+	if c.JSONPointer == v.skip {
+		return openapi3.SkipSubtree
+	}
+	return nil
+}
+
+func (v *recordingVisitor) Leave(c *openapi3.WalkCursor) error {
+	v.events = append(v.events, "leave "+c.JSONPointer)
+	return nil
+}
+
+func TestWalkSkipSubtree(t *testing.T) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData([]byte(dereferenceSpec))
+	require.NoError(t, err)
+
+	v := &recordingVisitor{skip: "/components"}
+	require.NoError(t, openapi3.Walk(doc, v))
+	require.Equal(t, []string{
+		`enter `,
+		`enter /components`,
+		`leave /components`,
+		`enter /info`,
+		`leave /info`,
+		`enter /paths`,
+		`enter /paths/~1nodes`,
+		`enter /paths/~1nodes/get`,
+		`enter /paths/~1nodes/get/parameters/0`,
+		`enter /paths/~1nodes/get/parameters/0/schema`,
+		`leave /paths/~1nodes/get/parameters/0/schema`,
+		`leave /paths/~1nodes/get/parameters/0`,
+		`enter /paths/~1nodes/get/responses`,
+		`enter /paths/~1nodes/get/responses/200`,
+		`enter /paths/~1nodes/get/responses/200/content/application~1json`,
+		`enter /paths/~1nodes/get/responses/200/content/application~1json/schema`,
+		`enter /paths/~1nodes/get/responses/200/content/application~1json/schema/properties/children`,
+		`enter /paths/~1nodes/get/responses/200/content/application~1json/schema/properties/children/items`,
+		`leave /paths/~1nodes/get/responses/200/content/application~1json/schema/properties/children/items`,
+		`leave /paths/~1nodes/get/responses/200/content/application~1json/schema/properties/children`,
+		`enter /paths/~1nodes/get/responses/200/content/application~1json/schema/properties/depth`,
+		`leave /paths/~1nodes/get/responses/200/content/application~1json/schema/properties/depth`,
+		`leave /paths/~1nodes/get/responses/200/content/application~1json/schema`,
+		`leave /paths/~1nodes/get/responses/200/content/application~1json`,
+		`leave /paths/~1nodes/get/responses/200`,
+		`leave /paths/~1nodes/get/responses`,
+		`leave /paths/~1nodes/get`,
+		`leave /paths/~1nodes`,
+		`leave /paths`,
+		`leave `,
+	}, v.events)
+}
+
+func TestWalkReplace(t *testing.T) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData([]byte(dereferenceSpec))
+	require.NoError(t, err)
+
+	// Inline every reference to the Depth schema and drop the responses
+	depth := doc.Components.Schemas["Depth"].Value
+	err = openapi3.Walk(doc, openapi3.VisitorFunc(func(c *openapi3.WalkCursor) error {
+		switch x := c.Value.(type) {
+		case *openapi3.SchemaRef:
+			if x.Ref == "#/components/schemas/Depth" {
+				c.Replace(&openapi3.SchemaRef{Value: depth})
+			}
+		case *openapi3.ResponseRef:
+			if c.Parent.Value == doc.Components {
+				c.Replace(nil)
+			}
+		}
+		return nil
+	}))
+	require.NoError(t, err)
+
+	require.Empty(t, doc.Components.Schemas["Node"].Value.Properties["depth"].Ref)
+	require.Same(t, depth, doc.Components.Schemas["Node"].Value.Properties["depth"].Value)
+	require.Empty(t, doc.Components.Parameters["Depth"].Value.Schema.Ref)
+	require.Empty(t, doc.Components.Responses)
+
+	require.PanicsWithValue(t, `openapi3: cannot replace a *openapi3.SchemaRef with a *openapi3.Schema`, func() {
+		_ = openapi3.Walk(doc, openapi3.VisitorFunc(func(c *openapi3.WalkCursor) error {
+			if x, ok := c.Value.(*openapi3.SchemaRef); ok {
+				c.Replace(x.Value)
+			}
+			return nil
+		}))
+	})
+}
+
+func TestWalkErrorAborts(t *testing.T) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData([]byte(dereferenceSpec))
+	require.NoError(t, err)
+
+	boom := errors.New("boom")
+	var visited []string
+	err = openapi3.Walk(doc, openapi3.VisitorFunc(func(c *openapi3.WalkCursor) error {
+		visited = append(visited, c.JSONPointer)
+		if _, ok := c.Value.(*openapi3.ParameterRef); ok {
+			return boom
+		}
+		return nil
+	}))
+	require.ErrorIs(t, err, boom)
+	require.Equal(t, "/components/parameters/Depth", visited[len(visited)-1])
+}
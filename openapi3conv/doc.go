@@ -17,7 +17,9 @@
 //
 // Scope:
 //   - In scope: 3.x → latest 3.x.
-//   - Out of scope: any → 3.0 (downgrade is lossy by nature).
+//   - Best effort: 3.1+ → 3.0 with Downgrade, for consumers that only read
+//     3.0. Downgrading is lossy by nature: constructs without a 3.0
+//     equivalent are dropped and reported through WithWriter.
 //   - Out of scope: cross-major upgrades (3 → 4 if/when v4 ships). Those
 //     belong in a dedicated package mirroring openapi2conv (which converts
 //     Swagger 2.0 documents to OpenAPI 3.0).
@@ -0,0 +1,437 @@
+package openapi3conv
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Downgrade rewrites doc in place into its closest OpenAPI 3.0 representation,
+// target being the 3.0.x version string written into doc.OpenAPI (e.g. "3.0.3").
+//
+// It is the best-effort inverse of Upgrade: type arrays including "null" become
+// nullable types, numeric exclusive bounds become boolean ones, examples become
+// example, const becomes a single value enum, and sequential media types
+// (itemSchema) become array schemas. The $defs of component schemas are hoisted
+// into components named "<schema>.<def>". Keywords without a 3.0 equivalent
+// (if/then/else, prefixItems, $dynamicRef, ...) and webhooks are removed.
+// Every rewrite is reported with the JSON pointer of its location to the
+// writer given by WithWriter.
+//
+// doc must be Validate()'d before calling Downgrade; passing an invalid
+// document is undefined behaviour.
+func Downgrade(doc *openapi3.T, target string, opts ...Option) error {
+	if target != "3.0" && !strings.HasPrefix(target, "3.0.") {
+		return fmt.Errorf("unsupported downgrade target %q: expected a 3.0.x version", target)
+	}
+	if doc == nil {
+		return nil
+	}
+
+	o := upgradeOptions{}
+	for _, apply := range opts {
+		apply(&o)
+	}
+	d := &downgrader{opts: o}
+
+	if len(doc.Webhooks) != 0 {
+		for _, name := range slices.Sorted(maps.Keys(doc.Webhooks)) {
+			d.logf("%s: dropped", pointerTo("/webhooks", name))
+		}
+		doc.Webhooks = nil
+	}
+	if doc.JSONSchemaDialect != "" {
+		d.logf("/jsonSchemaDialect: dropped")
+		doc.JSONSchemaDialect = ""
+	}
+	d.info(doc.Info)
+	if doc.Paths == nil {
+		doc.Paths = openapi3.NewPaths()
+	}
+	d.hoistDefs(doc)
+
+	_ = doc.WalkSchemas(func(ptr string, sr *openapi3.SchemaRef) error {
+		if to, ok := d.defRefs[sr.Ref]; ok {
+			sr.Ref = to
+		}
+		d.schema(ptr, sr.Value)
+		return nil
+	})
+	d.document(doc)
+
+	if doc.OpenAPI != target {
+		d.logf("openapi: %s -> %s", doc.OpenAPI, target)
+		doc.OpenAPI = target
+	}
+	return nil
+}
+
+// downgrader carries verbose output across a Downgrade pass.
+type downgrader struct {
+	opts upgradeOptions
+	// defRefs maps references to hoisted $defs to references to their new components.
+	defRefs map[string]string
+}
+
+func (d *downgrader) logf(format string, args ...any) {
+	d.opts.logf(format, args...)
+}
+
+// pointerTo appends escaped reference tokens to a JSON pointer.
+func pointerTo(ptr string, tokens ...string) string {
+	for _, token := range tokens {
+		ptr += "/" + openapi3.EscapeJSONPointerToken(token)
+	}
+	return ptr
+}
+
+func (d *downgrader) info(info *openapi3.Info) {
+	if info == nil {
+		return
+	}
+	if info.Summary != "" {
+		d.logf("/info/summary: dropped")
+		info.Summary = ""
+	}
+	if license := info.License; license != nil && license.Identifier != "" {
+		if license.URL == "" {
+			license.URL = "https://spdx.org/licenses/" + license.Identifier + ".html"
+			d.logf("/info/license/identifier: %s -> url: %s", license.Identifier, license.URL)
+		} else {
+			d.logf("/info/license/identifier: dropped")
+		}
+		license.Identifier = ""
+	}
+}
+
+// hoistDefs moves the $defs of component schemas into components,
+// as OpenAPI 3.0 schemas cannot hold definitions.
+func (d *downgrader) hoistDefs(doc *openapi3.T) {
+	if doc.Components == nil {
+		return
+	}
+	schemas := doc.Components.Schemas
+	for _, name := range slices.Sorted(maps.Keys(schemas)) {
+		schemaRef := schemas[name]
+		if schemaRef == nil || schemaRef.Ref != "" || schemaRef.Value == nil || len(schemaRef.Value.Defs) == 0 {
+			continue
+		}
+		for _, defName := range slices.Sorted(maps.Keys(schemaRef.Value.Defs)) {
+			hoisted := name + "." + defName
+			if _, ok := schemas[hoisted]; ok {
+				continue
+			}
+			schemas[hoisted] = schemaRef.Value.Defs[defName]
+			if d.defRefs == nil {
+				d.defRefs = make(map[string]string)
+			}
+			from := "#" + pointerTo("/components/schemas", name, "$defs", defName)
+			to := "#" + pointerTo("/components/schemas", hoisted)
+			d.defRefs[from] = to
+			d.logf("%s: hoisted into %s", from[1:], to[1:])
+			delete(schemaRef.Value.Defs, defName)
+		}
+	}
+}
+
+// schema rewrites a single schema, its sub-schemas being visited by WalkSchemas.
+func (d *downgrader) schema(ptr string, s *openapi3.Schema) {
+	d.rewriteRefs(s)
+	d.rewriteTypes(ptr, s)
+	d.rewriteExclusiveBounds(ptr, s)
+	d.rewriteExamples(ptr, s)
+	d.rewriteConst(ptr, s)
+	d.rewriteContent(ptr, s)
+	d.dropKeywords(ptr, s)
+}
+
+// rewriteRefs updates the references to hoisted $defs held by s.
+func (d *downgrader) rewriteRefs(s *openapi3.Schema) {
+	if len(d.defRefs) == 0 {
+		return
+	}
+	rewrite := func(sr *openapi3.SchemaRef) {
+		if sr == nil {
+			return
+		}
+		if to, ok := d.defRefs[sr.Ref]; ok {
+			sr.Ref = to
+		}
+	}
+	for _, sr := range s.Properties {
+		rewrite(sr)
+	}
+	rewrite(s.Items)
+	rewrite(s.AdditionalProperties.Schema)
+	rewrite(s.Not)
+	for _, refs := range []openapi3.SchemaRefs{s.AllOf, s.AnyOf, s.OneOf} {
+		for _, sr := range refs {
+			rewrite(sr)
+		}
+	}
+}
+
+// rewriteTypes converts type arrays into a single type with nullable,
+// or into anyOf when the schema allows several types.
+func (d *downgrader) rewriteTypes(ptr string, s *openapi3.Schema) {
+	if s.Type == nil || len(*s.Type) < 2 && !s.Type.Is(openapi3.TypeNull) {
+		return
+	}
+	before := s.Type.Slice()
+	types := slices.DeleteFunc(slices.Clone(before), func(t string) bool { return t == openapi3.TypeNull })
+	s.Nullable = len(types) != len(before)
+
+	switch len(types) {
+	case 0:
+		s.Type = nil
+		s.Enum = []any{nil}
+		d.logf("%s/type: %v -> nullable: true, enum: [null]", ptr, before)
+	case 1:
+		s.Type = &openapi3.Types{types[0]}
+		d.logf("%s/type: %v -> type: %s, nullable: %t", ptr, before, types[0], s.Nullable)
+	default:
+		s.Type = nil
+		anyOf := make(openapi3.SchemaRefs, 0, len(types))
+		for _, t := range types {
+			anyOf = append(anyOf, (&openapi3.Schema{Type: &openapi3.Types{t}}).NewRef())
+		}
+		if len(s.AnyOf) == 0 {
+			s.AnyOf = anyOf
+		} else {
+			s.AllOf = append(s.AllOf, (&openapi3.Schema{AnyOf: anyOf}).NewRef())
+		}
+		d.logf("%s/type: %v -> anyOf of single types, nullable: %t", ptr, before, s.Nullable)
+	}
+}
+
+// rewriteExclusiveBounds converts the 3.1 numeric form into the 3.0
+// boolean modifier, keeping the stricter of the bounds:
+//
+//	exclusiveMinimum: x  ->  minimum: x, exclusiveMinimum: true
+//
+// Mirror logic for maximum.
+func (d *downgrader) rewriteExclusiveBounds(ptr string, s *openapi3.Schema) {
+	if v := s.ExclusiveMin.Value; v != nil {
+		if s.Min == nil || *v >= *s.Min {
+			s.Min = v
+			s.ExclusiveMin = openapi3.ExclusiveBound{Bool: openapi3.Ptr(true)}
+			d.logf("%s/exclusiveMinimum: %v -> minimum: %v, exclusiveMinimum: true", ptr, *v, *v)
+		} else {
+			s.ExclusiveMin = openapi3.ExclusiveBound{}
+			d.logf("%s/exclusiveMinimum: %v -> dropped, implied by minimum: %v", ptr, *v, *s.Min)
+		}
+	}
+	if v := s.ExclusiveMax.Value; v != nil {
+		if s.Max == nil || *v <= *s.Max {
+			s.Max = v
+			s.ExclusiveMax = openapi3.ExclusiveBound{Bool: openapi3.Ptr(true)}
+			d.logf("%s/exclusiveMaximum: %v -> maximum: %v, exclusiveMaximum: true", ptr, *v, *v)
+		} else {
+			s.ExclusiveMax = openapi3.ExclusiveBound{}
+			d.logf("%s/exclusiveMaximum: %v -> dropped, implied by maximum: %v", ptr, *v, *s.Max)
+		}
+	}
+}
+
+// rewriteExamples keeps the first of the examples as the example of s.
+func (d *downgrader) rewriteExamples(ptr string, s *openapi3.Schema) {
+	if len(s.Examples) == 0 {
+		return
+	}
+	if s.Example == nil {
+		s.Example = s.Examples[0]
+		d.logf("%s/examples: %d examples -> example: first one", ptr, len(s.Examples))
+	} else {
+		d.logf("%s/examples: dropped in favor of example", ptr)
+	}
+	s.Examples = nil
+}
+
+// rewriteConst converts const into a single value enum.
+func (d *downgrader) rewriteConst(ptr string, s *openapi3.Schema) {
+	if s.Const == nil {
+		return
+	}
+	s.Enum = []any{s.Const}
+	s.Const = nil
+	d.logf("%s/const: %v -> enum: %v", ptr, s.Enum[0], s.Enum)
+}
+
+// rewriteContent converts contentEncoding and contentMediaType of strings into their 3.0 formats.
+func (d *downgrader) rewriteContent(ptr string, s *openapi3.Schema) {
+	if s.ContentEncoding == "" && s.ContentMediaType == "" {
+		return
+	}
+	if s.Type.Is(openapi3.TypeString) && s.Format == "" {
+		switch {
+		case s.ContentEncoding == "base64":
+			s.Format = "byte"
+			d.logf("%s/contentEncoding: base64 -> format: byte", ptr)
+		case s.ContentEncoding == "" && s.ContentMediaType == "application/octet-stream":
+			s.Format = "binary"
+			d.logf("%s/contentMediaType: application/octet-stream -> format: binary", ptr)
+		}
+	}
+	if s.ContentEncoding != "" && s.Format != "byte" {
+		d.logf("%s/contentEncoding: dropped", ptr)
+	}
+	if s.ContentMediaType != "" && s.Format != "binary" {
+		d.logf("%s/contentMediaType: dropped", ptr)
+	}
+	s.ContentEncoding, s.ContentMediaType = "", ""
+}
+
+// dropKeywords removes the keywords of s without a 3.0 equivalent.
+func (d *downgrader) dropKeywords(ptr string, s *openapi3.Schema) {
+	drop := func(keyword string, present bool) {
+		if present {
+			d.logf("%s/%s: dropped", ptr, keyword)
+		}
+	}
+	drop("if", s.If != nil)
+	drop("then", s.Then != nil)
+	drop("else", s.Else != nil)
+	drop("prefixItems", len(s.PrefixItems) != 0)
+	if len(s.PrefixItems) != 0 && s.Items == nil {
+		// OpenAPI 3.0 arrays must describe their items
+		s.Items = openapi3.NewSchema().NewRef()
+	}
+	drop("contains", s.Contains != nil)
+	drop("minContains", s.MinContains != nil)
+	drop("maxContains", s.MaxContains != nil)
+	drop("patternProperties", len(s.PatternProperties) != 0)
+	drop("dependentSchemas", len(s.DependentSchemas) != 0)
+	drop("dependentRequired", len(s.DependentRequired) != 0)
+	drop("propertyNames", s.PropertyNames != nil)
+	drop("unevaluatedItems", s.UnevaluatedItems.Has != nil || s.UnevaluatedItems.Schema != nil)
+	drop("unevaluatedProperties", s.UnevaluatedProperties.Has != nil || s.UnevaluatedProperties.Schema != nil)
+	drop("$defs", len(s.Defs) != 0)
+	drop("$schema", s.SchemaDialect != "")
+	drop("$comment", s.Comment != "")
+	drop("$id", s.SchemaID != "")
+	drop("$anchor", s.Anchor != "")
+	drop("$dynamicRef", s.DynamicRef != "")
+	drop("$dynamicAnchor", s.DynamicAnchor != "")
+	drop("contentSchema", s.ContentSchema != nil)
+
+	s.If, s.Then, s.Else = nil, nil, nil
+	s.PrefixItems = nil
+	s.Contains, s.MinContains, s.MaxContains = nil, nil, nil
+	s.PatternProperties, s.DependentSchemas, s.DependentRequired = nil, nil, nil
+	s.PropertyNames = nil
+	s.UnevaluatedItems, s.UnevaluatedProperties = openapi3.BoolSchema{}, openapi3.BoolSchema{}
+	s.Defs = nil
+	s.SchemaDialect, s.Comment, s.SchemaID, s.Anchor, s.DynamicRef, s.DynamicAnchor = "", "", "", "", "", ""
+	s.ContentSchema = nil
+}
+
+// document rewrites the media types of doc.
+func (d *downgrader) document(doc *openapi3.T) {
+	if c := doc.Components; c != nil {
+		for _, name := range slices.Sorted(maps.Keys(c.Parameters)) {
+			if pr := c.Parameters[name]; pr != nil && pr.Value != nil {
+				d.content(pointerTo("/components/parameters", name, "content"), pr.Value.Content)
+			}
+		}
+		for _, name := range slices.Sorted(maps.Keys(c.Headers)) {
+			if hr := c.Headers[name]; hr != nil && hr.Value != nil {
+				d.content(pointerTo("/components/headers", name, "content"), hr.Value.Content)
+			}
+		}
+		for _, name := range slices.Sorted(maps.Keys(c.RequestBodies)) {
+			if rbr := c.RequestBodies[name]; rbr != nil && rbr.Value != nil {
+				d.content(pointerTo("/components/requestBodies", name, "content"), rbr.Value.Content)
+			}
+		}
+		for _, name := range slices.Sorted(maps.Keys(c.Responses)) {
+			d.response(pointerTo("/components/responses", name), c.Responses[name])
+		}
+		for _, name := range slices.Sorted(maps.Keys(c.Callbacks)) {
+			if cbr := c.Callbacks[name]; cbr != nil && cbr.Value != nil {
+				d.callback(pointerTo("/components/callbacks", name), cbr.Value)
+			}
+		}
+	}
+	items := doc.Paths.Map()
+	for _, path := range slices.Sorted(maps.Keys(items)) {
+		d.pathItem(pointerTo("/paths", path), items[path])
+	}
+}
+
+func (d *downgrader) pathItem(ptr string, item *openapi3.PathItem) {
+	if item == nil {
+		return
+	}
+	for i, pr := range item.Parameters {
+		if pr != nil && pr.Value != nil {
+			d.content(pointerTo(ptr, "parameters", strconv.Itoa(i), "content"), pr.Value.Content)
+		}
+	}
+	ops := item.Operations()
+	for _, method := range slices.Sorted(maps.Keys(ops)) {
+		op := ops[method]
+		opPtr := pointerTo(ptr, strings.ToLower(method))
+		for i, pr := range op.Parameters {
+			if pr != nil && pr.Value != nil {
+				d.content(pointerTo(opPtr, "parameters", strconv.Itoa(i), "content"), pr.Value.Content)
+			}
+		}
+		if op.RequestBody != nil && op.RequestBody.Value != nil {
+			d.content(pointerTo(opPtr, "requestBody", "content"), op.RequestBody.Value.Content)
+		}
+		if op.Responses != nil {
+			responses := op.Responses.Map()
+			for _, code := range slices.Sorted(maps.Keys(responses)) {
+				d.response(pointerTo(opPtr, "responses", code), responses[code])
+			}
+		}
+		for _, name := range slices.Sorted(maps.Keys(op.Callbacks)) {
+			if cbr := op.Callbacks[name]; cbr != nil && cbr.Value != nil {
+				d.callback(pointerTo(opPtr, "callbacks", name), cbr.Value)
+			}
+		}
+	}
+}
+
+func (d *downgrader) callback(ptr string, cb *openapi3.Callback) {
+	items := cb.Map()
+	for _, expr := range slices.Sorted(maps.Keys(items)) {
+		d.pathItem(pointerTo(ptr, expr), items[expr])
+	}
+}
+
+func (d *downgrader) response(ptr string, rr *openapi3.ResponseRef) {
+	if rr == nil || rr.Value == nil {
+		return
+	}
+	for _, name := range slices.Sorted(maps.Keys(rr.Value.Headers)) {
+		if hr := rr.Value.Headers[name]; hr != nil && hr.Value != nil {
+			d.content(pointerTo(ptr, "headers", name, "content"), hr.Value.Content)
+		}
+	}
+	d.content(pointerTo(ptr, "content"), rr.Value.Content)
+}
+
+// content converts sequential media types (itemSchema) into array schemas.
+func (d *downgrader) content(ptr string, content openapi3.Content) {
+	for _, name := range slices.Sorted(maps.Keys(content)) {
+		mediaType := content[name]
+		if mediaType == nil || mediaType.ItemSchema == nil {
+			continue
+		}
+		itemPtr := pointerTo(ptr, name, "itemSchema")
+		if mediaType.Schema == nil {
+			schema := openapi3.NewArraySchema()
+			schema.Items = mediaType.ItemSchema
+			mediaType.Schema = schema.NewRef()
+			d.logf("%s -> schema: {type: array, items: <itemSchema>}", itemPtr)
+		} else {
+			d.logf("%s: dropped in favor of schema", itemPtr)
+		}
+		mediaType.ItemSchema = nil
+	}
+}
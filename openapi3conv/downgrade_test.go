@@ -0,0 +1,158 @@
+package openapi3conv_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3conv"
+)
+
+// downgradeAndAssertValid runs Downgrade with a Validate invariant on both
+// sides: the 3.1+ input must validate, and so must the 3.0 output.
+func downgradeAndAssertValid(t *testing.T, doc *openapi3.T, opts ...openapi3conv.Option) {
+	t.Helper()
+	require.NoError(t, doc.Validate(context.Background()), "document must validate before Downgrade")
+	require.NoError(t, openapi3conv.Downgrade(doc, "3.0.3", opts...))
+	require.NoError(t, doc.Validate(context.Background()), "document must validate after Downgrade")
+}
+
+func TestDowngrade_SchemaRewrites(t *testing.T) {
+	doc := loadV30(t, `
+openapi: 3.1.0
+info: {title: t, version: '1'}
+paths: {}
+components:
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name:
+          type: [string, "null"]
+          examples: [fido, rex]
+        age:
+          type: integer
+          exclusiveMinimum: 0
+          maximum: 30
+          exclusiveMaximum: 40
+        kind:
+          const: dog
+        id:
+          type: [string, integer]
+        tags:
+          type: array
+          prefixItems:
+          - type: string
+        photo:
+          type: string
+          contentEncoding: base64
+`)
+	downgradeAndAssertValid(t, doc)
+	require.Equal(t, "3.0.3", doc.OpenAPI)
+
+	pet := doc.Components.Schemas["Pet"].Value
+	name := pet.Properties["name"].Value
+	assert.Equal(t, openapi3.Types{"string"}, *name.Type)
+	assert.True(t, name.Nullable)
+	assert.Equal(t, "fido", name.Example)
+	assert.Nil(t, name.Examples)
+
+	age := pet.Properties["age"].Value
+	assert.Equal(t, 0.0, *age.Min)
+	assert.Equal(t, openapi3.ExclusiveBound{Bool: openapi3.Ptr(true)}, age.ExclusiveMin)
+	// maximum is stricter than the exclusive bound
+	assert.Equal(t, 30.0, *age.Max)
+	assert.False(t, age.ExclusiveMax.IsSet())
+
+	kind := pet.Properties["kind"].Value
+	assert.Equal(t, []any{"dog"}, kind.Enum)
+	assert.Nil(t, kind.Const)
+
+	id := pet.Properties["id"].Value
+	assert.Nil(t, id.Type)
+	require.Len(t, id.AnyOf, 2)
+	assert.Equal(t, openapi3.Types{"string"}, *id.AnyOf[0].Value.Type)
+	assert.Equal(t, openapi3.Types{"integer"}, *id.AnyOf[1].Value.Type)
+
+	assert.Nil(t, pet.Properties["tags"].Value.PrefixItems)
+	assert.Equal(t, "byte", pet.Properties["photo"].Value.Format)
+}
+
+func TestDowngrade_DocumentRewrites(t *testing.T) {
+	doc := loadV30(t, `
+openapi: 3.2.0
+info:
+  title: t
+  version: '1'
+  summary: A summary
+  license:
+    name: MIT
+    identifier: MIT
+paths:
+  /events:
+    get:
+      responses:
+        '200':
+          description: Events
+          content:
+            application/jsonl:
+              itemSchema:
+                $ref: '#/components/schemas/Event'
+webhooks:
+  newEvent:
+    post:
+      responses:
+        '200':
+          description: ok
+components:
+  schemas:
+    Event:
+      type: object
+      properties:
+        level:
+          $ref: '#/components/schemas/Event/$defs/Level'
+      $defs:
+        Level:
+          type: string
+          enum: [info, error]
+`)
+	var buf bytes.Buffer
+	downgradeAndAssertValid(t, doc, openapi3conv.WithWriter(&buf))
+
+	assert.Nil(t, doc.Webhooks)
+	assert.Empty(t, doc.Info.Summary)
+	assert.Equal(t, "https://spdx.org/licenses/MIT.html", doc.Info.License.URL)
+
+	mediaType := doc.Paths.Find("/events").Get.Responses.Status(200).Value.Content["application/jsonl"]
+	assert.Nil(t, mediaType.ItemSchema)
+	assert.Equal(t, openapi3.Types{"array"}, *mediaType.Schema.Value.Type)
+	assert.Equal(t, "#/components/schemas/Event", mediaType.Schema.Value.Items.Ref)
+
+	event := doc.Components.Schemas["Event"].Value
+	assert.Empty(t, event.Defs)
+	assert.Equal(t, "#/components/schemas/Event.Level", event.Properties["level"].Ref)
+	assert.Equal(t, []any{"info", "error"}, doc.Components.Schemas["Event.Level"].Value.Enum)
+
+	assert.Equal(t, `/webhooks/newEvent: dropped
+/info/summary: dropped
+/info/license/identifier: MIT -> url: https://spdx.org/licenses/MIT.html
+/components/schemas/Event/$defs/Level: hoisted into /components/schemas/Event.Level
+/paths/~1events/get/responses/200/content/application~1jsonl/itemSchema -> schema: {type: array, items: <itemSchema>}
+openapi: 3.2.0 -> 3.0.3
+`, buf.String())
+}
+
+func TestDowngrade_UnsupportedTarget(t *testing.T) {
+	doc := loadV30(t, `
+openapi: 3.1.0
+info: {title: t, version: '1'}
+paths: {}
+`)
+	err := openapi3conv.Downgrade(doc, "3.1.0")
+	require.EqualError(t, err, `unsupported downgrade target "3.1.0": expected a 3.0.x version`)
+	require.Equal(t, "3.1.0", doc.OpenAPI)
+}
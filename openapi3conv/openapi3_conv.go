@@ -15,7 +15,7 @@ import (
 // handles 3.1 correctly handles later 3.x versions correctly too.
 const latestTargetVersion = "3.2.0"
 
-// Option configures an Upgrade pass. See WithWriter.
+// Option configures an Upgrade or Downgrade pass. See WithWriter.
 type Option func(*upgradeOptions)
 
 // upgradeOptions is the internal carrier for Option functions. Kept private
@@ -83,11 +83,15 @@ type walker struct {
 }
 
 func (w *walker) logf(format string, args ...any) {
-	if w.opts.verbose == nil {
+	w.opts.logf(format, args...)
+}
+
+func (o upgradeOptions) logf(format string, args ...any) {
+	if o.verbose == nil {
 		return
 	}
-	fmt.Fprintf(w.opts.verbose, format, args...)
-	fmt.Fprintln(w.opts.verbose)
+	fmt.Fprintf(o.verbose, format, args...)
+	fmt.Fprintln(o.verbose)
 }
 
 // walkDoc visits every Schema reachable from the document root.
@@ -0,0 +1,204 @@
+package openapi3filter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// ErrBindTarget is returned by BindRequest when its destination is not a non-nil pointer to a struct.
+var ErrBindTarget = errors.New("binding destination must be a non-nil pointer to a struct")
+
+// decodedRequest contains the parameter and body values decoded while validating a request.
+type decodedRequest struct {
+	// validated is true once ValidateRequest completed, with err its result
+	validated  bool
+	err        error
+	parameters map[parameterKey]any
+	body       any
+	hasBody    bool
+}
+
+// parameterKey identifies a parameter by its location and name.
+type parameterKey struct {
+	in, name string
+}
+
+func newParameterKey(in, name string) parameterKey {
+	if in == openapi3.ParameterInHeader {
+		name = http.CanonicalHeaderKey(name)
+	}
+	return parameterKey{in: in, name: name}
+}
+
+func (input *RequestValidationInput) decodedRequest() *decodedRequest {
+	if input.decoded == nil {
+		input.decoded = &decodedRequest{parameters: make(map[parameterKey]any)}
+	}
+	return input.decoded
+}
+
+func (input *RequestValidationInput) resetDecodedRequest() *decodedRequest {
+	input.decoded = nil
+	return input.decodedRequest()
+}
+
+func (d *decodedRequest) setParameter(parameter *openapi3.Parameter, value any) {
+	d.parameters[newParameterKey(parameter.In, parameter.Name)] = value
+}
+
+func (d *decodedRequest) setBody(value any) {
+	d.body, d.hasBody = value, true
+}
+
+// BindRequest fills the struct pointed to by dst with the parameter and body values
+// decoded by ValidateRequest, with defaults applied. The request is validated first
+// if input was not already validated. Validation errors are returned, and nothing is bound,
+// whether they come from that validation or from a previous call to ValidateRequest.
+//
+// Struct fields tagged `path:"id"`, `query:"limit"`, `header:"X-Req"` or `cookie:"session"`
+// receive the value of the parameter with that location and name, decoded according to
+// its style and explode rules. Parameters missing from the request leave their field untouched.
+// A field tagged `body:""` receives the request body, otherwise the body is bound to
+// the fields of dst not bound to parameters as encoding/json would unmarshal it,
+// so that the body cannot override the value of a parameter.
+//
+// Values are converted to field types as encoding/json would, through their JSON representation.
+func BindRequest(ctx context.Context, input *RequestValidationInput, dst any) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return ErrBindTarget
+	}
+
+	if input.decoded == nil || !input.decoded.validated {
+		if err := ValidateRequest(ctx, input); err != nil {
+			return err
+		}
+	}
+	decoded := input.decodedRequest()
+	if decoded.err != nil {
+		return decoded.err
+	}
+
+	declared := make(map[parameterKey]struct{})
+	for _, parameterRef := range input.Route.PathItem.Parameters {
+		declared[newParameterKey(parameterRef.Value.In, parameterRef.Value.Name)] = struct{}{}
+	}
+	for _, parameterRef := range input.Route.Operation.Parameters {
+		declared[newParameterKey(parameterRef.Value.In, parameterRef.Value.Name)] = struct{}{}
+	}
+
+	v := rv.Elem()
+	bodyBound := false
+	for i := range v.NumField() {
+		field := v.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		if _, ok := field.Tag.Lookup("body"); ok {
+			if decoded.hasBody {
+				if err := bindValue(v.Field(i), decoded.body); err != nil {
+					return fmt.Errorf("binding request body to field %s: %w", field.Name, err)
+				}
+			}
+			bodyBound = true
+			continue
+		}
+
+		in, name := bindingOf(field)
+		if in == "" {
+			continue
+		}
+		key := newParameterKey(in, name)
+		if _, ok := declared[key]; !ok {
+			return fmt.Errorf("field %s is bound to %s parameter %q which is not declared by the operation", field.Name, in, name)
+		}
+		value, ok := decoded.parameters[key]
+		if !ok {
+			continue
+		}
+		if err := bindValue(v.Field(i), value); err != nil {
+			return fmt.Errorf("binding %s parameter %q to field %s: %w", in, name, field.Name, err)
+		}
+	}
+
+	if !bodyBound && decoded.hasBody && decoded.body != nil {
+		if _, ok := decoded.body.(map[string]any); !ok {
+			return fmt.Errorf("request body of type %T can only be bound to a field tagged body", decoded.body)
+		}
+		if err := bindBody(v, decoded.body); err != nil {
+			return fmt.Errorf("binding request body: %w", err)
+		}
+	}
+	return nil
+}
+
+// bindBody unmarshals an object body into the fields of struct v not bound to parameters.
+// The body is unmarshalled into a copy of v whose parameter fields are cleared,
+// so that values they point to are not modified either.
+func bindBody(v reflect.Value, body any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	bound := reflect.New(v.Type()).Elem()
+	bound.Set(v)
+	for i := range v.NumField() {
+		if in, _ := bindingOf(v.Type().Field(i)); in != "" {
+			bound.Field(i).SetZero()
+		}
+	}
+	if err := json.Unmarshal(data, bound.Addr().Interface()); err != nil {
+		return err
+	}
+	for i := range v.NumField() {
+		field := v.Type().Field(i)
+		if in, _ := bindingOf(field); in == "" && field.IsExported() {
+			v.Field(i).Set(bound.Field(i))
+		}
+	}
+	return nil
+}
+
+// bindingOf returns the parameter location and name a struct field is bound to, if any.
+func bindingOf(field reflect.StructField) (string, string) {
+	for _, in := range []string{
+		openapi3.ParameterInPath,
+		openapi3.ParameterInQuery,
+		openapi3.ParameterInHeader,
+		openapi3.ParameterInCookie,
+	} {
+		if tag, ok := field.Tag.Lookup(in); ok {
+			name, _, _ := strings.Cut(tag, ",")
+			if name == "" {
+				name = field.Name
+			}
+			return in, name
+		}
+	}
+	return "", ""
+}
+
+// bindValue sets the value of a struct field from a decoded value.
+func bindValue(field reflect.Value, value any) error {
+	if value == nil {
+		return nil
+	}
+	// Binary values are decoded as strings, which encoding/json would expect base64-encoded
+	if s, ok := value.(string); ok && field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.Uint8 {
+		field.SetBytes([]byte(s))
+		return nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, field.Addr().Interface())
+}
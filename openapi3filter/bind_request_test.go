@@ -0,0 +1,207 @@
+package openapi3filter_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+func TestBindRequest(t *testing.T) {
+	const spec = `
+openapi: 3.0.0
+info:
+  title: Pets
+  version: 0.0.1
+paths:
+  /pets/{id}:
+    parameters:
+    - name: id
+      in: path
+      required: true
+      schema:
+        type: integer
+    put:
+      parameters:
+      - name: tags
+        in: query
+        explode: false
+        schema:
+          type: array
+          items:
+            type: string
+      - name: limit
+        in: query
+        schema:
+          type: integer
+          default: 10
+      - name: X-Request-Id
+        in: header
+        schema:
+          type: string
+      - name: session
+        in: cookie
+        schema:
+          type: string
+      - name: filter
+        in: query
+        style: deepObject
+        schema:
+          type: object
+          properties:
+            color:
+              type: string
+            min:
+              type: integer
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                name:
+                  type: string
+                kind:
+                  type: string
+                  default: dog
+      responses:
+        '200':
+          description: OK
+  /pets:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: array
+              items:
+                type: string
+      responses:
+        '200':
+          description: OK
+`
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData([]byte(spec))
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(loader.Context))
+	router, err := gorillamux.NewRouter(doc)
+	require.NoError(t, err)
+
+	newInput := func(method, target, body string) *openapi3filter.RequestValidationInput {
+		req, err := http.NewRequest(method, target, strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Request-Id", "abc")
+		req.AddCookie(&http.Cookie{Name: "session", Value: "s3cr3t"})
+		route, pathParams, err := router.FindRoute(req)
+		require.NoError(t, err)
+		return &openapi3filter.RequestValidationInput{
+			Request:    req,
+			PathParams: pathParams,
+			Route:      route,
+		}
+	}
+
+	type Filter struct {
+		Color string `json:"color"`
+		Min   int    `json:"min"`
+	}
+	type UpdatePet struct {
+		ID        int64    `path:"id"`
+		Tags      []string `query:"tags"`
+		Limit     int      `query:"limit"`
+		RequestID string   `header:"x-request-id"`
+		Session   *string  `cookie:"session"`
+		Filter    Filter   `query:"filter"`
+		Name      string   `json:"name"`
+		Kind      string   `json:"kind"`
+	}
+
+	ctx := context.Background()
+	input := newInput(http.MethodPut, "/pets/42?tags=a,b&filter[color]=red&filter[min]=3", `{"name":"Rex"}`)
+	require.NoError(t, openapi3filter.ValidateRequest(ctx, input))
+
+	var dst UpdatePet
+	require.NoError(t, openapi3filter.BindRequest(ctx, input, &dst))
+	session := "s3cr3t"
+	require.Equal(t, UpdatePet{
+		ID:        42,
+		Tags:      []string{"a", "b"},
+		Limit:     10,
+		RequestID: "abc",
+		Session:   &session,
+		Filter:    Filter{Color: "red", Min: 3},
+		Name:      "Rex",
+		Kind:      "dog",
+	}, dst)
+
+	// The body does not override parameters, even pointed to ones
+	type Overriding struct {
+		ID      int64   `path:"id"`
+		Limit   *int    `query:"limit"`
+		Session *string `cookie:"session"`
+		Name    string  `json:"name"`
+	}
+	limit := 3
+	overriding := Overriding{Limit: &limit}
+	input = newInput(http.MethodPut, "/pets/42?limit=3", `{"id":7,"ID":8,"limit":9,"session":"forged","name":"Rex"}`)
+	require.NoError(t, openapi3filter.BindRequest(ctx, input, &overriding))
+	require.Equal(t, int64(42), overriding.ID)
+	require.Equal(t, 3, *overriding.Limit)
+	require.Equal(t, 3, limit)
+	require.Equal(t, "s3cr3t", *overriding.Session)
+	require.Equal(t, "Rex", overriding.Name)
+
+	// Requests are validated when they were not already
+	input = newInput(http.MethodPut, "/pets/42?limit=oops", `{"name":"Rex"}`)
+	err = openapi3filter.BindRequest(ctx, input, &dst)
+	require.ErrorContains(t, err, `parameter "limit" in query has an error`)
+
+	// Requests that failed validation are not bound
+	input = newInput(http.MethodPut, "/pets/42?limit=oops", `{"name":"Rex"}`)
+	require.Error(t, openapi3filter.ValidateRequest(ctx, input))
+	dst = UpdatePet{}
+	err = openapi3filter.BindRequest(ctx, input, &dst)
+	require.ErrorContains(t, err, `parameter "limit" in query has an error`)
+	require.Equal(t, UpdatePet{}, dst)
+
+	// Validating again discards the values decoded by a previous validation
+	input = newInput(http.MethodPut, "/pets/42?filter[color]=red", `{"name":"Rex"}`)
+	require.NoError(t, openapi3filter.ValidateRequest(ctx, input))
+	input.Request.URL.RawQuery, input.QueryParams = "", nil
+	require.NoError(t, openapi3filter.ValidateRequest(ctx, input))
+	dst = UpdatePet{}
+	require.NoError(t, openapi3filter.BindRequest(ctx, input, &dst))
+	require.Equal(t, Filter{}, dst.Filter)
+
+	// Non-object bodies are bound to a dedicated field
+	type CreatePets struct {
+		Names []string `body:""`
+	}
+	input = newInput(http.MethodPost, "/pets", `["a","b"]`)
+	var created CreatePets
+	require.NoError(t, openapi3filter.BindRequest(ctx, input, &created))
+	require.Equal(t, []string{"a", "b"}, created.Names)
+
+	input = newInput(http.MethodPost, "/pets", `["a","b"]`)
+	err = openapi3filter.BindRequest(ctx, input, &struct{}{})
+	require.EqualError(t, err, "request body of type []interface {} can only be bound to a field tagged body")
+
+	// Fields must be bound to declared parameters
+	type Unknown struct {
+		Page int `query:"page"`
+	}
+	input = newInput(http.MethodPut, "/pets/42", `{}`)
+	err = openapi3filter.BindRequest(ctx, input, &Unknown{})
+	require.EqualError(t, err, `field Page is bound to query parameter "page" which is not declared by the operation`)
+
+	err = openapi3filter.BindRequest(ctx, input, dst)
+	require.ErrorIs(t, err, openapi3filter.ErrBindTarget)
+}
@@ -0,0 +1,437 @@
+package openapi3filter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// CoverageRecorder counts what requests and responses exercise of the
+// operations of an OpenAPI document: the parameters requests set, the statuses
+// and media types of responses, and the oneOf branches their bodies match.
+// Give it to a Validator with RecordCoverage, or call RecordRequest and
+// RecordResponse. It is safe for concurrent use.
+type CoverageRecorder struct {
+	mu     sync.Mutex
+	report *CoverageReport
+}
+
+// NewCoverageRecorder returns a CoverageRecorder of the operations of doc,
+// none of them exercised yet.
+func NewCoverageRecorder(doc *openapi3.T) *CoverageRecorder {
+	report := &CoverageReport{}
+	if doc.Paths != nil {
+		paths := doc.Paths.Map()
+		for _, path := range slices.Sorted(maps.Keys(paths)) {
+			pathItem := paths[path]
+			operations := pathItem.Operations()
+			for _, method := range slices.Sorted(maps.Keys(operations)) {
+				report.Operations = append(report.Operations, newOperationCoverage(method, path, pathItem, operations[method]))
+			}
+		}
+	}
+	return &CoverageRecorder{report: report}
+}
+
+func newOperationCoverage(method, path string, pathItem *openapi3.PathItem, operation *openapi3.Operation) *OperationCoverage {
+	oc := &OperationCoverage{Method: method, Path: path, OperationID: operation.OperationID}
+	for _, parameter := range operationParameters(pathItem, operation) {
+		oc.Parameters = append(oc.Parameters, &ParameterCoverage{Name: parameter.Name, In: parameter.In})
+	}
+	if requestBody := operation.RequestBody; requestBody != nil && requestBody.Value != nil {
+		content := requestBody.Value.Content
+		for _, contentType := range slices.Sorted(maps.Keys(content)) {
+			oc.addOneOfs(requestLocation(contentType), content[contentType].Schema)
+		}
+	}
+	if operation.Responses != nil {
+		responses := operation.Responses.Map()
+		for _, status := range slices.Sorted(maps.Keys(responses)) {
+			response := responses[status].Value
+			if response == nil || len(response.Content) == 0 {
+				oc.Responses = append(oc.Responses, &ResponseCoverage{Status: status})
+				continue
+			}
+			for _, contentType := range slices.Sorted(maps.Keys(response.Content)) {
+				oc.Responses = append(oc.Responses, &ResponseCoverage{Status: status, ContentType: contentType})
+				oc.addOneOfs(responseLocation(status, contentType), response.Content[contentType].Schema)
+			}
+		}
+	}
+	return oc
+}
+
+// operationParameters returns the parameters of operation, with those of
+// pathItem it does not override.
+func operationParameters(pathItem *openapi3.PathItem, operation *openapi3.Operation) []*openapi3.Parameter {
+	var parameters []*openapi3.Parameter
+	for _, ref := range operation.Parameters {
+		if ref != nil && ref.Value != nil {
+			parameters = append(parameters, ref.Value)
+		}
+	}
+	for _, ref := range pathItem.Parameters {
+		if ref == nil || ref.Value == nil {
+			continue
+		}
+		if operation.Parameters.GetByInAndName(ref.Value.In, ref.Value.Name) == nil {
+			parameters = append(parameters, ref.Value)
+		}
+	}
+	return parameters
+}
+
+func requestLocation(contentType string) string {
+	return "request " + contentType + "#"
+}
+
+func responseLocation(status, contentType string) string {
+	return "response " + status + " " + contentType + "#"
+}
+
+// addOneOfs adds the oneOf schemas of schema, and of the schemas it is made of,
+// to the coverage of oc.
+func (oc *OperationCoverage) addOneOfs(location string, schema *openapi3.SchemaRef) {
+	newSchemaWalker(func(location string, s *openapi3.Schema, _ any) {
+		if len(s.OneOf) == 0 {
+			return
+		}
+		oneOf := &OneOfCoverage{Location: location}
+		for i, branch := range s.OneOf {
+			ref := ""
+			if branch != nil {
+				ref = branch.Ref
+			}
+			oneOf.Branches = append(oneOf.Branches, &BranchCoverage{Index: i, Ref: ref})
+		}
+		oc.OneOfs = append(oc.OneOfs, oneOf)
+	}).walk(schema, location)
+}
+
+// schemaWalker calls f with schemas and the schemas they are made of, each
+// with its location: the JSON Pointer of the schema appended to the location of
+// the walk. Walking a value only walks the schemas that apply to the value or
+// parts of it, which are given to f, such as the oneOf branches the value
+// matches. Recursive schemas are walked once.
+type schemaWalker struct {
+	f        func(location string, s *openapi3.Schema, value any)
+	opts     []openapi3.SchemaValidationOption
+	visiting map[*openapi3.Schema]bool
+}
+
+func newSchemaWalker(f func(location string, s *openapi3.Schema, value any), opts ...openapi3.SchemaValidationOption) *schemaWalker {
+	return &schemaWalker{f: f, opts: opts, visiting: make(map[*openapi3.Schema]bool)}
+}
+
+// walk walks every schema of schema.
+func (w *schemaWalker) walk(schema *openapi3.SchemaRef, location string) {
+	if schema == nil || schema.Value == nil || w.visiting[schema.Value] {
+		return
+	}
+	s := schema.Value
+	w.visiting[s] = true
+	defer delete(w.visiting, s)
+
+	w.f(location, s, nil)
+	for i, branch := range s.OneOf {
+		w.walk(branch, location+"/oneOf/"+strconv.Itoa(i))
+	}
+	for i, branch := range s.AnyOf {
+		w.walk(branch, location+"/anyOf/"+strconv.Itoa(i))
+	}
+	for i, branch := range s.AllOf {
+		w.walk(branch, location+"/allOf/"+strconv.Itoa(i))
+	}
+	for _, name := range slices.Sorted(maps.Keys(s.Properties)) {
+		w.walk(s.Properties[name], location+"/properties/"+openapi3.EscapeJSONPointerToken(name))
+	}
+	w.walk(s.AdditionalProperties.Schema, location+"/additionalProperties")
+	w.walk(s.Items, location+"/items")
+}
+
+// walkValue walks the schemas of schema that apply to value.
+func (w *schemaWalker) walkValue(schema *openapi3.SchemaRef, location string, value any) {
+	if schema == nil || schema.Value == nil || w.visiting[schema.Value] {
+		return
+	}
+	s := schema.Value
+	w.visiting[s] = true
+	defer delete(w.visiting, s)
+
+	w.f(location, s, value)
+	for i, branch := range s.OneOf {
+		if w.matches(branch, value) {
+			w.walkValue(branch, location+"/oneOf/"+strconv.Itoa(i), value)
+		}
+	}
+	for i, branch := range s.AnyOf {
+		if w.matches(branch, value) {
+			w.walkValue(branch, location+"/anyOf/"+strconv.Itoa(i), value)
+		}
+	}
+	for i, branch := range s.AllOf {
+		w.walkValue(branch, location+"/allOf/"+strconv.Itoa(i), value)
+	}
+	switch value := value.(type) {
+	case map[string]any:
+		for _, name := range slices.Sorted(maps.Keys(value)) {
+			if property, ok := s.Properties[name]; ok {
+				w.walkValue(property, location+"/properties/"+openapi3.EscapeJSONPointerToken(name), value[name])
+			} else {
+				w.walkValue(s.AdditionalProperties.Schema, location+"/additionalProperties", value[name])
+			}
+		}
+	case []any:
+		for _, item := range value {
+			w.walkValue(s.Items, location+"/items", item)
+		}
+	}
+}
+
+func (w *schemaWalker) matches(schema *openapi3.SchemaRef, value any) bool {
+	return schema != nil && schema.Value != nil && schema.Value.VisitJSON(value, w.opts...) == nil
+}
+
+// RecordRequest accounts for a request routed to an operation: the operation
+// was called, with the parameters the request sets and a body matching some
+// oneOf branches. The body is read with input.Request.GetBody, which
+// ValidateRequest sets, so that the request can still be served.
+func (c *CoverageRecorder) RecordRequest(ctx context.Context, input *RequestValidationInput) {
+	route := input.Route
+	if route == nil || route.Operation == nil {
+		return
+	}
+	req := input.Request
+
+	// Parameters and bodies are matched before locking, only counting is not
+	pathItem := route.PathItem
+	if pathItem == nil {
+		pathItem = &openapi3.PathItem{}
+	}
+	set := make(map[[2]string]bool)
+	for _, parameter := range operationParameters(pathItem, route.Operation) {
+		if parameterSet(input, parameter) {
+			set[[2]string{parameter.In, parameter.Name}] = true
+		}
+	}
+	var matched map[string][]int
+	if requestBody := route.Operation.RequestBody; requestBody != nil && requestBody.Value != nil && req.GetBody != nil {
+		if contentType, value := decodeCoverageBody(req.GetBody, req.Header, requestBody.Value.Content); contentType != "" {
+			matched = matchOneOfs(requestLocation(contentType), requestBody.Value.Content[contentType].Schema, value, openapi3.VisitAsRequest())
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	oc := c.report.operation(route.Method, route.Path)
+	if oc == nil {
+		return
+	}
+	oc.Calls++
+	for _, pc := range oc.Parameters {
+		if set[[2]string{pc.In, pc.Name}] {
+			pc.Calls++
+		}
+	}
+	oc.recordOneOfs(matched)
+}
+
+// parameterSet tells whether the request of input sets parameter.
+func parameterSet(input *RequestValidationInput, parameter *openapi3.Parameter) bool {
+	req := input.Request
+	name := parameter.Name
+	switch parameter.In {
+	case openapi3.ParameterInPath:
+		_, ok := input.PathParams[name]
+		return ok
+	case openapi3.ParameterInQuery:
+		query := req.URL.Query()
+		if query.Has(name) {
+			return true
+		}
+		// Objects serialized with the deepObject or exploded form styles
+		for key := range query {
+			if strings.HasPrefix(key, name+"[") {
+				return true
+			}
+		}
+		if schema := parameter.Schema; schema != nil && schema.Value != nil && schema.Value.Type.Is(openapi3.TypeObject) {
+			for property := range schema.Value.Properties {
+				if query.Has(property) {
+					return true
+				}
+			}
+		}
+		return false
+	case openapi3.ParameterInHeader:
+		return req.Header.Get(name) != ""
+	case openapi3.ParameterInCookie:
+		_, err := req.Cookie(name)
+		return err == nil
+	}
+	return false
+}
+
+// RecordResponse accounts for the response to a request that RecordRequest
+// accounted for: its status and media type were returned, with a body matching
+// some oneOf branches. The body of input is read, and replaced with a copy.
+func (c *CoverageRecorder) RecordResponse(ctx context.Context, input *ResponseValidationInput) {
+	requestInput := input.RequestValidationInput
+	if requestInput == nil || requestInput.Route == nil || requestInput.Route.Operation == nil {
+		return
+	}
+	route := requestInput.Route
+	status, response := responseFor(route.Operation.Responses, input.Status)
+	if response == nil {
+		return
+	}
+
+	contentType := ""
+	var matched map[string][]int
+	if len(response.Content) != 0 {
+		var data []byte
+		if input.Body != nil {
+			data, _ = io.ReadAll(input.Body)
+			input.Body.Close()
+			input.SetBodyBytes(data)
+		}
+		getBody := func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(data)), nil }
+		var value any
+		if contentType, value = decodeCoverageBody(getBody, input.Header, response.Content); contentType == "" {
+			return
+		}
+		matched = matchOneOfs(responseLocation(status, contentType), response.Content[contentType].Schema, value, openapi3.VisitAsResponse())
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	oc := c.report.operation(route.Method, route.Path)
+	if oc == nil {
+		return
+	}
+	for _, rc := range oc.Responses {
+		if rc.Status == status && rc.ContentType == contentType {
+			rc.Calls++
+		}
+	}
+	oc.recordOneOfs(matched)
+}
+
+// responseFor returns the response of responses for status, with its key.
+func responseFor(responses *openapi3.Responses, status int) (string, *openapi3.Response) {
+	if responses == nil {
+		return "", nil
+	}
+	key := strconv.Itoa(status)
+	ref := responses.Value(key)
+	if ref == nil {
+		key = fmt.Sprintf("%dXX", status/100)
+		if ref = responses.Value(key); ref == nil {
+			key = "default"
+			ref = responses.Default()
+		}
+	}
+	if ref == nil || ref.Value == nil {
+		return "", nil
+	}
+	return key, ref.Value
+}
+
+// contentFor returns the media type of content for a body with header, with
+// its key, or an empty key when content has none for the body.
+func contentFor(content openapi3.Content, header http.Header) (string, *openapi3.MediaType) {
+	mediaType, _, err := mime.ParseMediaType(header.Get(headerCT))
+	if err != nil {
+		return "", nil
+	}
+	declared := content.Get(mediaType)
+	if declared == nil {
+		return "", nil
+	}
+	for key, v := range content {
+		if v == declared {
+			return key, declared
+		}
+	}
+	return "", nil
+}
+
+// decodeCoverageBody returns the key of the content of a body with header, and
+// its decoded value, or nil when it cannot be decoded. The key is empty when
+// content has none for the body.
+func decodeCoverageBody(getBody func() (io.ReadCloser, error), header http.Header, content openapi3.Content) (string, any) {
+	contentType, declared := contentFor(content, header)
+	if declared == nil || declared.Schema == nil {
+		return contentType, nil
+	}
+	body, err := getBody()
+	if err != nil {
+		return contentType, nil
+	}
+	defer body.Close()
+	encFn := func(name string) *openapi3.Encoding { return declared.Encoding[name] }
+	_, value, err := decodeBody(body, header, declared.Schema, encFn)
+	if err != nil {
+		return contentType, nil
+	}
+	return contentType, value
+}
+
+// matchOneOfs returns the indexes of the oneOf branches value matches, of
+// schema and the schemas it is made of, by the location of their oneOf.
+func matchOneOfs(location string, schema *openapi3.SchemaRef, value any, opts ...openapi3.SchemaValidationOption) map[string][]int {
+	matched := make(map[string][]int)
+	var w *schemaWalker
+	w = newSchemaWalker(func(location string, s *openapi3.Schema, value any) {
+		for i, branch := range s.OneOf {
+			if w.matches(branch, value) {
+				matched[location] = append(matched[location], i)
+			}
+		}
+	}, opts...)
+	w.walkValue(schema, location, value)
+	return matched
+}
+
+// recordOneOfs accounts for the oneOf branches matchOneOfs matched.
+func (oc *OperationCoverage) recordOneOfs(matched map[string][]int) {
+	for location, branches := range matched {
+		oneOf := oc.oneOf(location)
+		if oneOf == nil {
+			continue
+		}
+		for _, i := range branches {
+			if i < len(oneOf.Branches) {
+				oneOf.Branches[i].Calls++
+			}
+		}
+	}
+}
+
+// Report returns what was recorded so far.
+func (c *CoverageRecorder) Report() *CoverageReport {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	report := &CoverageReport{}
+	report.Merge(c.report)
+	return report
+}
+
+// RecordCoverage makes the Validator record the coverage of the requests it
+// validates and of the responses to them with c. Invalid requests are not
+// recorded, as they are not served.
+func RecordCoverage(c *CoverageRecorder) ValidatorOption {
+	return func(v *Validator) {
+		v.coverage = c
+	}
+}
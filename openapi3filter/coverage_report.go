@@ -0,0 +1,299 @@
+package openapi3filter
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+	"text/tabwriter"
+)
+
+// CoverageReport is what requests and responses exercised of the operations of
+// an OpenAPI document, as recorded by a CoverageRecorder. It marshals to JSON,
+// so that the reports of several test runs can be merged.
+type CoverageReport struct {
+	// Operations are the operations of the document, sorted by path and method.
+	Operations []*OperationCoverage `json:"operations" yaml:"operations"`
+}
+
+// OperationCoverage is what requests and responses exercised of an operation.
+type OperationCoverage struct {
+	Method      string `json:"method" yaml:"method"`
+	Path        string `json:"path" yaml:"path"`
+	OperationID string `json:"operationId,omitempty" yaml:"operationId,omitempty"`
+	// Calls is the number of requests routed to the operation.
+	Calls      int                  `json:"calls" yaml:"calls"`
+	Parameters []*ParameterCoverage `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	// Responses are the responses of the operation, one per status and
+	// content type, sorted as they are in the document.
+	Responses []*ResponseCoverage `json:"responses,omitempty" yaml:"responses,omitempty"`
+	// OneOfs are the oneOf schemas of the bodies of the operation.
+	OneOfs []*OneOfCoverage `json:"oneOfs,omitempty" yaml:"oneOfs,omitempty"`
+}
+
+// ParameterCoverage is how many requests set a parameter of an operation.
+type ParameterCoverage struct {
+	Name  string `json:"name" yaml:"name"`
+	In    string `json:"in" yaml:"in"`
+	Calls int    `json:"calls" yaml:"calls"`
+}
+
+// ResponseCoverage is how many times a response of an operation was returned.
+type ResponseCoverage struct {
+	// Status is the key of the response in the document, such as "200", "4XX"
+	// or "default".
+	Status string `json:"status" yaml:"status"`
+	// ContentType is the key of the content of the response in the document, or
+	// empty for responses without content.
+	ContentType string `json:"contentType,omitempty" yaml:"contentType,omitempty"`
+	Calls       int    `json:"calls" yaml:"calls"`
+}
+
+// OneOfCoverage is how many bodies matched each branch of a oneOf schema.
+type OneOfCoverage struct {
+	// Location is where the schema is, such as
+	// "response 200 application/json#/properties/pet": the body it is the
+	// schema of, and its JSON Pointer from the schema of the body.
+	Location string            `json:"location" yaml:"location"`
+	Branches []*BranchCoverage `json:"branches" yaml:"branches"`
+}
+
+// BranchCoverage is how many bodies matched a branch of a oneOf schema.
+type BranchCoverage struct {
+	Index int `json:"index" yaml:"index"`
+	// Ref is the reference of the branch, if it is one.
+	Ref   string `json:"ref,omitempty" yaml:"ref,omitempty"`
+	Calls int    `json:"calls" yaml:"calls"`
+}
+
+func (r *CoverageReport) operation(method, path string) *OperationCoverage {
+	for _, oc := range r.Operations {
+		if oc.Method == method && oc.Path == path {
+			return oc
+		}
+	}
+	return nil
+}
+
+func (oc *OperationCoverage) oneOf(location string) *OneOfCoverage {
+	for _, oneOf := range oc.OneOfs {
+		if oneOf.Location == location {
+			return oneOf
+		}
+	}
+	return nil
+}
+
+// Merge adds the counts of other to those of r, such as to sum up the reports
+// of the tests of several packages.
+func (r *CoverageReport) Merge(other *CoverageReport) {
+	for _, ooc := range other.Operations {
+		oc := r.operation(ooc.Method, ooc.Path)
+		if oc == nil {
+			oc = &OperationCoverage{Method: ooc.Method, Path: ooc.Path, OperationID: ooc.OperationID}
+			r.Operations = append(r.Operations, oc)
+		}
+		oc.Calls += ooc.Calls
+
+	parameters:
+		for _, opc := range ooc.Parameters {
+			for _, pc := range oc.Parameters {
+				if pc.Name == opc.Name && pc.In == opc.In {
+					pc.Calls += opc.Calls
+					continue parameters
+				}
+			}
+			pc := *opc
+			oc.Parameters = append(oc.Parameters, &pc)
+		}
+
+	responses:
+		for _, orc := range ooc.Responses {
+			for _, rc := range oc.Responses {
+				if rc.Status == orc.Status && rc.ContentType == orc.ContentType {
+					rc.Calls += orc.Calls
+					continue responses
+				}
+			}
+			rc := *orc
+			oc.Responses = append(oc.Responses, &rc)
+		}
+
+		for _, oOneOf := range ooc.OneOfs {
+			oneOf := oc.oneOf(oOneOf.Location)
+			if oneOf == nil {
+				oneOf = &OneOfCoverage{Location: oOneOf.Location}
+				oc.OneOfs = append(oc.OneOfs, oneOf)
+			}
+		branches:
+			for _, obc := range oOneOf.Branches {
+				for _, bc := range oneOf.Branches {
+					if bc.Index == obc.Index {
+						bc.Calls += obc.Calls
+						continue branches
+					}
+				}
+				bc := *obc
+				oneOf.Branches = append(oneOf.Branches, &bc)
+			}
+		}
+	}
+}
+
+// CoverageCounts are how many parts of the operations of a document were
+// exercised, out of how many there are.
+type CoverageCounts struct {
+	Operations, OperationsTotal int
+	Parameters, ParametersTotal int
+	Responses, ResponsesTotal   int
+	Branches, BranchesTotal     int
+}
+
+// Counts returns how many operations, parameters, responses and oneOf branches
+// were exercised, out of how many there are.
+func (r *CoverageReport) Counts() CoverageCounts {
+	var counts CoverageCounts
+	count := func(calls int, exercised, total *int) {
+		*total++
+		if calls > 0 {
+			*exercised++
+		}
+	}
+	for _, oc := range r.Operations {
+		count(oc.Calls, &counts.Operations, &counts.OperationsTotal)
+		for _, pc := range oc.Parameters {
+			count(pc.Calls, &counts.Parameters, &counts.ParametersTotal)
+		}
+		for _, rc := range oc.Responses {
+			count(rc.Calls, &counts.Responses, &counts.ResponsesTotal)
+		}
+		for _, oneOf := range oc.OneOfs {
+			for _, bc := range oneOf.Branches {
+				count(bc.Calls, &counts.Branches, &counts.BranchesTotal)
+			}
+		}
+	}
+	return counts
+}
+
+// Percent returns the percentage of the operations, parameters, responses and
+// oneOf branches that were exercised, or 100 for a document without any.
+func (r *CoverageReport) Percent() float64 {
+	c := r.Counts()
+	total := c.OperationsTotal + c.ParametersTotal + c.ResponsesTotal + c.BranchesTotal
+	if total == 0 {
+		return 100
+	}
+	return 100 * float64(c.Operations+c.Parameters+c.Responses+c.Branches) / float64(total)
+}
+
+// Check returns an error when less than minimum percent of the document was
+// exercised, such as to fail a CI job.
+func (r *CoverageReport) Check(minimum float64) error {
+	if percent := r.Percent(); percent < minimum {
+		return fmt.Errorf("API coverage %.1f%% is below %.1f%%", percent, minimum)
+	}
+	return nil
+}
+
+func (c CoverageCounts) String() string {
+	return fmt.Sprintf("%d/%d operations, %d/%d parameters, %d/%d responses, %d/%d oneOf branches",
+		c.Operations, c.OperationsTotal,
+		c.Parameters, c.ParametersTotal,
+		c.Responses, c.ResponsesTotal,
+		c.Branches, c.BranchesTotal)
+}
+
+// WriteText writes r to w as a table of every part of every operation, with the
+// number of times it was exercised.
+func (r *CoverageReport) WriteText(w io.Writer) error {
+	if _, err := fmt.Fprintf(w, "API coverage: %.1f%% (%s)\n", r.Percent(), r.Counts()); err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, oc := range r.Operations {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", oc.Method, oc.Path, oc.OperationID, coverageCalls(oc.Calls))
+		for _, pc := range oc.Parameters {
+			fmt.Fprintf(tw, "\t  parameter\t%s %s\t%s\n", pc.In, pc.Name, coverageCalls(pc.Calls))
+		}
+		for _, rc := range oc.Responses {
+			fmt.Fprintf(tw, "\t  response\t%s\t%s\n", responseLabel(rc), coverageCalls(rc.Calls))
+		}
+		for _, oneOf := range oc.OneOfs {
+			for _, bc := range oneOf.Branches {
+				fmt.Fprintf(tw, "\t  oneOf\t%s\t%s\n", branchLabel(oneOf, bc), coverageCalls(bc.Calls))
+			}
+		}
+	}
+	return tw.Flush()
+}
+
+func responseLabel(rc *ResponseCoverage) string {
+	if rc.ContentType == "" {
+		return rc.Status
+	}
+	return rc.Status + " " + rc.ContentType
+}
+
+func branchLabel(oneOf *OneOfCoverage, bc *BranchCoverage) string {
+	label := oneOf.Location + "/oneOf/" + strconv.Itoa(bc.Index)
+	if bc.Ref != "" {
+		label += " (" + bc.Ref + ")"
+	}
+	return label
+}
+
+func coverageCalls(n int) string {
+	if n == 0 {
+		return "-"
+	}
+	return strconv.Itoa(n)
+}
+
+// WriteJSON writes r to w as indented JSON.
+func (r *CoverageReport) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+var coverageHTMLTemplate = template.Must(template.New("coverage").Funcs(template.FuncMap{
+	"calls":         coverageCalls,
+	"responseLabel": responseLabel,
+	"branchLabel":   branchLabel,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>API coverage</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; margin-bottom: 1em; }
+th, td { padding: 2px 8px; text-align: left; }
+td.calls { text-align: right; }
+tr.hit td.calls { background: #c8e6c9; }
+tr.miss td.calls { background: #ffcdd2; }
+</style>
+</head>
+<body>
+<h1>API coverage: {{printf "%.1f" .Percent}}%</h1>
+<p>{{.Counts}}</p>
+{{range .Operations}}
+<h2>{{.Method}} {{.Path}}{{with .OperationID}} ({{.}}){{end}}</h2>
+<table>
+<tr class="{{if .Calls}}hit{{else}}miss{{end}}"><th>calls</th><td></td><td class="calls">{{calls .Calls}}</td></tr>
+{{range .Parameters}}<tr class="{{if .Calls}}hit{{else}}miss{{end}}"><th>parameter</th><td>{{.In}} {{.Name}}</td><td class="calls">{{calls .Calls}}</td></tr>
+{{end}}{{range .Responses}}<tr class="{{if .Calls}}hit{{else}}miss{{end}}"><th>response</th><td>{{responseLabel .}}</td><td class="calls">{{calls .Calls}}</td></tr>
+{{end}}{{range $oneOf := .OneOfs}}{{range .Branches}}<tr class="{{if .Calls}}hit{{else}}miss{{end}}"><th>oneOf</th><td>{{branchLabel $oneOf .}}</td><td class="calls">{{calls .Calls}}</td></tr>
+{{end}}{{end}}</table>
+{{end}}
+</body>
+</html>
+`))
+
+// WriteHTML writes r to w as an HTML page, with the parts of operations that
+// were not exercised highlighted.
+func (r *CoverageReport) WriteHTML(w io.Writer) error {
+	return coverageHTMLTemplate.Execute(w, r)
+}
@@ -0,0 +1,236 @@
+package openapi3filter_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+const coverageSpec = `
+openapi: 3.0.0
+info:
+  title: Pets
+  version: 1.0.0
+paths:
+  /pets:
+    parameters:
+      - name: X-Request-ID
+        in: header
+        schema: {type: string}
+    get:
+      operationId: listPets
+      parameters:
+        - name: limit
+          in: query
+          schema: {type: integer}
+      responses:
+        "200":
+          description: Pets
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Pet'
+    post:
+      operationId: createPet
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+      responses:
+        "201":
+          description: Created
+        4XX:
+          description: Invalid pet
+          content:
+            text/plain:
+              schema: {type: string}
+components:
+  schemas:
+    Pet:
+      oneOf:
+        - $ref: '#/components/schemas/Cat'
+        - $ref: '#/components/schemas/Dog'
+    Cat:
+      type: object
+      required: [meows]
+      properties:
+        meows: {type: boolean}
+    Dog:
+      type: object
+      required: [barks]
+      properties:
+        barks: {type: boolean}
+`
+
+func TestCoverageRecorder(t *testing.T) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData([]byte(coverageSpec))
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(loader.Context))
+	router, err := gorillamux.NewRouter(doc)
+	require.NoError(t, err)
+
+	recorder := openapi3filter.NewCoverageRecorder(doc)
+	handler := openapi3filter.NewValidator(router, openapi3filter.RecordCoverage(recorder)).Middleware(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodPost {
+				w.WriteHeader(http.StatusCreated)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`[{"meows":true},{"meows":false}]`))
+		}))
+	serve := func(method, target, body string) {
+		t.Helper()
+		var req *http.Request
+		if body == "" {
+			req = httptest.NewRequest(method, target, nil)
+		} else {
+			req = httptest.NewRequest(method, target, strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+		}
+		req.Header.Set("X-Request-ID", "42")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		require.Less(t, rec.Code, 300, rec.Body.String())
+	}
+	serve(http.MethodGet, "/pets?limit=2", "")
+	serve(http.MethodGet, "/pets", "")
+	serve(http.MethodPost, "/pets", `{"barks":true}`)
+
+	report := recorder.Report()
+	require.Equal(t, []*openapi3filter.OperationCoverage{{
+		Method:      http.MethodGet,
+		Path:        "/pets",
+		OperationID: "listPets",
+		Calls:       2,
+		Parameters: []*openapi3filter.ParameterCoverage{
+			{Name: "limit", In: "query", Calls: 1},
+			{Name: "X-Request-ID", In: "header", Calls: 2},
+		},
+		Responses: []*openapi3filter.ResponseCoverage{
+			{Status: "200", ContentType: "application/json", Calls: 2},
+		},
+		OneOfs: []*openapi3filter.OneOfCoverage{{
+			Location: "response 200 application/json#/items",
+			Branches: []*openapi3filter.BranchCoverage{
+				{Index: 0, Ref: "#/components/schemas/Cat", Calls: 4},
+				{Index: 1, Ref: "#/components/schemas/Dog"},
+			},
+		}},
+	}, {
+		Method:      http.MethodPost,
+		Path:        "/pets",
+		OperationID: "createPet",
+		Calls:       1,
+		Parameters: []*openapi3filter.ParameterCoverage{
+			{Name: "X-Request-ID", In: "header", Calls: 1},
+		},
+		Responses: []*openapi3filter.ResponseCoverage{
+			{Status: "201", Calls: 1},
+			{Status: "4XX", ContentType: "text/plain"},
+		},
+		OneOfs: []*openapi3filter.OneOfCoverage{{
+			Location: "request application/json#",
+			Branches: []*openapi3filter.BranchCoverage{
+				{Index: 0, Ref: "#/components/schemas/Cat"},
+				{Index: 1, Ref: "#/components/schemas/Dog", Calls: 1},
+			},
+		}},
+	}}, report.Operations)
+
+	require.Equal(t, "2/2 operations, 3/3 parameters, 2/3 responses, 2/4 oneOf branches", report.Counts().String())
+	require.InDelta(t, 75, report.Percent(), 0.01)
+	require.NoError(t, report.Check(75))
+	require.EqualError(t, report.Check(80), "API coverage 75.0% is below 80.0%")
+
+	var text strings.Builder
+	require.NoError(t, report.WriteText(&text))
+	require.Equal(t, `API coverage: 75.0% (2/2 operations, 3/3 parameters, 2/3 responses, 2/4 oneOf branches)
+GET   /pets        listPets                                                                 2
+        parameter  query limit                                                              1
+        parameter  header X-Request-ID                                                      2
+        response   200 application/json                                                     2
+        oneOf      response 200 application/json#/items/oneOf/0 (#/components/schemas/Cat)  4
+        oneOf      response 200 application/json#/items/oneOf/1 (#/components/schemas/Dog)  -
+POST  /pets        createPet                                                                1
+        parameter  header X-Request-ID                                                      1
+        response   201                                                                      1
+        response   4XX text/plain                                                           -
+        oneOf      request application/json#/oneOf/0 (#/components/schemas/Cat)             -
+        oneOf      request application/json#/oneOf/1 (#/components/schemas/Dog)             1
+`, text.String())
+
+	var html bytes.Buffer
+	require.NoError(t, report.WriteHTML(&html))
+	require.Contains(t, html.String(), "<h1>API coverage: 75.0%</h1>")
+	require.Contains(t, html.String(), `<tr class="miss"><th>response</th><td>4XX text/plain</td><td class="calls">-</td></tr>`)
+
+	// Reports merge through JSON, such as those of several test runs
+	var data bytes.Buffer
+	require.NoError(t, report.WriteJSON(&data))
+	var decoded openapi3filter.CoverageReport
+	require.NoError(t, json.Unmarshal(data.Bytes(), &decoded))
+	require.Equal(t, report, &decoded)
+	empty := openapi3filter.NewCoverageRecorder(doc).Report()
+	empty.Merge(&decoded)
+	empty.Merge(&decoded)
+	require.Equal(t, 4, empty.Operations[0].Calls)
+	require.Equal(t, 8, empty.Operations[0].OneOfs[0].Branches[0].Calls)
+	require.Equal(t, report.Counts(), empty.Counts())
+}
+
+func TestCoverageRecorderPathItemParameters(t *testing.T) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData([]byte(`
+openapi: 3.0.0
+info: {title: Pets, version: 1.0.0}
+paths:
+  /pets:
+    parameters:
+      - name: filter
+        in: query
+        schema:
+          type: object
+          properties:
+            species: {type: string}
+    get:
+      responses:
+        "200": {description: Pets}
+`))
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(loader.Context))
+	router, err := gorillamux.NewRouter(doc)
+	require.NoError(t, err)
+	recorder := openapi3filter.NewCoverageRecorder(doc)
+
+	// Objects of the exploded form style set their properties as query parameters
+	var wg sync.WaitGroup
+	for _, target := range []string{"/pets?species=cat", "/pets", "/pets?species=dog"} {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		route, pathParams, err := router.FindRoute(req)
+		require.NoError(t, err)
+		wg.Go(func() {
+			recorder.RecordRequest(req.Context(), &openapi3filter.RequestValidationInput{Request: req, PathParams: pathParams, Route: route})
+		})
+	}
+	wg.Wait()
+
+	oc := recorder.Report().Operations[0]
+	require.Equal(t, 3, oc.Calls)
+	require.Equal(t, []*openapi3filter.ParameterCoverage{{Name: "filter", In: "query", Calls: 2}}, oc.Parameters)
+}
@@ -12,11 +12,12 @@ import (
 
 // Validator provides HTTP request and response validation middleware.
 type Validator struct {
-	router  routers.Router
-	errFunc ErrFunc
-	logFunc LogFunc
-	strict  bool
-	options Options
+	router   routers.Router
+	errFunc  ErrFunc
+	logFunc  LogFunc
+	strict   bool
+	options  Options
+	coverage *CoverageRecorder
 }
 
 // ErrFunc handles errors that may occur during validation.
@@ -136,6 +137,9 @@ func (v *Validator) Middleware(h http.Handler) http.Handler {
 			v.errFunc(ctx, w, http.StatusBadRequest, ErrCodeRequestInvalid, err)
 			return
 		}
+		if v.coverage != nil {
+			v.coverage.RecordRequest(ctx, requestValidationInput)
+		}
 
 		var wr responseWrapper
 		if v.strict {
@@ -146,13 +150,18 @@ func (v *Validator) Middleware(h http.Handler) http.Handler {
 
 		h.ServeHTTP(wr, r)
 
-		if err = ValidateResponse(ctx, &ResponseValidationInput{
+		responseValidationInput := &ResponseValidationInput{
 			RequestValidationInput: requestValidationInput,
 			Status:                 wr.statusCode(),
 			Header:                 wr.Header(),
 			Body:                   io.NopCloser(bytes.NewBuffer(wr.bodyContents())),
 			Options:                &v.options,
-		}); err != nil {
+		}
+		err = ValidateResponse(ctx, responseValidationInput)
+		if v.coverage != nil {
+			v.coverage.RecordResponse(ctx, responseValidationInput)
+		}
+		if err != nil {
 			v.logFunc(ctx, "invalid response", err)
 			if v.strict {
 				v.errFunc(ctx, w, http.StatusInternalServerError, ErrCodeResponseInvalid, err)
@@ -1238,6 +1238,26 @@ func UnregisterBodyDecoder(contentType string) {
 	delete(bodyDecoders, contentType)
 }
 
+// DecodeExample is an openapi3.ExampleDecoder which decodes the external
+// values of examples with the registered body decoders, as the bodies of the
+// content type they are examples of. Use it with
+// openapi3.ExternalExamplesDecoder. Contents without a content type are
+// decoded by openapi3.DecodeExample.
+func DecodeExample(data []byte, contentType string, schema *openapi3.SchemaRef) (any, error) {
+	if contentType == "" {
+		return openapi3.DecodeExample(data, contentType, schema)
+	}
+	header := http.Header{headerCT: {contentType}}
+	_, value, err := decodeBody(bytes.NewReader(data), header, schema, nil)
+	if err != nil {
+		if e, ok := err.(*ParseError); ok && e.Kind == KindUnsupportedFormat {
+			return nil, fmt.Errorf("%w %q", openapi3.ErrUnsupportedExampleContentType, contentType)
+		}
+		return nil, err
+	}
+	return value, nil
+}
+
 var headerCT = http.CanonicalHeaderKey("Content-Type")
 
 const (
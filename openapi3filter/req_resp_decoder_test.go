@@ -2048,3 +2048,25 @@ func matchParseError(t *testing.T, got, want error) {
 		matchParseError(t, gErr.Cause, wErr.Cause)
 	}
 }
+
+func TestDecodeExample(t *testing.T) {
+	schema := objectOf("name", stringSchema, "age", integerSchema)
+
+	value, err := DecodeExample([]byte(`name=Rex&age=3`), "application/x-www-form-urlencoded", schema)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"name": "Rex", "age": int64(3)}, value)
+
+	value, err = DecodeExample([]byte(`{"name": "Rex"}`), "application/json; charset=utf-8", schema)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"name": "Rex"}, value)
+
+	value, err = DecodeExample([]byte(`name: Rex`), "", schema)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"name": "Rex"}, value)
+
+	_, err = DecodeExample([]byte(`{`), "application/json", schema)
+	require.Error(t, err)
+
+	_, err = DecodeExample([]byte(`<pet/>`), "application/unknown", schema)
+	require.ErrorIs(t, err, openapi3.ErrUnsupportedExampleContentType)
+}
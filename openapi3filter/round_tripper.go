@@ -0,0 +1,192 @@
+package openapi3filter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+
+	"github.com/getkin/kin-openapi/routers"
+)
+
+// ViolationMode is what a RoundTripper does with the requests and responses
+// that do not comply with the OpenAPI document.
+type ViolationMode int
+
+const (
+	// FailOnViolation makes RoundTrip return the Violation as its error. An
+	// invalid request is not sent.
+	FailOnViolation ViolationMode = iota
+	// LogViolations logs violations with the LogFunc of the RoundTripper, and
+	// sends requests and returns responses as they are.
+	LogViolations
+	// CollectViolations keeps violations for Violations to return, and sends
+	// requests and returns responses as they are.
+	CollectViolations
+)
+
+// Violation is a request or response that does not comply with the OpenAPI
+// document.
+type Violation struct {
+	// Request is the request that was sent, or would have been.
+	Request *http.Request
+	// StatusCode is the status of the invalid response, or 0 for an invalid
+	// request.
+	StatusCode int
+	// Err is a *RequestError or a *ResponseError, or the error of the router
+	// for requests that match no operation.
+	Err error
+}
+
+func (v *Violation) Error() string {
+	if v.StatusCode != 0 {
+		return fmt.Sprintf("%s %s: invalid response with status %d: %v", v.Request.Method, v.Request.URL, v.StatusCode, v.Err)
+	}
+	return fmt.Sprintf("%s %s: invalid request: %v", v.Request.Method, v.Request.URL, v.Err)
+}
+
+func (v *Violation) Unwrap() error { return v.Err }
+
+// RoundTripper is an http.RoundTripper that validates the requests it sends
+// and the responses it receives against the OpenAPI document of the API they
+// are for. It is the client side counterpart of Validator: set it as the
+// Transport of the http.Client of the API.
+//
+// The body of a response is read to validate it, and replaced with a copy that
+// can be read again.
+type RoundTripper struct {
+	// Transport sends the requests, http.DefaultTransport when nil.
+	Transport http.RoundTripper
+	// Router finds the operation of a request, out of the servers and paths of
+	// the document.
+	Router routers.Router
+	// Options are the options of validation. Requests are not authenticated
+	// unless Options.AuthenticationFunc is set.
+	Options Options
+	// Mode is what to do with violations, failing by default.
+	Mode ViolationMode
+	// LogFunc logs violations in LogViolations mode, with the log package when
+	// nil.
+	LogFunc LogFunc
+
+	mu         sync.Mutex
+	violations []*Violation
+}
+
+var _ http.RoundTripper = (*RoundTripper)(nil)
+
+// NewRoundTripper returns a RoundTripper sending requests with transport, or
+// http.DefaultTransport when nil, that validates them with router.
+func NewRoundTripper(router routers.Router, transport http.RoundTripper) *RoundTripper {
+	return &RoundTripper{Transport: transport, Router: router}
+}
+
+// Violations returns the violations collected in CollectViolations mode.
+func (rt *RoundTripper) Violations() []*Violation {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	return append([]*Violation(nil), rt.violations...)
+}
+
+// ResetViolations forgets the violations collected so far.
+func (rt *RoundTripper) ResetViolations() {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	rt.violations = nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (rt *RoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	transport := rt.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	options := rt.Options
+	if options.AuthenticationFunc == nil {
+		options.AuthenticationFunc = NoopAuthenticationFunc
+	}
+
+	// The request sent and the one validated each read their own copy of the
+	// body, and validation is free to change its copy, such as to set defaults
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req = req.Clone(ctx)
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(body)), nil }
+	}
+	validated := req.Clone(ctx)
+	validated.Body = io.NopCloser(bytes.NewReader(body))
+
+	route, pathParams, err := rt.Router.FindRoute(validated)
+	if err != nil {
+		if err := rt.violate(ctx, &Violation{Request: req, Err: err}); err != nil {
+			return nil, err
+		}
+		return transport.RoundTrip(req)
+	}
+	requestValidationInput := &RequestValidationInput{
+		Request:    validated,
+		PathParams: pathParams,
+		Route:      route,
+		Options:    &options,
+	}
+	if err := ValidateRequest(ctx, requestValidationInput); err != nil {
+		if err := rt.violate(ctx, &Violation{Request: req, Err: err}); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+
+	if err := ValidateResponse(ctx, &ResponseValidationInput{
+		RequestValidationInput: requestValidationInput,
+		Status:                 resp.StatusCode,
+		Header:                 resp.Header,
+		Body:                   io.NopCloser(bytes.NewReader(data)),
+		Options:                &options,
+	}); err != nil {
+		if err := rt.violate(ctx, &Violation{Request: req, StatusCode: resp.StatusCode, Err: err}); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+// violate handles v as the mode of rt says, and returns it when it fails the
+// round trip.
+func (rt *RoundTripper) violate(ctx context.Context, v *Violation) error {
+	switch rt.Mode {
+	case LogViolations:
+		if rt.LogFunc != nil {
+			rt.LogFunc(ctx, "openapi violation", v)
+		} else {
+			log.Printf("openapi violation: %v", v)
+		}
+	case CollectViolations:
+		rt.mu.Lock()
+		rt.violations = append(rt.violations, v)
+		rt.mu.Unlock()
+	default:
+		return v
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package openapi3filter_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+const roundTripperSpec = `
+openapi: 3.0.0
+info:
+  title: Partner
+  version: 1.0.0
+servers:
+  - url: SERVER/v1
+paths:
+  /pets:
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name: {type: string}
+                status: {type: string, default: available}
+      responses:
+        "201":
+          description: Created
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [id, name]
+                properties:
+                  id: {type: integer}
+                  name: {type: string}
+`
+
+// newRoundTripperTest returns a RoundTripper for a server answering response,
+// the URL of its pets and the bodies it received.
+func newRoundTripperTest(t *testing.T, response string) (*openapi3filter.RoundTripper, string, *[]string) {
+	t.Helper()
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		received = append(received, string(body))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData([]byte(strings.Replace(roundTripperSpec, "SERVER", server.URL, 1)))
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(loader.Context))
+	router, err := gorillamux.NewRouter(doc)
+	require.NoError(t, err)
+	return openapi3filter.NewRoundTripper(router, nil), server.URL + "/v1/pets", &received
+}
+
+func TestRoundTripper(t *testing.T) {
+	rt, url, received := newRoundTripperTest(t, `{"id": 1, "name": "Tom"}`)
+	client := &http.Client{Transport: rt}
+
+	resp, err := client.Post(url, "application/json", strings.NewReader(`{"name": "Tom"}`))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"id": 1, "name": "Tom"}`, string(body))
+	// The defaults validation sets are not sent
+	require.Equal(t, []string{`{"name": "Tom"}`}, *received)
+
+	// An invalid request is not sent
+	_, err = client.Post(url, "application/json", strings.NewReader(`{}`))
+	var violation *openapi3filter.Violation
+	require.ErrorAs(t, err, &violation)
+	require.Zero(t, violation.StatusCode)
+	var requestError *openapi3filter.RequestError
+	require.ErrorAs(t, err, &requestError)
+	require.ErrorContains(t, err, `POST `+url+`: invalid request: request body has an error: doesn't match schema: Error at "/name": property "name" is missing`)
+	require.Len(t, *received, 1)
+
+	_, err = client.Get(url)
+	require.ErrorIs(t, err, routers.ErrMethodNotAllowed)
+}
+
+func TestRoundTripperInvalidResponse(t *testing.T) {
+	rt, url, received := newRoundTripperTest(t, `{"name": "Tom"}`)
+	client := &http.Client{Transport: rt}
+
+	_, err := client.Post(url, "application/json", strings.NewReader(`{"name": "Tom"}`))
+	var violation *openapi3filter.Violation
+	require.ErrorAs(t, err, &violation)
+	require.Equal(t, http.StatusCreated, violation.StatusCode)
+	var responseError *openapi3filter.ResponseError
+	require.ErrorAs(t, err, &responseError)
+	require.Len(t, *received, 1)
+
+	var logged []error
+	rt.Mode = openapi3filter.LogViolations
+	rt.LogFunc = func(_ context.Context, message string, err error) {
+		require.Equal(t, "openapi violation", message)
+		logged = append(logged, err)
+	}
+	resp, err := client.Post(url, "application/json", strings.NewReader(`{"name": "Tom"}`))
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.Equal(t, `{"name": "Tom"}`, string(body))
+	require.Len(t, logged, 1)
+	require.True(t, errors.As(logged[0], &responseError))
+}
+
+func TestRoundTripperCollect(t *testing.T) {
+	rt, url, received := newRoundTripperTest(t, `{"id": "1", "name": "Tom"}`)
+	rt.Mode = openapi3filter.CollectViolations
+	client := &http.Client{Transport: rt}
+
+	for _, body := range []string{`{"name": "Tom"}`, `{"name": 1}`} {
+		resp, err := client.Post(url, "application/json", strings.NewReader(body))
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+	}
+	require.Equal(t, []string{`{"name": "Tom"}`, `{"name": 1}`}, *received)
+
+	violations := rt.Violations()
+	require.Len(t, violations, 3)
+	require.Equal(t, http.StatusCreated, violations[0].StatusCode)
+	require.Zero(t, violations[1].StatusCode)
+	require.Equal(t, http.StatusCreated, violations[2].StatusCode)
+
+	rt.ResetViolations()
+	require.Empty(t, rt.Violations())
+}
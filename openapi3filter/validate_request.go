@@ -30,9 +30,14 @@ var ErrInvalidEmptyValue = errors.New("empty value is not allowed")
 //
 // Note: One can tune the behavior of uniqueItems: true verification
 // by registering a custom function with openapi3.RegisterArrayUniqueItemsChecker
-func ValidateRequest(ctx context.Context, input *RequestValidationInput) error {
+func ValidateRequest(ctx context.Context, input *RequestValidationInput) (err error) {
 	var me openapi3.MultiError
 
+	// Values decoded by a previous validation are discarded,
+	// and the outcome of this one is kept for BindRequest
+	decoded := input.resetDecodedRequest()
+	defer func() { decoded.validated, decoded.err = true, err }()
+
 	options := input.Options
 	if options == nil {
 		options = &Options{}
@@ -216,6 +221,10 @@ func ValidateParameter(ctx context.Context, input *RequestValidationInput, param
 		}
 	}
 
+	if found || value != nil {
+		input.decodedRequest().setParameter(parameter, value)
+	}
+
 	// Validate a parameter's value and presence.
 	if parameter.Required && !found {
 		return &RequestError{Input: input, Parameter: parameter, Reason: ErrInvalidRequired.Error(), Err: ErrInvalidRequired}
@@ -367,6 +376,8 @@ func ValidateRequestBody(ctx context.Context, input *RequestValidationInput, req
 		}
 	}
 
+	input.decodedRequest().setBody(value)
+
 	if defaultsSet {
 		var err error
 		if data, err = encodeBody(value, mediaType); err != nil {
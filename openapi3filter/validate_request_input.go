@@ -26,6 +26,9 @@ type RequestValidationInput struct {
 	Route        *routers.Route
 	Options      *Options
 	ParamDecoder ContentParameterDecoder
+
+	// decoded holds the values decoded while validating the request, for BindRequest
+	decoded *decodedRequest
 }
 
 func (input *RequestValidationInput) GetQueryParams() url.Values {
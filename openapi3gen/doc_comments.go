@@ -0,0 +1,277 @@
+package openapi3gen
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// packageDoc contains the doc comments of the types declared in a package.
+type packageDoc struct {
+	// Types maps type names to their docs.
+	// A nil value indicates the name is declared more than once (e.g. in function bodies).
+	Types map[string]*typeDoc
+}
+
+// typeDoc contains the doc comments of a type and of its struct fields.
+type typeDoc struct {
+	Doc    string
+	Fields map[string]string
+}
+
+// docComment is a doc comment split into its description and markers.
+type docComment struct {
+	Description string
+	Example     any
+	RawExample  string
+	HasExample  bool
+	Deprecated  bool
+}
+
+// UseDocComments enables reading the Go sources of the packages declaring
+// the generated types, setting schema descriptions from type and field doc comments.
+// A `// Example: ...` line sets the schema example (decoded as JSON when possible)
+// and a `// Deprecated:` paragraph marks the schema as deprecated.
+// Sources are located by running `go list` in the working directory,
+// so types from other modules in the build, including vendored ones,
+// are described as well. Types of the standard library, whose doc comments document
+// the Go API rather than the values, and types whose sources cannot be found are left undescribed.
+func UseDocComments() Option {
+	return func(x *generatorOpt) { x.useDocComments = true }
+}
+
+// applyTypeDoc sets schema fields from the doc comment of named type t.
+func (g *Generator) applyTypeDoc(t reflect.Type, schema *openapi3.Schema) {
+	if t.Name() == "" || t.PkgPath() == "" {
+		return
+	}
+	td := g.packageDoc(t.PkgPath()).Types[localTypeName(t)]
+	if td == nil || td.Doc == "" {
+		return
+	}
+	parseDocComment(td.Doc).apply(schema)
+}
+
+// withFieldDoc returns a schema reference for a struct field, described by the field's doc comment.
+// Shared schemas are copied rather than modified.
+func (g *Generator) withFieldDoc(t reflect.Type, fieldInfo theFieldInfo, ref *openapi3.SchemaRef) *openapi3.SchemaRef {
+	if ref == nil || ref.Value == nil || strings.HasPrefix(ref.Ref, "#/components/schemas/") {
+		// Siblings of $ref are ignored in OpenAPI 3.0
+		return ref
+	}
+	owner, field := getStructFieldOwner(t, fieldInfo)
+	if owner.Name() == "" || owner.PkgPath() == "" {
+		return ref
+	}
+	td := g.packageDoc(owner.PkgPath()).Types[localTypeName(owner)]
+	if td == nil {
+		return ref
+	}
+	doc, ok := td.Fields[field.Name]
+	if !ok || doc == "" {
+		return ref
+	}
+
+	schema := *ref.Value
+	parseDocComment(doc).apply(&schema)
+	return openapi3.NewSchemaRef("", &schema)
+}
+
+// getStructFieldOwner returns the struct type declaring the field, along with the field.
+func getStructFieldOwner(t reflect.Type, fieldInfo theFieldInfo) (reflect.Type, reflect.StructField) {
+	var ff reflect.StructField
+	for i, index := range fieldInfo.Index {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		ff = t.Field(index)
+		if i < len(fieldInfo.Index)-1 {
+			t = ff.Type
+		}
+	}
+	return t, ff
+}
+
+func (dc docComment) apply(schema *openapi3.Schema) {
+	if dc.Description != "" {
+		schema.Description = dc.Description
+	}
+	if dc.HasExample {
+		example := dc.Example
+		if _, ok := example.(string); !ok && schema.Type.Is("string") {
+			example = dc.RawExample
+		}
+		schema.Example = example
+	}
+	if dc.Deprecated {
+		schema.Deprecated = true
+	}
+}
+
+// parseDocComment extracts the description, example and deprecation markers from a doc comment.
+func parseDocComment(text string) docComment {
+	var dc docComment
+	var lines []string
+	for line := range strings.SplitSeq(strings.TrimSpace(text), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if value, ok := strings.CutPrefix(trimmed, "Example:"); ok {
+			dc.HasExample = true
+			dc.RawExample = strings.TrimSpace(value)
+			if err := json.Unmarshal([]byte(dc.RawExample), &dc.Example); err != nil {
+				dc.Example = dc.RawExample
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "Deprecated:") {
+			dc.Deprecated = true
+		}
+		lines = append(lines, line)
+	}
+	dc.Description = strings.TrimSpace(strings.Join(lines, "\n"))
+	return dc
+}
+
+// localTypeName returns the name a type is declared with in its sources,
+// without type arguments.
+func localTypeName(t reflect.Type) string {
+	name, _, _ := strings.Cut(t.Name(), "[")
+	return name
+}
+
+// packageDoc returns the doc comments of the package with the given import path.
+// Results, including failures to locate the package, are cached by the generator.
+func (g *Generator) packageDoc(pkgPath string) *packageDoc {
+	if pd, ok := g.packageDocs[pkgPath]; ok {
+		return pd
+	}
+	pd := loadPackageDoc(pkgPath)
+	g.packageDocs[pkgPath] = pd
+	return pd
+}
+
+// listedPackage is the subset of the `go list -json` output describing a package.
+type listedPackage struct {
+	ImportPath string
+	Dir        string
+	Standard   bool
+	GoFiles    []string
+	CgoFiles   []string
+	Error      *struct{ Err string }
+}
+
+func loadPackageDoc(pkgPath string) *packageDoc {
+	pd := &packageDoc{Types: make(map[string]*typeDoc)}
+
+	// External test packages are only known to the go command through their base package
+	basePath, _ := strings.CutSuffix(pkgPath, "_test")
+	out, err := exec.Command("go", "list", "-e", "-json", "-test", "--", basePath).Output()
+	if err != nil {
+		return pd
+	}
+	// Prefer the variant of the package compiled with its tests, declaring the most types
+	var pkg *listedPackage
+	for dec := json.NewDecoder(bytes.NewReader(out)); ; {
+		var p listedPackage
+		if err := dec.Decode(&p); err != nil {
+			if !errors.Is(err, io.EOF) {
+				return pd
+			}
+			break
+		}
+		// Test variants are listed as "path [path.test]"
+		importPath, _, _ := strings.Cut(p.ImportPath, " ")
+		if importPath == pkgPath && (pkg == nil || len(p.GoFiles) > len(pkg.GoFiles)) {
+			pkg = &p
+		}
+	}
+	if pkg == nil || pkg.Error != nil || pkg.Standard {
+		return pd
+	}
+
+	fset := token.NewFileSet()
+	for _, name := range append(pkg.GoFiles, pkg.CgoFiles...) {
+		file, err := parser.ParseFile(fset, filepath.Join(pkg.Dir, name), nil, parser.ParseComments|parser.SkipObjectResolution)
+		if err != nil {
+			continue
+		}
+		ast.Inspect(file, func(node ast.Node) bool {
+			decl, ok := node.(*ast.GenDecl)
+			if !ok || decl.Tok != token.TYPE {
+				return true
+			}
+			for _, spec := range decl.Specs {
+				ts := spec.(*ast.TypeSpec)
+				name := ts.Name.Name
+				if _, ok := pd.Types[name]; ok {
+					pd.Types[name] = nil
+					continue
+				}
+				doc := ts.Doc
+				if doc == nil && len(decl.Specs) == 1 {
+					doc = decl.Doc
+				}
+				pd.Types[name] = &typeDoc{
+					Doc:    doc.Text(),
+					Fields: structFieldDocs(ts.Type),
+				}
+			}
+			return true
+		})
+	}
+	return pd
+}
+
+// structFieldDocs returns the doc comments of the fields of a struct type expression,
+// falling back to their line comments.
+func structFieldDocs(expr ast.Expr) map[string]string {
+	st, ok := expr.(*ast.StructType)
+	if !ok || st.Fields == nil {
+		return nil
+	}
+	docs := make(map[string]string, len(st.Fields.List))
+	for _, field := range st.Fields.List {
+		doc := field.Doc.Text()
+		if doc == "" {
+			doc = field.Comment.Text()
+		}
+		if doc == "" {
+			continue
+		}
+		if len(field.Names) == 0 {
+			// Embedded field, named after its type
+			if name := embeddedFieldName(field.Type); name != "" {
+				docs[name] = doc
+			}
+		}
+		for _, name := range field.Names {
+			docs[name.Name] = doc
+		}
+	}
+	return docs
+}
+
+func embeddedFieldName(expr ast.Expr) string {
+	switch x := expr.(type) {
+	case *ast.Ident:
+		return x.Name
+	case *ast.StarExpr:
+		return embeddedFieldName(x.X)
+	case *ast.SelectorExpr:
+		return x.Sel.Name
+	case *ast.IndexExpr:
+		return embeddedFieldName(x.X)
+	case *ast.IndexListExpr:
+		return embeddedFieldName(x.X)
+	}
+	return ""
+}
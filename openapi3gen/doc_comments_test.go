@@ -0,0 +1,131 @@
+package openapi3gen_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/jsonpointer"
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3gen"
+	"github.com/getkin/kin-openapi/openapi3gen/internal/subpkg"
+)
+
+// DocumentedColor is a color name.
+// Example: red
+type DocumentedColor string
+
+// DocumentedPet describes a pet.
+type DocumentedPet struct {
+	// Name of the pet.
+	// Example: Rex
+	Name string `json:"name"`
+
+	Age int `json:"age"` // Age in years. Example: 3
+
+	// Weight in kilograms.
+	// Example: 4.5
+	Weight float64 `json:"weight"`
+
+	// Legacy identifier.
+	//
+	// Deprecated: use Name instead.
+	LegacyID string `json:"legacyId"`
+
+	Color DocumentedColor `json:"color"`
+
+	// Favorite toy of the pet.
+	Toy subpkg.Child `json:"toy"`
+
+	Owner DocumentedOwner `json:"owner"`
+}
+
+// DocumentedOwner owns pets.
+type DocumentedOwner struct {
+	// Code of the owner.
+	// Example: 42
+	Code string `json:"code"`
+}
+
+func TestUseDocComments(t *testing.T) {
+	schemaRef, err := openapi3gen.NewSchemaRefForValue(&DocumentedPet{}, nil, openapi3gen.UseDocComments())
+	require.NoError(t, err)
+
+	schema := schemaRef.Value
+	require.Equal(t, "DocumentedPet describes a pet.", schema.Description)
+
+	name := schema.Properties["name"].Value
+	require.Equal(t, "Name of the pet.", name.Description)
+	require.Equal(t, "Rex", name.Example)
+
+	// Line comments are used when there is no doc comment, and markers must start a line
+	require.Equal(t, "Age in years. Example: 3", schema.Properties["age"].Value.Description)
+	require.Nil(t, schema.Properties["age"].Value.Example)
+
+	require.Equal(t, 4.5, schema.Properties["weight"].Value.Example)
+
+	legacy := schema.Properties["legacyId"].Value
+	require.True(t, legacy.Deprecated)
+	require.Equal(t, "Legacy identifier.\n\nDeprecated: use Name instead.", legacy.Description)
+
+	color := schema.Properties["color"].Value
+	require.Equal(t, "DocumentedColor is a color name.", color.Description)
+	require.Equal(t, "red", color.Example)
+
+	// Field docs take precedence over type docs, types from other packages are described as well
+	require.Equal(t, "Favorite toy of the pet.", schema.Properties["toy"].Value.Description)
+
+	owner := schema.Properties["owner"].Value
+	require.Equal(t, "DocumentedOwner owns pets.", owner.Description)
+	// Examples of string schemas are kept as strings
+	require.Equal(t, "42", owner.Properties["code"].Value.Example)
+
+	// Descriptions are only set when asked to
+	schemaRef, err = openapi3gen.NewSchemaRefForValue(&DocumentedPet{}, nil)
+	require.NoError(t, err)
+	require.Empty(t, schemaRef.Value.Description)
+	require.Empty(t, schemaRef.Value.Properties["name"].Value.Description)
+}
+
+func TestUseDocCommentsDoesNotAlterSharedSchemas(t *testing.T) {
+	type Pair struct {
+		// The first one.
+		First DocumentedColor `json:"first"`
+		// The second one.
+		Second DocumentedColor `json:"second"`
+	}
+
+	schemaRef, err := openapi3gen.NewSchemaRefForValue(&Pair{}, nil, openapi3gen.UseDocComments())
+	require.NoError(t, err)
+	require.Equal(t, "The first one.", schemaRef.Value.Properties["first"].Value.Description)
+	require.Equal(t, "The second one.", schemaRef.Value.Properties["second"].Value.Description)
+}
+
+func TestUseDocCommentsSubPackage(t *testing.T) {
+	schemaRef, err := openapi3gen.NewSchemaRefForValue(&subpkg.Child{}, nil, openapi3gen.UseDocComments(), openapi3gen.UseAllExportedFields())
+	require.NoError(t, err)
+	require.Equal(t, "Child is declared in another package.", schemaRef.Value.Description)
+	require.Equal(t, "Name of the child.", schemaRef.Value.Properties["name"].Value.Description)
+}
+
+func TestUseDocCommentsOtherModule(t *testing.T) {
+	schemaRef, err := openapi3gen.NewSchemaRefForValue(&jsonpointer.Pointer{}, nil, openapi3gen.UseDocComments())
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(schemaRef.Value.Description, "Pointer is a representation of a json pointer."))
+}
+
+func TestUseDocCommentsSkipsStandardLibrary(t *testing.T) {
+	type Event struct {
+		At time.Time `json:"at"`
+		// Expiration of the event.
+		Expires time.Time     `json:"expires"`
+		Timeout time.Duration `json:"timeout"`
+	}
+
+	schemaRef, err := openapi3gen.NewSchemaRefForValue(&Event{}, nil, openapi3gen.UseDocComments())
+	require.NoError(t, err)
+	require.Empty(t, schemaRef.Value.Properties["at"].Value.Description)
+	require.Equal(t, "Expiration of the event.", schemaRef.Value.Properties["expires"].Value.Description)
+	require.Empty(t, schemaRef.Value.Properties["timeout"].Value.Description)
+}
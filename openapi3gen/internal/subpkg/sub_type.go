@@ -1,5 +1,7 @@
 package subpkg
 
+// Child is declared in another package.
 type Child struct {
+	// Name of the child.
 	Name string `yaml:"name"`
 }
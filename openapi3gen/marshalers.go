@@ -0,0 +1,167 @@
+package openapi3gen
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"maps"
+	"net/netip"
+	"reflect"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// defaultMarshalerFormats maps well-known types marshaling to JSON strings,
+// identified by their package path and name, to the format of their schema.
+var defaultMarshalerFormats = map[string]string{
+	"cloud.google.com/go/civil.Date":     "date",
+	"cloud.google.com/go/civil.DateTime": "date-time",
+	"github.com/gofrs/uuid.UUID":         "uuid",
+	"github.com/gofrs/uuid/v5.UUID":      "uuid",
+	"github.com/google/uuid.UUID":        "uuid",
+}
+
+// goDurationPattern matches the representation of time.Duration values by their String method.
+const goDurationPattern = `^(0|-?([0-9]+(\.[0-9]*)?(ns|us|µs|ms|s|m|h))+)$`
+
+var (
+	durationType      = reflect.TypeFor[time.Duration]()
+	netipAddrType     = reflect.TypeFor[netip.Addr]()
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+	setSchemarType    = reflect.TypeFor[SetSchemar]()
+)
+
+// RegisterTypeSchema overrides the schema generated for type T.
+// A deep copy of schema is used for every occurrence of T.
+func RegisterTypeSchema[T any](schema *openapi3.Schema) Option {
+	t := reflect.TypeFor[T]()
+	return func(x *generatorOpt) {
+		if x.typeSchemas == nil {
+			x.typeSchemas = make(map[reflect.Type]*openapi3.Schema)
+		}
+		x.typeSchemas[t] = schema
+	}
+}
+
+// MarshalerFormats sets the formats of the string schemas generated for types
+// implementing encoding.TextMarshaler or json.Marshaler.
+// Keys are types' package paths and names, e.g. "github.com/google/uuid.UUID".
+// Formats are set by default for the UUID types of github.com/google/uuid and github.com/gofrs/uuid
+// and for the Date and DateTime types of cloud.google.com/go/civil. An empty format removes a default one.
+func MarshalerFormats(formats map[string]string) Option {
+	return func(x *generatorOpt) {
+		if x.marshalerFormats == nil {
+			x.marshalerFormats = make(map[string]string, len(formats))
+		}
+		maps.Copy(x.marshalerFormats, formats)
+	}
+}
+
+// DurationAsString changes the default behavior of describing time.Duration
+// as an integer number of nanoseconds (as encoding/json marshals it)
+// to instead describe it as a string such as "1h30m", for types marshaling it with its String method.
+func DurationAsString() Option {
+	return func(x *generatorOpt) { x.durationAsString = true }
+}
+
+// generateForMarshaledType sets schema for types whose JSON representation
+// is not derived from their Go layout: registered types, time.Duration,
+// and types implementing encoding.TextMarshaler or json.Marshaler.
+// It reports whether t was such a type.
+func (g *Generator) generateForMarshaledType(t reflect.Type, schema *openapi3.Schema) bool {
+	if s, ok := g.opts.typeSchemas[t]; ok {
+		nullable := schema.Nullable
+		*schema = *s.Clone()
+		schema.Nullable = schema.Nullable || nullable
+		return true
+	}
+
+	if t == durationType {
+		if g.opts.durationAsString {
+			schema.Type = &openapi3.Types{"string"}
+			schema.Pattern = goDurationPattern
+			schema.Example = "1h30m"
+		} else {
+			schema.Type = &openapi3.Types{"integer"}
+			schema.Format = "int64"
+		}
+		return true
+	}
+
+	if t == netipAddrType {
+		// Addresses marshal in either notation
+		schema.Type = &openapi3.Types{"string"}
+		schema.AnyOf = openapi3.SchemaRefs{
+			openapi3.NewSchemaRef("", &openapi3.Schema{Format: "ipv4"}),
+			openapi3.NewSchemaRef("", &openapi3.Schema{Format: "ipv6"}),
+		}
+		return true
+	}
+
+	// These are handled by generateWithoutSaving
+	if t == timeType || t == rawMessageType || t.Kind() == reflect.Interface || implements(t, setSchemarType) {
+		return false
+	}
+
+	var typ string
+	switch {
+	case implements(t, jsonMarshalerType):
+		if typ = marshaledJSONType(t); typ == "" {
+			return false
+		}
+	case implements(t, textMarshalerType):
+		// encoding/json marshals these as strings
+		typ = "string"
+	default:
+		return false
+	}
+
+	schema.Type = &openapi3.Types{typ}
+	if typ == "string" {
+		schema.Format = g.marshalerFormat(t)
+	}
+	return true
+}
+
+func (g *Generator) marshalerFormat(t reflect.Type) string {
+	if t.Name() == "" {
+		return ""
+	}
+	key := t.PkgPath() + "." + t.Name()
+	if format, ok := g.opts.marshalerFormats[key]; ok {
+		return format
+	}
+	return defaultMarshalerFormats[key]
+}
+
+// implements reports whether t or *t implements iface.
+func implements(t, iface reflect.Type) bool {
+	return t.Implements(iface) || reflect.PointerTo(t).Implements(iface)
+}
+
+// marshaledJSONType returns the JSON type of the zero value of a json.Marshaler type,
+// if it is a string, number or boolean. Marshalers failing on zero values are assumed
+// to marshal to strings.
+func marshaledJSONType(t reflect.Type) (typ string) {
+	defer func() {
+		if recover() != nil {
+			typ = "string"
+		}
+	}()
+	data, err := json.Marshal(reflect.New(t).Interface())
+	if err != nil {
+		return "string"
+	}
+	data = bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(data, []byte(`"`)):
+		return "string"
+	case bytes.Equal(data, []byte("true")), bytes.Equal(data, []byte("false")):
+		return "boolean"
+	case len(data) != 0 && (data[0] == '-' || '0' <= data[0] && data[0] <= '9'):
+		return "number"
+	}
+	return ""
+}
@@ -0,0 +1,122 @@
+package openapi3gen_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/netip"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3gen"
+)
+
+type Status int
+
+func (s Status) MarshalText() ([]byte, error) { return []byte(strconv.Itoa(int(s))), nil }
+
+type Level struct {
+	value int
+}
+
+func (l Level) MarshalJSON() ([]byte, error) { return json.Marshal(l.value) }
+
+type Point struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+func (p Point) MarshalJSON() ([]byte, error) { return json.Marshal([]int{p.X, p.Y}) }
+
+type AccountID [16]byte
+
+func (id AccountID) MarshalText() ([]byte, error) { return []byte("acc"), nil }
+
+func TestMarshalerTypes(t *testing.T) {
+	type Account struct {
+		ID       AccountID     `json:"id"`
+		Addr     netip.Addr    `json:"addr"`
+		Status   Status        `json:"status"`
+		Level    *Level        `json:"level"`
+		Point    Point         `json:"point"`
+		Balance  *big.Int      `json:"balance"`
+		Created  time.Time     `json:"created"`
+		Timeout  time.Duration `json:"timeout"`
+		Metadata json.RawMessage
+	}
+
+	schemaRef, err := openapi3gen.NewSchemaRefForValue(&Account{}, nil,
+		openapi3gen.MarshalerFormats(map[string]string{
+			"github.com/getkin/kin-openapi/openapi3gen_test.AccountID": "uuid",
+		}),
+	)
+	require.NoError(t, err)
+	properties := schemaRef.Value.Properties
+
+	require.Equal(t, &openapi3.Types{"string"}, properties["id"].Value.Type)
+	require.Equal(t, "uuid", properties["id"].Value.Format)
+
+	addr := properties["addr"].Value
+	require.Equal(t, &openapi3.Types{"string"}, addr.Type)
+	require.Len(t, addr.AnyOf, 2)
+	require.Equal(t, "ipv4", addr.AnyOf[0].Value.Format)
+	require.Equal(t, "ipv6", addr.AnyOf[1].Value.Format)
+	require.NoError(t, addr.VisitJSON("192.0.2.1"))
+	require.NoError(t, addr.VisitJSON("2001:db8::1"))
+
+	require.Equal(t, &openapi3.Types{"string"}, properties["status"].Value.Type)
+
+	require.Equal(t, &openapi3.Types{"number"}, properties["level"].Value.Type)
+	require.True(t, properties["level"].Value.Nullable)
+
+	// Layouts of marshalers not producing scalars are kept
+	require.Equal(t, &openapi3.Types{"object"}, properties["point"].Value.Type)
+
+	require.Equal(t, &openapi3.Types{"number"}, properties["balance"].Value.Type)
+
+	require.Equal(t, "date-time", properties["created"].Value.Format)
+
+	require.Equal(t, &openapi3.Types{"integer"}, properties["timeout"].Value.Type)
+	require.Equal(t, "int64", properties["timeout"].Value.Format)
+}
+
+func TestDurationAsString(t *testing.T) {
+	type Config struct {
+		Timeout time.Duration `json:"timeout"`
+	}
+
+	schemaRef, err := openapi3gen.NewSchemaRefForValue(&Config{}, nil, openapi3gen.DurationAsString())
+	require.NoError(t, err)
+	timeout := schemaRef.Value.Properties["timeout"].Value
+	require.Equal(t, &openapi3.Types{"string"}, timeout.Type)
+	require.NoError(t, timeout.VisitJSON("1h30m"))
+	require.NoError(t, timeout.VisitJSON("-1.5s"))
+	require.NoError(t, timeout.VisitJSON("0"))
+	require.Error(t, timeout.VisitJSON("1 hour"))
+}
+
+func TestRegisterTypeSchema(t *testing.T) {
+	type Config struct {
+		Point  Point  `json:"point"`
+		Points *Point `json:"points"`
+	}
+
+	pointSchema := openapi3.NewArraySchema().WithItems(openapi3.NewIntegerSchema()).WithMinItems(2).WithMaxItems(2)
+	pointSchema.Extensions = map[string]any{"x-go-type": "Point"}
+	schemaRef, err := openapi3gen.NewSchemaRefForValue(&Config{}, nil, openapi3gen.RegisterTypeSchema[Point](pointSchema))
+	require.NoError(t, err)
+
+	point := schemaRef.Value.Properties["point"].Value
+	require.Equal(t, &openapi3.Types{"array"}, point.Type)
+	require.False(t, point.Nullable)
+	require.True(t, schemaRef.Value.Properties["points"].Value.Nullable)
+	// The registered schema is left untouched, and occurrences do not share its maps or slices
+	require.False(t, pointSchema.Nullable)
+	require.NotSame(t, pointSchema.Items, point.Items)
+	point.Extensions["x-go-type"] = "Other"
+	require.Equal(t, "Point", pointSchema.Extensions["x-go-type"])
+	require.Equal(t, "Point", schemaRef.Value.Properties["points"].Value.Extensions["x-go-type"])
+}
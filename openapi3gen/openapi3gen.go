@@ -66,6 +66,18 @@ type generatorOpt struct {
 	exportComponentSchemas ExportComponentSchemasOptions
 	typeNameGenerator      TypeNameGenerator
 	fieldNameGenerator     FieldNameGenerator
+	implementations        map[reflect.Type][]any
+	discriminatorProperty  string
+	embeddedStructsAsAllOf bool
+	useDocComments         bool
+	excludeParameterFields bool
+	typeSchemas            map[reflect.Type]*openapi3.Schema
+	marshalerFormats       map[string]string
+	durationAsString       bool
+
+	// err collects the invalid arguments given to options,
+	// returned by the generator
+	err error
 }
 
 // UseAllExportedFields changes the default behavior of only
@@ -120,6 +132,17 @@ type Generator struct {
 	// componentSchemaRefs is a set of schemas that must be defined in the components to avoid cycles
 	// or if we have specified create components schemas
 	componentSchemaRefs map[string]struct{}
+
+	// exportedSchemaRefs maps component references created for polymorphic
+	// and composed schemas to the name they are exported under.
+	exportedSchemaRefs map[*openapi3.SchemaRef]string
+
+	// interfaceSchemas holds the schemas of interfaces being generated,
+	// so that cyclic references to them can be resolved.
+	interfaceSchemas map[reflect.Type]*openapi3.Schema
+
+	// packageDocs caches the doc comments of packages by import path.
+	packageDocs map[string]*packageDoc
 }
 
 func NewGenerator(opts ...Option) *Generator {
@@ -131,12 +154,18 @@ func NewGenerator(opts ...Option) *Generator {
 		Types:               make(map[reflect.Type]*openapi3.SchemaRef),
 		SchemaRefs:          make(map[*openapi3.SchemaRef]int),
 		componentSchemaRefs: make(map[string]struct{}),
+		exportedSchemaRefs:  make(map[*openapi3.SchemaRef]string),
+		interfaceSchemas:    make(map[reflect.Type]*openapi3.Schema),
+		packageDocs:         make(map[string]*packageDoc),
 		opts:                *gOpt,
 	}
 }
 
 func (g *Generator) GenerateSchemaRef(t reflect.Type) (*openapi3.SchemaRef, error) {
 	//check generatorOpt consistency here
+	if g.opts.err != nil {
+		return nil, g.opts.err
+	}
 	return g.generateSchemaRefFor(nil, t, "_root", "")
 }
 
@@ -159,6 +188,11 @@ func (g *Generator) NewSchemaRefForValue(value any, schemas openapi3.Schemas) (*
 				}
 			}
 		}
+		if name, ok := g.exportedSchemaRefs[ref]; ok && schemas != nil && ref.Value != nil {
+			schemas[name] = &openapi3.SchemaRef{
+				Value: ref.Value,
+			}
+		}
 		if strings.HasPrefix(ref.Ref, "#/components/schemas/") {
 			ref.Value = nil
 		} else {
@@ -246,6 +280,18 @@ func (g *Generator) generateWithoutSaving(parents []*theTypeInfo, t reflect.Type
 	schema := &openapi3.Schema{}
 	schema.Nullable = isNullable
 
+	if g.generateForMarshaledType(t, schema) {
+		if g.opts.useDocComments {
+			g.applyTypeDoc(t, schema)
+		}
+		if g.opts.schemaCustomizer != nil {
+			if err := g.opts.schemaCustomizer(name, t, tag, schema); err != nil {
+				return nil, err
+			}
+		}
+		return openapi3.NewSchemaRef(t.Name(), schema), nil
+	}
+
 	switch t.Kind() {
 	case reflect.Func, reflect.Chan:
 		return nil, nil // ignore
@@ -349,15 +395,31 @@ func (g *Generator) generateWithoutSaving(parents []*theTypeInfo, t reflect.Type
 				return openapi3.NewSchemaRef("#/components/schemas/"+typeName, schema), nil
 			}
 
+			var composed map[int]struct{}
+			if g.opts.embeddedStructsAsAllOf {
+				var err error
+				if composed, err = g.generateAllOf(parents, t, schema); err != nil {
+					return nil, err
+				}
+			}
+
 			for _, fieldInfo := range typeInfo.Fields {
 				// Only fields with JSON tag are considered (by default)
 				if !fieldInfo.HasJSONTag && !g.opts.useAllExportedFields {
 					continue
 				}
 
+				// Fields of embedded structs are already part of allOf
+				if _, ok := composed[fieldInfo.Index[0]]; ok && len(fieldInfo.Index) > 1 {
+					continue
+				}
+
 				// If asked, try to use yaml tag
 				fieldName, fType := fieldInfo.JSONName, fieldInfo.Type
 				ff := getStructField(t, fieldInfo)
+				if g.opts.excludeParameterFields && parameterLocation(ff) != "" {
+					continue
+				}
 				if !fieldInfo.HasJSONTag && g.opts.useAllExportedFields {
 					if tag, ok := ff.Tag.Lookup("yaml"); ok && tag != "-" {
 						fieldName = tag
@@ -386,6 +448,9 @@ func (g *Generator) generateWithoutSaving(parents []*theTypeInfo, t reflect.Type
 						return nil, err
 					}
 				}
+				if ref != nil && g.opts.useDocComments {
+					ref = g.withFieldDoc(t, fieldInfo, ref)
+				}
 				if ref != nil {
 					g.SchemaRefs[ref]++
 					schema.WithPropertyRef(fieldName, ref)
@@ -399,6 +464,19 @@ func (g *Generator) generateWithoutSaving(parents []*theTypeInfo, t reflect.Type
 			}
 		}
 
+	case reflect.Interface:
+		if impls, ok := g.opts.implementations[t]; ok {
+			if err := g.generateOneOf(parents, t, impls, schema); err != nil {
+				return nil, err
+			}
+			break
+		}
+		if v := reflect.New(t); v.CanInterface() {
+			if v, ok := v.Interface().(SetSchemar); ok {
+				v.SetSchema(schema)
+			}
+		}
+
 	default:
 		// Object has their own schema's implementation, so we'll use those
 		if v := reflect.New(t); v.CanInterface() {
@@ -409,12 +487,23 @@ func (g *Generator) generateWithoutSaving(parents []*theTypeInfo, t reflect.Type
 
 	}
 
+	if g.opts.useDocComments {
+		g.applyTypeDoc(t, schema)
+	}
+
 	if g.opts.schemaCustomizer != nil {
 		if err := g.opts.schemaCustomizer(name, t, tag, schema); err != nil {
 			return nil, err
 		}
 	}
 
+	if _, ok := g.opts.implementations[t]; ok {
+		// Polymorphic schemas are components so implementations can refer back to them
+		if typeName := g.generateTypeName(t); typeName != "" {
+			return g.newComponentSchemaRef(typeName, schema), nil
+		}
+	}
+
 	if !g.opts.exportComponentSchemas.ExportComponentSchemas || t.Kind() != reflect.Struct {
 		return openapi3.NewSchemaRef(t.Name(), schema), nil
 	}
@@ -488,6 +577,11 @@ func (g *Generator) generateCycleSchemaRef(t reflect.Type, schema *openapi3.Sche
 		mapSchema.Type = &openapi3.Types{"object"}
 		mapSchema.AdditionalProperties = openapi3.AdditionalProperties{Schema: ref}
 		return openapi3.NewSchemaRef("", mapSchema)
+	case reflect.Interface:
+		if s, ok := g.interfaceSchemas[t]; ok {
+			return openapi3.NewSchemaRef("#/components/schemas/"+g.generateTypeName(t), s)
+		}
+		typeName = g.generateTypeName(t)
 	default:
 		typeName = g.generateTypeName(t)
 	}
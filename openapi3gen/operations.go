@@ -0,0 +1,329 @@
+package openapi3gen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// parameterLocations lists the struct tags binding request fields to parameters, in precedence order.
+var parameterLocations = []string{
+	openapi3.ParameterInPath,
+	openapi3.ParameterInQuery,
+	openapi3.ParameterInHeader,
+	openapi3.ParameterInCookie,
+}
+
+var (
+	contextType = reflect.TypeFor[context.Context]()
+	errorType   = reflect.TypeFor[error]()
+
+	pathParamRegexp = regexp.MustCompile(`{([^{}]+)}`)
+)
+
+// OperationBuilder builds the operations of a document from typed Go handlers,
+// registering the schemas of their request and response types as components.
+//
+// Handlers are functions taking an optional context.Context followed by an optional
+// request struct, and returning an optional response value followed by an optional error.
+// Request struct fields tagged `path:"id"`, `query:"limit"`, `header:"X-Req"` or `cookie:"session"`
+// describe parameters (appending ",required" to the tag marks non-path parameters as required),
+// and the other fields serialized to JSON describe the request body.
+// Response struct fields tagged `header:"X-Rate-Limit"` describe response headers,
+// and the other fields serialized to JSON describe the response body.
+type OperationBuilder struct {
+	doc       *openapi3.T
+	generator *Generator
+}
+
+// NewOperationBuilder returns a builder adding operations to doc.
+// Schemas are generated with CreateComponentSchemas (exporting top level schemas)
+// unless overridden by opts.
+func NewOperationBuilder(doc *openapi3.T, opts ...Option) *OperationBuilder {
+	opts = append([]Option{
+		CreateComponentSchemas(ExportComponentSchemasOptions{
+			ExportComponentSchemas: true,
+			ExportTopLevelSchema:   true,
+		}),
+	}, opts...)
+	g := NewGenerator(opts...)
+	g.opts.excludeParameterFields = true
+	return &OperationBuilder{
+		doc:       doc,
+		generator: g,
+	}
+}
+
+// AddOperation describes handler and adds the resulting operation to the document
+// under path and method. The returned operation can be further customized.
+// Operations of named handlers are identified by their name, qualified with the type
+// of their receiver for methods, and adding two operations with the same ID is an error.
+func (b *OperationBuilder) AddOperation(method, path string, handler any) (*openapi3.Operation, error) {
+	ht := reflect.TypeOf(handler)
+	if ht == nil || ht.Kind() != reflect.Func {
+		return nil, fmt.Errorf("handler for %s %s is not a function", method, path)
+	}
+	reqType, respType, err := handlerTypes(ht)
+	if err != nil {
+		return nil, fmt.Errorf("handler for %s %s: %w", method, path, err)
+	}
+
+	operation := openapi3.NewOperation()
+	operation.OperationID = handlerName(handler)
+	if err := b.checkOperationID(operation.OperationID); err != nil {
+		return nil, fmt.Errorf("handler for %s %s: %w", method, path, err)
+	}
+
+	if reqType != nil {
+		if err := b.describeRequest(operation, path, reqType); err != nil {
+			return nil, fmt.Errorf("request of %s %s: %w", method, path, err)
+		}
+	} else if params := pathParamRegexp.FindAllStringSubmatch(path, -1); len(params) != 0 {
+		return nil, fmt.Errorf("request of %s %s: no field bound to path parameter %q", method, path, params[0][1])
+	}
+
+	response, status, err := b.describeResponse(respType)
+	if err != nil {
+		return nil, fmt.Errorf("response of %s %s: %w", method, path, err)
+	}
+	operation.Responses = openapi3.NewResponses(openapi3.WithStatus(status, &openapi3.ResponseRef{Value: response}))
+
+	b.doc.AddOperation(path, strings.ToUpper(method), operation)
+	return operation, nil
+}
+
+// checkOperationID returns an error if an operation of the document already has the given ID.
+func (b *OperationBuilder) checkOperationID(id string) error {
+	if id == "" || b.doc.Paths == nil {
+		return nil
+	}
+	for path, pathItem := range b.doc.Paths.Map() {
+		for method, operation := range pathItem.Operations() {
+			if operation.OperationID == id {
+				return fmt.Errorf("operation ID %q is already used by %s %s", id, method, path)
+			}
+		}
+	}
+	return nil
+}
+
+// handlerTypes returns the request and response types of a handler function type.
+func handlerTypes(ht reflect.Type) (reqType, respType reflect.Type, err error) {
+	in := make([]reflect.Type, 0, ht.NumIn())
+	for i := range ht.NumIn() {
+		in = append(in, ht.In(i))
+	}
+	if len(in) != 0 && in[0] == contextType {
+		in = in[1:]
+	}
+	switch len(in) {
+	case 0:
+	case 1:
+		reqType = in[0]
+		for reqType.Kind() == reflect.Pointer {
+			reqType = reqType.Elem()
+		}
+		if reqType.Kind() != reflect.Struct {
+			return nil, nil, fmt.Errorf("request type %s is not a struct", in[0])
+		}
+	default:
+		return nil, nil, errors.New("expected at most a context and a request argument")
+	}
+
+	out := make([]reflect.Type, 0, ht.NumOut())
+	for i := range ht.NumOut() {
+		out = append(out, ht.Out(i))
+	}
+	if n := len(out); n != 0 && out[n-1] == errorType {
+		out = out[:n-1]
+	}
+	switch len(out) {
+	case 0:
+	case 1:
+		respType = out[0]
+	default:
+		return nil, nil, errors.New("expected at most a response and an error result")
+	}
+	return reqType, respType, nil
+}
+
+// handlerName returns the name of a named handler function, to be used as an operation ID.
+// Methods are qualified with the type of their receiver (e.g. "PetHandler.List").
+func handlerName(handler any) string {
+	fn := runtime.FuncForPC(reflect.ValueOf(handler).Pointer())
+	if fn == nil {
+		return ""
+	}
+	name := fn.Name()
+	// Drop the import path and the package name
+	name = name[strings.LastIndex(name, "/")+1:]
+	_, name, _ = strings.Cut(name, ".")
+	name = strings.TrimSuffix(name, "-fm")
+	if strings.HasPrefix(name[strings.LastIndex(name, ".")+1:], "func") {
+		// Anonymous function
+		return ""
+	}
+	name = strings.NewReplacer("(*", "", ")", "", "[...]", "").Replace(name)
+	return name
+}
+
+func (b *OperationBuilder) describeRequest(operation *openapi3.Operation, path string, reqType reflect.Type) error {
+	boundPathParams := make(map[string]struct{})
+	for _, fieldInfo := range getTypeInfo(reqType).Fields {
+		ff := getStructField(reqType, fieldInfo)
+		in := parameterLocation(ff)
+		if in == "" {
+			continue
+		}
+		name, opts, _ := strings.Cut(ff.Tag.Get(in), ",")
+		if name == "" {
+			name = ff.Name
+		}
+
+		var parameter *openapi3.Parameter
+		switch in {
+		case openapi3.ParameterInPath:
+			if !strings.Contains(path, "{"+name+"}") {
+				return fmt.Errorf("path parameter %q of field %s is not part of the path", name, ff.Name)
+			}
+			boundPathParams[name] = struct{}{}
+			parameter = openapi3.NewPathParameter(name)
+		case openapi3.ParameterInQuery:
+			parameter = openapi3.NewQueryParameter(name)
+		case openapi3.ParameterInHeader:
+			parameter = openapi3.NewHeaderParameter(name)
+		case openapi3.ParameterInCookie:
+			parameter = openapi3.NewCookieParameter(name)
+		}
+		if slices.Contains(strings.Split(opts, ","), "required") {
+			parameter.Required = true
+		}
+
+		schemaRef, err := b.schemaRefFor(ff.Type)
+		if err != nil {
+			return fmt.Errorf("parameter %q: %w", name, err)
+		}
+		parameter.Schema = schemaRef
+		operation.AddParameter(parameter)
+	}
+
+	for _, match := range pathParamRegexp.FindAllStringSubmatch(path, -1) {
+		if _, ok := boundPathParams[match[1]]; !ok {
+			return fmt.Errorf("no field bound to path parameter %q", match[1])
+		}
+	}
+
+	if !b.hasBody(reqType) {
+		return nil
+	}
+	schemaRef, err := b.schemaRefFor(reqType)
+	if err != nil {
+		return err
+	}
+	operation.RequestBody = &openapi3.RequestBodyRef{
+		Value: openapi3.NewRequestBody().WithRequired(true).WithJSONSchemaRef(schemaRef),
+	}
+	return nil
+}
+
+func (b *OperationBuilder) describeResponse(respType reflect.Type) (*openapi3.Response, int, error) {
+	response := openapi3.NewResponse()
+	if respType == nil {
+		return response.WithDescription(http.StatusText(http.StatusNoContent)), http.StatusNoContent, nil
+	}
+
+	t := respType
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Struct {
+		for _, fieldInfo := range getTypeInfo(t).Fields {
+			ff := getStructField(t, fieldInfo)
+			name, ok := ff.Tag.Lookup(openapi3.ParameterInHeader)
+			if !ok {
+				continue
+			}
+			name, _, _ = strings.Cut(name, ",")
+			if name == "" {
+				name = ff.Name
+			}
+			schemaRef, err := b.schemaRefFor(ff.Type)
+			if err != nil {
+				return nil, 0, fmt.Errorf("header %q: %w", name, err)
+			}
+			if response.Headers == nil {
+				response.Headers = make(openapi3.Headers)
+			}
+			response.Headers[name] = &openapi3.HeaderRef{Value: &openapi3.Header{
+				Parameter: openapi3.Parameter{Schema: schemaRef},
+			}}
+		}
+		if !b.hasBody(t) {
+			return response.WithDescription(http.StatusText(http.StatusNoContent)), http.StatusNoContent, nil
+		}
+	}
+
+	schemaRef, err := b.schemaRefFor(respType)
+	if err != nil {
+		return nil, 0, err
+	}
+	return response.WithDescription(http.StatusText(http.StatusOK)).WithJSONSchemaRef(schemaRef), http.StatusOK, nil
+}
+
+// hasBody reports whether any field of struct type t is serialized to JSON.
+func (b *OperationBuilder) hasBody(t reflect.Type) bool {
+	for _, fieldInfo := range getTypeInfo(t).Fields {
+		if !fieldInfo.HasJSONTag && !b.generator.opts.useAllExportedFields {
+			continue
+		}
+		if parameterLocation(getStructField(t, fieldInfo)) == "" {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *OperationBuilder) schemaRefFor(t reflect.Type) (*openapi3.SchemaRef, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if b.doc.Components == nil {
+		b.doc.Components = &openapi3.Components{}
+	}
+	if b.doc.Components.Schemas == nil {
+		b.doc.Components.Schemas = make(openapi3.Schemas)
+	}
+	schemas := b.doc.Components.Schemas
+	schemaRef, err := b.generator.NewSchemaRefForValue(reflect.New(t).Interface(), schemas)
+	if err != nil {
+		return nil, err
+	}
+
+	// Resolve references to components, as the loader would
+	for ref := range b.generator.SchemaRefs {
+		if name, ok := strings.CutPrefix(ref.Ref, "#/components/schemas/"); ok && ref.Value == nil {
+			if component := schemas[name]; component != nil {
+				ref.Value = component.Value
+			}
+		}
+	}
+	return schemaRef, nil
+}
+
+// parameterLocation returns where the struct field is bound as a parameter, if it is.
+func parameterLocation(field reflect.StructField) string {
+	for _, in := range parameterLocations {
+		if _, ok := field.Tag.Lookup(in); ok {
+			return in
+		}
+	}
+	return ""
+}
@@ -0,0 +1,275 @@
+package openapi3gen_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3gen"
+)
+
+type Pet struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type GetPetRequest struct {
+	ID      int64  `path:"id"`
+	Verbose bool   `query:"verbose"`
+	TraceID string `header:"X-Trace-Id,required"`
+}
+
+type CreatePetRequest struct {
+	Session string `cookie:"session"`
+	Name    string `json:"name"`
+}
+
+type ListPetsRequest struct {
+	Limit int `query:"limit"`
+}
+
+type ListPetsResponse struct {
+	Total int    `header:"X-Total-Count"`
+	Pets  []*Pet `json:"pets"`
+}
+
+func GetPet(ctx context.Context, req *GetPetRequest) (*Pet, error) { return nil, nil }
+
+func CreatePet(req CreatePetRequest) (Pet, error) { return Pet{}, nil }
+
+func ListPets(ctx context.Context, req ListPetsRequest) (*ListPetsResponse, error) { return nil, nil }
+
+func DeletePet(ctx context.Context, req *GetPetRequest) error { return nil }
+
+func ExampleOperationBuilder() {
+	doc := &openapi3.T{
+		OpenAPI: "3.0.3",
+		Info:    &openapi3.Info{Title: "Pets", Version: "1.0.0"},
+	}
+	builder := openapi3gen.NewOperationBuilder(doc)
+	if _, err := builder.AddOperation("GET", "/pets/{id}", GetPet); err != nil {
+		panic(err)
+	}
+	operation, err := builder.AddOperation("POST", "/pets", CreatePet)
+	if err != nil {
+		panic(err)
+	}
+	operation.Summary = "Create a pet"
+
+	if err := doc.Validate(context.Background()); err != nil {
+		panic(err)
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf("%s\n", data)
+	// Output:
+	// {
+	//   "components": {
+	//     "schemas": {
+	//       "CreatePetRequest": {
+	//         "properties": {
+	//           "name": {
+	//             "type": "string"
+	//           }
+	//         },
+	//         "type": "object"
+	//       },
+	//       "Pet": {
+	//         "properties": {
+	//           "id": {
+	//             "format": "int64",
+	//             "type": "integer"
+	//           },
+	//           "name": {
+	//             "type": "string"
+	//           }
+	//         },
+	//         "type": "object"
+	//       }
+	//     }
+	//   },
+	//   "info": {
+	//     "title": "Pets",
+	//     "version": "1.0.0"
+	//   },
+	//   "openapi": "3.0.3",
+	//   "paths": {
+	//     "/pets": {
+	//       "post": {
+	//         "operationId": "CreatePet",
+	//         "parameters": [
+	//           {
+	//             "in": "cookie",
+	//             "name": "session",
+	//             "schema": {
+	//               "type": "string"
+	//             }
+	//           }
+	//         ],
+	//         "requestBody": {
+	//           "content": {
+	//             "application/json": {
+	//               "schema": {
+	//                 "$ref": "#/components/schemas/CreatePetRequest"
+	//               }
+	//             }
+	//           },
+	//           "required": true
+	//         },
+	//         "responses": {
+	//           "200": {
+	//             "content": {
+	//               "application/json": {
+	//                 "schema": {
+	//                   "$ref": "#/components/schemas/Pet"
+	//                 }
+	//               }
+	//             },
+	//             "description": "OK"
+	//           }
+	//         },
+	//         "summary": "Create a pet"
+	//       }
+	//     },
+	//     "/pets/{id}": {
+	//       "get": {
+	//         "operationId": "GetPet",
+	//         "parameters": [
+	//           {
+	//             "in": "path",
+	//             "name": "id",
+	//             "required": true,
+	//             "schema": {
+	//               "format": "int64",
+	//               "type": "integer"
+	//             }
+	//           },
+	//           {
+	//             "in": "header",
+	//             "name": "X-Trace-Id",
+	//             "required": true,
+	//             "schema": {
+	//               "type": "string"
+	//             }
+	//           },
+	//           {
+	//             "in": "query",
+	//             "name": "verbose",
+	//             "schema": {
+	//               "type": "boolean"
+	//             }
+	//           }
+	//         ],
+	//         "responses": {
+	//           "200": {
+	//             "content": {
+	//               "application/json": {
+	//                 "schema": {
+	//                   "$ref": "#/components/schemas/Pet"
+	//                 }
+	//               }
+	//             },
+	//             "description": "OK"
+	//           }
+	//         }
+	//       }
+	//     }
+	//   }
+	// }
+}
+
+func TestOperationBuilder(t *testing.T) {
+	doc := &openapi3.T{
+		OpenAPI: "3.0.3",
+		Info:    &openapi3.Info{Title: "Pets", Version: "1.0.0"},
+	}
+	builder := openapi3gen.NewOperationBuilder(doc)
+
+	_, err := builder.AddOperation("GET", "/pets", ListPets)
+	require.NoError(t, err)
+	_, err = builder.AddOperation("delete", "/pets/{id}", DeletePet)
+	require.NoError(t, err)
+	_, err = builder.AddOperation("GET", "/health", func() {})
+	require.NoError(t, err)
+
+	require.NoError(t, doc.Validate(context.Background()))
+
+	list := doc.Paths.Find("/pets").Get
+	require.Equal(t, "ListPets", list.OperationID)
+	require.Nil(t, list.RequestBody)
+	response := list.Responses.Status(200).Value
+	require.Contains(t, response.Headers, "X-Total-Count")
+	require.Equal(t, "#/components/schemas/ListPetsResponse", response.Content.Get("application/json").Schema.Ref)
+	require.NotContains(t, doc.Components.Schemas["ListPetsResponse"].Value.Properties, "Total")
+
+	del := doc.Paths.Find("/pets/{id}").Delete
+	require.NotNil(t, del)
+	require.NotNil(t, del.Responses.Status(204))
+
+	health := doc.Paths.Find("/health").Get
+	require.Empty(t, health.OperationID)
+	require.NotNil(t, health.Responses.Status(204))
+}
+
+func TestOperationBuilderErrors(t *testing.T) {
+	doc := &openapi3.T{OpenAPI: "3.0.3"}
+	builder := openapi3gen.NewOperationBuilder(doc)
+
+	_, err := builder.AddOperation("GET", "/pets/{petId}", GetPet)
+	require.EqualError(t, err, `request of GET /pets/{petId}: path parameter "id" of field ID is not part of the path`)
+
+	_, err = builder.AddOperation("GET", "/pets/{id}/{other}", GetPet)
+	require.EqualError(t, err, `request of GET /pets/{id}/{other}: no field bound to path parameter "other"`)
+
+	_, err = builder.AddOperation("GET", "/pets/{id}", func() {})
+	require.EqualError(t, err, `request of GET /pets/{id}: no field bound to path parameter "id"`)
+
+	_, err = builder.AddOperation("GET", "/pets", "not a function")
+	require.EqualError(t, err, `handler for GET /pets is not a function`)
+
+	_, err = builder.AddOperation("GET", "/pets", func(int) {})
+	require.EqualError(t, err, `handler for GET /pets: request type int is not a struct`)
+
+	_, err = builder.AddOperation("GET", "/pets", func() (int, int) { return 0, 0 })
+	require.EqualError(t, err, `handler for GET /pets: expected at most a response and an error result`)
+}
+
+type PetHandler struct{}
+
+type ListRequest struct {
+	Tag string `query:"tag,omitempty,required"`
+}
+
+func (*PetHandler) List(ListRequest) ([]*Pet, error) { return nil, nil }
+
+type UserHandler struct{}
+
+func (UserHandler) List() error { return nil }
+
+func TestOperationBuilderMethods(t *testing.T) {
+	doc := &openapi3.T{
+		OpenAPI: "3.0.3",
+		Info:    &openapi3.Info{Title: "Pets", Version: "1.0.0"},
+	}
+	builder := openapi3gen.NewOperationBuilder(doc)
+
+	pets, err := builder.AddOperation("GET", "/pets", (&PetHandler{}).List)
+	require.NoError(t, err)
+	require.Equal(t, "PetHandler.List", pets.OperationID)
+	require.True(t, pets.Parameters.GetByInAndName("query", "tag").Required)
+
+	users, err := builder.AddOperation("GET", "/users", UserHandler{}.List)
+	require.NoError(t, err)
+	require.Equal(t, "UserHandler.List", users.OperationID)
+
+	require.NoError(t, doc.Validate(context.Background()))
+
+	_, err = builder.AddOperation("GET", "/v2/users", UserHandler{}.List)
+	require.EqualError(t, err, `handler for GET /v2/users: operation ID "UserHandler.List" is already used by GET /users`)
+}
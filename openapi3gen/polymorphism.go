@@ -0,0 +1,188 @@
+package openapi3gen
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// DefaultDiscriminatorPropertyName is the property used to discriminate
+// between the implementations of an interface, unless changed with
+// DiscriminatorPropertyName.
+const DefaultDiscriminatorPropertyName = "type"
+
+// DiscriminatorValuer can be implemented by the types registered with
+// RegisterImplementations to choose the value of the discriminator property
+// identifying them. By default the type name is used.
+type DiscriminatorValuer interface {
+	DiscriminatorValue() string
+}
+
+// RegisterImplementations registers the concrete types implementing interface I,
+// given as sample values (e.g. RegisterImplementations[Shape](Circle{}, Square{})).
+// Fields of type I then generate a oneOf schema over the component schemas
+// of the implementations, along with a discriminator mapping to them.
+// The schema of each implementation that has a field for the discriminator
+// property, or implements DiscriminatorValuer, requires the property with its
+// mapping value as only allowed value. Implementations are expected to marshal
+// the discriminator property themselves.
+// Types not implementing I make the generator return an error.
+func RegisterImplementations[I any](impls ...any) Option {
+	iface := reflect.TypeFor[I]()
+	return func(x *generatorOpt) {
+		if iface.Kind() != reflect.Interface {
+			x.err = errors.Join(x.err, fmt.Errorf("%s is not an interface type", iface))
+			return
+		}
+		for _, impl := range impls {
+			if t := reflect.TypeOf(impl); t == nil || !t.Implements(iface) {
+				x.err = errors.Join(x.err, fmt.Errorf("%T does not implement %s", impl, iface))
+				return
+			}
+		}
+		if x.implementations == nil {
+			x.implementations = make(map[reflect.Type][]any)
+		}
+		x.implementations[iface] = append(x.implementations[iface], impls...)
+	}
+}
+
+// DiscriminatorPropertyName changes the name of the property used
+// to discriminate between the implementations of an interface.
+func DiscriminatorPropertyName(name string) Option {
+	return func(x *generatorOpt) { x.discriminatorProperty = name }
+}
+
+// EmbeddedStructsAsAllOf changes the default behavior of flattening
+// the fields of embedded structs into the embedding struct's properties
+// to instead reference the embedded structs' component schemas through allOf.
+func EmbeddedStructsAsAllOf() Option {
+	return func(x *generatorOpt) { x.embeddedStructsAsAllOf = true }
+}
+
+func (g *Generator) generateOneOf(parents []*theTypeInfo, t reflect.Type, impls []any, schema *openapi3.Schema) error {
+	g.interfaceSchemas[t] = schema
+	defer delete(g.interfaceSchemas, t)
+
+	propertyName := g.opts.discriminatorProperty
+	if propertyName == "" {
+		propertyName = DefaultDiscriminatorPropertyName
+	}
+	discriminator := &openapi3.Discriminator{
+		PropertyName: propertyName,
+		Mapping:      make(map[string]openapi3.MappingRef, len(impls)),
+	}
+
+	for _, impl := range impls {
+		implType := reflect.TypeOf(impl)
+		ref, err := g.generateComponentSchemaRef(parents, implType)
+		if err != nil {
+			return err
+		}
+		if ref == nil {
+			continue
+		}
+
+		value := g.generateTypeName(implType)
+		if v, ok := impl.(DiscriminatorValuer); ok {
+			value = v.DiscriminatorValue()
+		}
+		if _, ok := discriminator.Mapping[value]; ok {
+			return fmt.Errorf("duplicate discriminator value %q for %s", value, t)
+		}
+		discriminator.Mapping[value] = openapi3.MappingRef{Ref: ref.Ref}
+		if ref.Value != nil {
+			// Only implementations that marshal the property get it required
+			_, valuer := impl.(DiscriminatorValuer)
+			if _, ok := ref.Value.Properties[propertyName]; ok || valuer {
+				requireDiscriminator(ref.Value, propertyName, value)
+			}
+		}
+
+		schema.OneOf = append(schema.OneOf, ref)
+	}
+
+	if len(schema.OneOf) != 0 {
+		schema.Discriminator = discriminator
+	}
+	return nil
+}
+
+// requireDiscriminator declares the discriminator property of an implementation
+// schema, only allowing its mapping value, and lists it as required.
+func requireDiscriminator(schema *openapi3.Schema, propertyName, value string) {
+	property := openapi3.NewStringSchema().WithEnum(value)
+	if existing := schema.Properties[propertyName]; existing != nil && existing.Value != nil {
+		// The schema of the field is shared with other fields of its type
+		restricted := *existing.Value
+		restricted.Enum = []any{value}
+		property = &restricted
+	}
+	schema.WithPropertyRef(propertyName, openapi3.NewSchemaRef("", property))
+	if !slices.Contains(schema.Required, propertyName) {
+		schema.Required = append(schema.Required, propertyName)
+	}
+	schema.Type = &openapi3.Types{"object"}
+}
+
+func (g *Generator) generateAllOf(parents []*theTypeInfo, t reflect.Type, schema *openapi3.Schema) (map[int]struct{}, error) {
+	composed := make(map[int]struct{})
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.Anonymous || f.Tag.Get("json") != "" || !f.IsExported() {
+			continue
+		}
+		ft := f.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if ft.Kind() != reflect.Struct || ft == timeType {
+			continue
+		}
+
+		ref, err := g.generateComponentSchemaRef(parents, ft)
+		if err != nil {
+			return nil, err
+		}
+		composed[i] = struct{}{}
+		if ref != nil {
+			schema.AllOf = append(schema.AllOf, ref)
+		}
+	}
+	return composed, nil
+}
+
+// generateComponentSchemaRef generates the schema for t and returns a reference
+// to it as a component schema, later exported by NewSchemaRefForValue.
+func (g *Generator) generateComponentSchemaRef(parents []*theTypeInfo, t reflect.Type) (*openapi3.SchemaRef, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	typeName := g.generateTypeName(t)
+
+	ref, err := g.generateSchemaRefFor(parents, t, typeName, "")
+	if err != nil {
+		if _, ok := err.(*CycleError); ok && !g.opts.throwErrorOnCycle {
+			return g.generateCycleSchemaRef(t, &openapi3.Schema{}), nil
+		}
+		return nil, err
+	}
+	if ref == nil || strings.HasPrefix(ref.Ref, "#/components/schemas/") {
+		return ref, nil
+	}
+	if typeName == "" {
+		return nil, fmt.Errorf("cannot reference anonymous type %s as a component schema", t)
+	}
+	return g.newComponentSchemaRef(typeName, ref.Value), nil
+}
+
+func (g *Generator) newComponentSchemaRef(typeName string, schema *openapi3.Schema) *openapi3.SchemaRef {
+	ref := openapi3.NewSchemaRef("#/components/schemas/"+typeName, schema)
+	g.exportedSchemaRefs[ref] = typeName
+	g.SchemaRefs[ref]++
+	return ref
+}
@@ -0,0 +1,222 @@
+package openapi3gen_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3gen"
+)
+
+type Shape interface {
+	Area() float64
+}
+
+type Circle struct {
+	Kind   string  `json:"kind"`
+	Radius float64 `json:"radius"`
+}
+
+func (Circle) Area() float64 { return 0 }
+
+type Square struct {
+	Kind string  `json:"kind"`
+	Side float64 `json:"side"`
+}
+
+func (Square) Area() float64              { return 0 }
+func (Square) DiscriminatorValue() string { return "square" }
+
+type Group struct {
+	Kind   string  `json:"kind"`
+	Shapes []Shape `json:"shapes"`
+}
+
+func (Group) Area() float64 { return 0 }
+
+func ExampleRegisterImplementations() {
+	type Drawing struct {
+		Main Shape `json:"main"`
+	}
+
+	schemas := make(openapi3.Schemas)
+	schemaRef, err := openapi3gen.NewSchemaRefForValue(&Drawing{}, schemas,
+		openapi3gen.RegisterImplementations[Shape](Circle{}, Square{}),
+		openapi3gen.DiscriminatorPropertyName("kind"),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	var data []byte
+	if data, err = json.MarshalIndent(&schemas, "", "  "); err != nil {
+		panic(err)
+	}
+	fmt.Printf("schemas: %s\n", data)
+	if data, err = json.MarshalIndent(&schemaRef, "", "  "); err != nil {
+		panic(err)
+	}
+	fmt.Printf("schemaRef: %s\n", data)
+	// Output:
+	// schemas: {
+	//   "Circle": {
+	//     "properties": {
+	//       "kind": {
+	//         "enum": [
+	//           "Circle"
+	//         ],
+	//         "type": "string"
+	//       },
+	//       "radius": {
+	//         "format": "double",
+	//         "type": "number"
+	//       }
+	//     },
+	//     "required": [
+	//       "kind"
+	//     ],
+	//     "type": "object"
+	//   },
+	//   "Shape": {
+	//     "discriminator": {
+	//       "mapping": {
+	//         "Circle": "#/components/schemas/Circle",
+	//         "square": "#/components/schemas/Square"
+	//       },
+	//       "propertyName": "kind"
+	//     },
+	//     "oneOf": [
+	//       {
+	//         "$ref": "#/components/schemas/Circle"
+	//       },
+	//       {
+	//         "$ref": "#/components/schemas/Square"
+	//       }
+	//     ]
+	//   },
+	//   "Square": {
+	//     "properties": {
+	//       "kind": {
+	//         "enum": [
+	//           "square"
+	//         ],
+	//         "type": "string"
+	//       },
+	//       "side": {
+	//         "format": "double",
+	//         "type": "number"
+	//       }
+	//     },
+	//     "required": [
+	//       "kind"
+	//     ],
+	//     "type": "object"
+	//   }
+	// }
+	// schemaRef: {
+	//   "properties": {
+	//     "main": {
+	//       "$ref": "#/components/schemas/Shape"
+	//     }
+	//   },
+	//   "type": "object"
+	// }
+}
+
+func TestRegisterImplementationsRecursive(t *testing.T) {
+	type Drawing struct {
+		Main Shape `json:"main"`
+	}
+
+	schemas := make(openapi3.Schemas)
+	schemaRef, err := openapi3gen.NewSchemaRefForValue(&Drawing{}, schemas,
+		openapi3gen.RegisterImplementations[Shape](Circle{}, Group{}),
+	)
+	require.NoError(t, err)
+	require.Equal(t, "#/components/schemas/Shape", schemaRef.Value.Properties["main"].Ref)
+
+	require.Contains(t, schemas, "Shape")
+	require.Contains(t, schemas, "Circle")
+	require.Contains(t, schemas, "Group")
+
+	shape := schemas["Shape"].Value
+	require.Equal(t, "type", shape.Discriminator.PropertyName)
+	require.Len(t, shape.OneOf, 2)
+	require.Equal(t, "#/components/schemas/Group", shape.Discriminator.Mapping["Group"].Ref)
+
+	items := schemas["Group"].Value.Properties["shapes"].Value.Items
+	require.Equal(t, "#/components/schemas/Shape", items.Ref)
+
+	// Implementations that do not marshal the discriminator are left alone
+	group := schemas["Group"].Value
+	require.Empty(t, group.Required)
+	require.NotContains(t, group.Properties, "type")
+	require.Nil(t, group.Properties["kind"].Value.Enum)
+
+	doc := &openapi3.T{
+		OpenAPI:    "3.0.3",
+		Info:       &openapi3.Info{Title: "Shapes", Version: "1"},
+		Paths:      openapi3.NewPaths(),
+		Components: &openapi3.Components{Schemas: schemas},
+	}
+	data, err := json.Marshal(doc)
+	require.NoError(t, err)
+	loader := openapi3.NewLoader()
+	doc, err = loader.LoadFromData(data)
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(loader.Context))
+
+	// Marshaled implementations are valid against their schemas
+	for name, value := range map[string]any{"Circle": Circle{Kind: "circle", Radius: 1}, "Group": Group{Kind: "group", Shapes: []Shape{}}} {
+		data, err := json.Marshal(value)
+		require.NoError(t, err)
+		var decoded any
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		require.NoError(t, doc.Components.Schemas[name].Value.VisitJSON(decoded), name)
+	}
+}
+
+func TestRegisterImplementationsNotImplementing(t *testing.T) {
+	_, err := openapi3gen.NewSchemaRefForValue(&Circle{}, nil, openapi3gen.RegisterImplementations[Shape](struct{}{}))
+	require.EqualError(t, err, "struct {} does not implement openapi3gen_test.Shape")
+
+	_, err = openapi3gen.NewSchemaRefForValue(&Circle{}, nil, openapi3gen.RegisterImplementations[Circle](Circle{}))
+	require.EqualError(t, err, "openapi3gen_test.Circle is not an interface type")
+}
+
+func TestEmbeddedStructsAsAllOf(t *testing.T) {
+	type Base struct {
+		ID string `json:"id"`
+	}
+	type Audit struct {
+		CreatedBy string `json:"createdBy"`
+	}
+	type Pet struct {
+		Base
+		*Audit
+		Name string `json:"name"`
+	}
+
+	schemas := make(openapi3.Schemas)
+	schemaRef, err := openapi3gen.NewSchemaRefForValue(&Pet{}, schemas, openapi3gen.EmbeddedStructsAsAllOf())
+	require.NoError(t, err)
+
+	schema := schemaRef.Value
+	require.Len(t, schema.AllOf, 2)
+	require.Equal(t, "#/components/schemas/Base", schema.AllOf[0].Ref)
+	require.Equal(t, "#/components/schemas/Audit", schema.AllOf[1].Ref)
+	require.Len(t, schema.Properties, 1)
+	require.Contains(t, schema.Properties, "name")
+
+	require.Contains(t, schemas["Base"].Value.Properties, "id")
+	require.Contains(t, schemas["Audit"].Value.Properties, "createdBy")
+
+	// Default behavior is still to flatten
+	schemaRef, err = openapi3gen.NewSchemaRefForValue(&Pet{}, nil)
+	require.NoError(t, err)
+	require.Empty(t, schemaRef.Value.AllOf)
+	require.Len(t, schemaRef.Value.Properties, 3)
+}
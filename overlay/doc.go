@@ -0,0 +1,23 @@
+// Package overlay implements the OpenAPI Overlay Specification 1.0, which
+// describes changes to apply to an OpenAPI document, such as replacing its
+// servers for an environment, removing internal operations or adding
+// extensions, as a list of actions on the nodes that JSONPath (RFC 9535)
+// queries select.
+//
+// An Overlay applies to a loaded document with Apply, or while the document is
+// loaded through openapi3.Loader.Overlays, which keeps the origins of the
+// nodes the overlay leaves in place:
+//
+//	o, err := overlay.ParseFile("production.overlay.yaml")
+//	if err != nil {
+//		return err
+//	}
+//	if err := o.Validate(); err != nil {
+//		return err
+//	}
+//	loader := openapi3.NewLoader()
+//	loader.Overlays = []openapi3.DocumentOverlay{o}
+//	doc, err := loader.LoadFromFile("openapi.yaml")
+//
+// See https://spec.openapis.org/overlay/v1.0.0.html
+package overlay
@@ -0,0 +1,923 @@
+package overlay
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// originKey is the key under which the Loader keeps the source location of a
+// node. It is not part of the document and queries never select it.
+const originKey = "__origin__"
+
+// jsonPath is a parsed RFC 9535 JSONPath query.
+type jsonPath struct {
+	segments []jsonPathSegment
+}
+
+type jsonPathSegment struct {
+	descendant bool
+	selectors  []jsonPathSelector
+}
+
+type jsonPathSelector interface {
+	selectFrom(n jsonNode, root any, out []jsonNode) []jsonNode
+}
+
+// jsonNode is a value of the document along with its normalized path, made of
+// member names (string) and array indices (int).
+type jsonNode struct {
+	path  []any
+	value any
+}
+
+func (n jsonNode) child(key any, value any) jsonNode {
+	return jsonNode{path: append(slices.Clip(n.path), key), value: value}
+}
+
+// query returns the nodes of root that p selects, in document order.
+func (p *jsonPath) query(root any) []jsonNode {
+	return p.queryFrom(jsonNode{value: root}, root)
+}
+
+func (p *jsonPath) queryFrom(start jsonNode, root any) []jsonNode {
+	nodes := []jsonNode{start}
+	for _, segment := range p.segments {
+		var out []jsonNode
+		for _, n := range nodes {
+			if !segment.descendant {
+				for _, s := range segment.selectors {
+					out = s.selectFrom(n, root, out)
+				}
+				continue
+			}
+			for _, d := range descendants(n, nil) {
+				for _, s := range segment.selectors {
+					out = s.selectFrom(d, root, out)
+				}
+			}
+		}
+		nodes = out
+	}
+	return nodes
+}
+
+// singular reports whether p selects at most one node.
+func (p *jsonPath) singular() bool {
+	for _, segment := range p.segments {
+		if segment.descendant || len(segment.selectors) != 1 {
+			return false
+		}
+		switch segment.selectors[0].(type) {
+		case nameSelector, indexSelector:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// descendants appends n and all the nodes below it, in document order.
+func descendants(n jsonNode, out []jsonNode) []jsonNode {
+	out = append(out, n)
+	for _, c := range children(n) {
+		out = descendants(c, out)
+	}
+	return out
+}
+
+// children returns the member values of an object, sorted by name, or the
+// elements of an array.
+func children(n jsonNode) []jsonNode {
+	switch v := n.value.(type) {
+	case map[string]any:
+		out := make([]jsonNode, 0, len(v))
+		for _, k := range memberNames(v) {
+			out = append(out, n.child(k, v[k]))
+		}
+		return out
+	case []any:
+		out := make([]jsonNode, 0, len(v))
+		for i, e := range v {
+			out = append(out, n.child(i, e))
+		}
+		return out
+	}
+	return nil
+}
+
+func memberNames(m map[string]any) []string {
+	names := make([]string, 0, len(m))
+	for k := range m {
+		if k != originKey {
+			names = append(names, k)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+type nameSelector string
+
+func (s nameSelector) selectFrom(n jsonNode, _ any, out []jsonNode) []jsonNode {
+	if m, ok := n.value.(map[string]any); ok && s != originKey {
+		if v, ok := m[string(s)]; ok {
+			out = append(out, n.child(string(s), v))
+		}
+	}
+	return out
+}
+
+type wildcardSelector struct{}
+
+func (wildcardSelector) selectFrom(n jsonNode, _ any, out []jsonNode) []jsonNode {
+	return append(out, children(n)...)
+}
+
+type indexSelector int
+
+func (s indexSelector) selectFrom(n jsonNode, _ any, out []jsonNode) []jsonNode {
+	if a, ok := n.value.([]any); ok {
+		i := int(s)
+		if i < 0 {
+			i += len(a)
+		}
+		if i >= 0 && i < len(a) {
+			out = append(out, n.child(i, a[i]))
+		}
+	}
+	return out
+}
+
+type sliceSelector struct {
+	start, end *int
+	step       int
+}
+
+func (s sliceSelector) selectFrom(n jsonNode, _ any, out []jsonNode) []jsonNode {
+	a, ok := n.value.([]any)
+	if !ok || s.step == 0 {
+		return out
+	}
+	length := len(a)
+	normalize := func(i int) int {
+		if i < 0 {
+			return i + length
+		}
+		return i
+	}
+	if s.step > 0 {
+		lower, upper := 0, length
+		if s.start != nil {
+			lower = min(max(normalize(*s.start), 0), length)
+		}
+		if s.end != nil {
+			upper = min(max(normalize(*s.end), 0), length)
+		}
+		for i := lower; i < upper; i += s.step {
+			out = append(out, n.child(i, a[i]))
+		}
+		return out
+	}
+	upper, lower := length-1, -1
+	if s.start != nil {
+		upper = min(max(normalize(*s.start), -1), length-1)
+	}
+	if s.end != nil {
+		lower = min(max(normalize(*s.end), -1), length-1)
+	}
+	for i := upper; i > lower; i += s.step {
+		out = append(out, n.child(i, a[i]))
+	}
+	return out
+}
+
+type filterSelector struct {
+	expr filterExpr
+}
+
+func (s filterSelector) selectFrom(n jsonNode, root any, out []jsonNode) []jsonNode {
+	for _, c := range children(n) {
+		if s.expr.test(c, root) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// filterExpr is a logical expression of a filter selector.
+type filterExpr interface {
+	test(current jsonNode, root any) bool
+}
+
+// filterValue is an operand of a comparison. Its value is absent, which the
+// RFC calls Nothing, when ok is false.
+type filterValue interface {
+	value(current jsonNode, root any) (v any, ok bool)
+}
+
+type orExpr []filterExpr
+
+func (e orExpr) test(current jsonNode, root any) bool {
+	for _, x := range e {
+		if x.test(current, root) {
+			return true
+		}
+	}
+	return false
+}
+
+type andExpr []filterExpr
+
+func (e andExpr) test(current jsonNode, root any) bool {
+	for _, x := range e {
+		if !x.test(current, root) {
+			return false
+		}
+	}
+	return true
+}
+
+type notExpr struct{ expr filterExpr }
+
+func (e notExpr) test(current jsonNode, root any) bool {
+	return !e.expr.test(current, root)
+}
+
+// filterQuery is a query embedded in a filter, relative to the current node
+// when it starts with "@" and to the root when it starts with "$".
+type filterQuery struct {
+	relative bool
+	path     *jsonPath
+}
+
+func (q filterQuery) nodes(current jsonNode, root any) []jsonNode {
+	if q.relative {
+		return q.path.queryFrom(current, root)
+	}
+	return q.path.query(root)
+}
+
+// test is the existence test of a query.
+func (q filterQuery) test(current jsonNode, root any) bool {
+	return len(q.nodes(current, root)) != 0
+}
+
+func (q filterQuery) value(current jsonNode, root any) (any, bool) {
+	nodes := q.nodes(current, root)
+	if len(nodes) != 1 {
+		return nil, false
+	}
+	return nodes[0].value, true
+}
+
+type literal struct{ v any }
+
+func (l literal) value(jsonNode, any) (any, bool) {
+	return l.v, true
+}
+
+type comparison struct {
+	op          string
+	left, right filterValue
+}
+
+func (c comparison) test(current jsonNode, root any) bool {
+	l, lok := c.left.value(current, root)
+	r, rok := c.right.value(current, root)
+	switch c.op {
+	case "==":
+		return compareEqual(l, lok, r, rok)
+	case "!=":
+		return !compareEqual(l, lok, r, rok)
+	case "<":
+		return lok && rok && compareLess(l, r)
+	case "<=":
+		return lok && rok && (compareLess(l, r) || compareEqual(l, lok, r, rok))
+	case ">":
+		return lok && rok && compareLess(r, l)
+	case ">=":
+		return lok && rok && (compareLess(r, l) || compareEqual(l, lok, r, rok))
+	}
+	return false
+}
+
+func compareEqual(l any, lok bool, r any, rok bool) bool {
+	if !lok || !rok {
+		return lok == rok
+	}
+	return equalValues(l, r)
+}
+
+func compareLess(l, r any) bool {
+	if lf, ok := toFloat(l); ok {
+		rf, ok := toFloat(r)
+		return ok && lf < rf
+	}
+	if ls, ok := l.(string); ok {
+		rs, ok := r.(string)
+		return ok && ls < rs
+	}
+	return false
+}
+
+// equalValues reports whether two values are deeply equal, comparing numbers
+// by value whatever their Go type.
+func equalValues(l, r any) bool {
+	if lf, ok := toFloat(l); ok {
+		rf, ok := toFloat(r)
+		return ok && lf == rf
+	}
+	switch l := l.(type) {
+	case nil:
+		return r == nil
+	case string:
+		r, ok := r.(string)
+		return ok && l == r
+	case bool:
+		r, ok := r.(bool)
+		return ok && l == r
+	case []any:
+		r, ok := r.([]any)
+		return ok && slices.EqualFunc(l, r, equalValues)
+	case map[string]any:
+		r, ok := r.(map[string]any)
+		if !ok || !slices.Equal(memberNames(l), memberNames(r)) {
+			return false
+		}
+		for _, k := range memberNames(l) {
+			if !equalValues(l[k], r[k]) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func toFloat(v any) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// functionExpr is a call to one of the function extensions of RFC 9535.
+type functionExpr struct {
+	name string
+	args []any // filterQuery, filterValue or filterExpr
+	re   *regexp.Regexp
+}
+
+func (f functionExpr) test(current jsonNode, root any) bool {
+	switch f.name {
+	case "match", "search":
+		v, ok := f.argValue(0, current, root)
+		s, isString := v.(string)
+		if !ok || !isString || f.re == nil {
+			return false
+		}
+		return f.re.MatchString(s)
+	}
+	v, ok := f.value(current, root)
+	return ok && v != false
+}
+
+func (f functionExpr) value(current jsonNode, root any) (any, bool) {
+	switch f.name {
+	case "length":
+		v, ok := f.argValue(0, current, root)
+		if !ok {
+			return nil, false
+		}
+		switch v := v.(type) {
+		case string:
+			return utf8.RuneCountInString(v), true
+		case []any:
+			return len(v), true
+		case map[string]any:
+			return len(memberNames(v)), true
+		}
+		return nil, false
+	case "count":
+		if q, ok := f.args[0].(filterQuery); ok {
+			return len(q.nodes(current, root)), true
+		}
+		return nil, false
+	case "value":
+		if q, ok := f.args[0].(filterQuery); ok {
+			return q.value(current, root)
+		}
+		return nil, false
+	case "match", "search":
+		return f.test(current, root), true
+	}
+	return nil, false
+}
+
+func (f functionExpr) argValue(i int, current jsonNode, root any) (any, bool) {
+	if v, ok := f.args[i].(filterValue); ok {
+		return v.value(current, root)
+	}
+	return nil, false
+}
+
+// parseJSONPath parses an RFC 9535 JSONPath query. Member name shorthands
+// also accept "-", so that extensions read as in "$.info.x-logo".
+func parseJSONPath(s string) (*jsonPath, error) {
+	p := jsonPathParser{s: s}
+	if !p.consume("$") {
+		return nil, p.errorf("query must start with %q", "$")
+	}
+	path, err := p.segments(false)
+	if err != nil {
+		return nil, err
+	}
+	if p.i != len(p.s) {
+		return nil, p.errorf("unexpected %q", p.s[p.i:])
+	}
+	return path, nil
+}
+
+type jsonPathParser struct {
+	s string
+	i int
+}
+
+func (p *jsonPathParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("invalid JSONPath %q at offset %d: %s", p.s, p.i, fmt.Sprintf(format, args...))
+}
+
+func (p *jsonPathParser) peek(prefix string) bool {
+	return strings.HasPrefix(p.s[p.i:], prefix)
+}
+
+func (p *jsonPathParser) consume(prefix string) bool {
+	if p.peek(prefix) {
+		p.i += len(prefix)
+		return true
+	}
+	return false
+}
+
+func (p *jsonPathParser) skipSpaces() {
+	for p.i < len(p.s) && strings.IndexByte(" \t\n\r", p.s[p.i]) >= 0 {
+		p.i++
+	}
+}
+
+// segments parses the segments following "$" or "@". Within a filter, blank
+// space may precede a segment.
+func (p *jsonPathParser) segments(inFilter bool) (*jsonPath, error) {
+	path := &jsonPath{}
+	for {
+		start := p.i
+		if inFilter {
+			p.skipSpaces()
+		}
+		var segment jsonPathSegment
+		switch {
+		case p.consume(".."):
+			segment.descendant = true
+			switch {
+			case p.consume("*"):
+				segment.selectors = []jsonPathSelector{wildcardSelector{}}
+			case p.peek("["):
+				selectors, err := p.bracket()
+				if err != nil {
+					return nil, err
+				}
+				segment.selectors = selectors
+			default:
+				name, err := p.memberName()
+				if err != nil {
+					return nil, err
+				}
+				segment.selectors = []jsonPathSelector{nameSelector(name)}
+			}
+		case p.consume("."):
+			if p.consume("*") {
+				segment.selectors = []jsonPathSelector{wildcardSelector{}}
+				break
+			}
+			name, err := p.memberName()
+			if err != nil {
+				return nil, err
+			}
+			segment.selectors = []jsonPathSelector{nameSelector(name)}
+		case p.peek("["):
+			selectors, err := p.bracket()
+			if err != nil {
+				return nil, err
+			}
+			segment.selectors = selectors
+		default:
+			p.i = start
+			return path, nil
+		}
+		path.segments = append(path.segments, segment)
+	}
+}
+
+func isNameFirst(r rune) bool {
+	return r == '_' || r >= 0x80 || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z'
+}
+
+func (p *jsonPathParser) memberName() (string, error) {
+	start := p.i
+	for p.i < len(p.s) {
+		r, size := utf8.DecodeRuneInString(p.s[p.i:])
+		if !isNameFirst(r) && !(p.i > start && (r == '-' || '0' <= r && r <= '9')) {
+			break
+		}
+		p.i += size
+	}
+	if p.i == start {
+		return "", p.errorf("expected a member name")
+	}
+	return p.s[start:p.i], nil
+}
+
+// bracket parses a bracketed list of selectors.
+func (p *jsonPathParser) bracket() ([]jsonPathSelector, error) {
+	p.consume("[")
+	var selectors []jsonPathSelector
+	for {
+		p.skipSpaces()
+		selector, err := p.selector()
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, selector)
+		p.skipSpaces()
+		if p.consume("]") {
+			return selectors, nil
+		}
+		if !p.consume(",") {
+			return nil, p.errorf("expected %q or %q", ",", "]")
+		}
+	}
+}
+
+func (p *jsonPathParser) selector() (jsonPathSelector, error) {
+	switch {
+	case p.peek("'") || p.peek(`"`):
+		s, err := p.stringLiteral()
+		if err != nil {
+			return nil, err
+		}
+		return nameSelector(s), nil
+	case p.consume("*"):
+		return wildcardSelector{}, nil
+	case p.consume("?"):
+		p.skipSpaces()
+		expr, err := p.logicalOr()
+		if err != nil {
+			return nil, err
+		}
+		return filterSelector{expr: expr}, nil
+	}
+
+	var bounds [3]*int
+	var colons int
+	for {
+		p.skipSpaces()
+		if i, ok, err := p.integer(); err != nil {
+			return nil, err
+		} else if ok {
+			bounds[colons] = &i
+		}
+		p.skipSpaces()
+		if colons == 2 || !p.consume(":") {
+			break
+		}
+		colons++
+	}
+	if colons == 0 {
+		if bounds[0] == nil {
+			return nil, p.errorf("expected a selector")
+		}
+		return indexSelector(*bounds[0]), nil
+	}
+	s := sliceSelector{start: bounds[0], end: bounds[1], step: 1}
+	if bounds[2] != nil {
+		s.step = *bounds[2]
+	}
+	return s, nil
+}
+
+func (p *jsonPathParser) integer() (int, bool, error) {
+	start := p.i
+	p.consume("-")
+	for p.i < len(p.s) && '0' <= p.s[p.i] && p.s[p.i] <= '9' {
+		p.i++
+	}
+	if p.i == start {
+		return 0, false, nil
+	}
+	i, err := strconv.Atoi(p.s[start:p.i])
+	if err != nil {
+		return 0, false, p.errorf("invalid integer %q", p.s[start:p.i])
+	}
+	return i, true, nil
+}
+
+func (p *jsonPathParser) stringLiteral() (string, error) {
+	quote := p.s[p.i]
+	p.i++
+	var b strings.Builder
+	for p.i < len(p.s) {
+		c := p.s[p.i]
+		switch {
+		case c == quote:
+			p.i++
+			return b.String(), nil
+		case c == '\\' && p.i+1 < len(p.s):
+			p.i++
+			switch e := p.s[p.i]; e {
+			case 'b':
+				b.WriteByte('\b')
+			case 'f':
+				b.WriteByte('\f')
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case 'u':
+				if p.i+4 >= len(p.s) {
+					return "", p.errorf("invalid unicode escape")
+				}
+				r, err := strconv.ParseUint(p.s[p.i+1:p.i+5], 16, 32)
+				if err != nil {
+					return "", p.errorf("invalid unicode escape")
+				}
+				b.WriteRune(rune(r))
+				p.i += 4
+			default:
+				b.WriteByte(e)
+			}
+			p.i++
+		default:
+			b.WriteByte(c)
+			p.i++
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+func (p *jsonPathParser) logicalOr() (filterExpr, error) {
+	var or orExpr
+	for {
+		and, err := p.logicalAnd()
+		if err != nil {
+			return nil, err
+		}
+		or = append(or, and)
+		p.skipSpaces()
+		if !p.consume("||") {
+			break
+		}
+		p.skipSpaces()
+	}
+	if len(or) == 1 {
+		return or[0], nil
+	}
+	return or, nil
+}
+
+func (p *jsonPathParser) logicalAnd() (filterExpr, error) {
+	var and andExpr
+	for {
+		basic, err := p.basic()
+		if err != nil {
+			return nil, err
+		}
+		and = append(and, basic)
+		p.skipSpaces()
+		if !p.consume("&&") {
+			break
+		}
+		p.skipSpaces()
+	}
+	if len(and) == 1 {
+		return and[0], nil
+	}
+	return and, nil
+}
+
+func (p *jsonPathParser) basic() (filterExpr, error) {
+	if p.consume("!") {
+		p.skipSpaces()
+		if p.consume("(") {
+			expr, err := p.parenthesized()
+			if err != nil {
+				return nil, err
+			}
+			return notExpr{expr}, nil
+		}
+		operand, err := p.operand()
+		if err != nil {
+			return nil, err
+		}
+		test, ok := operand.(filterExpr)
+		if !ok {
+			return nil, p.errorf("expected a test expression")
+		}
+		return notExpr{test}, nil
+	}
+	if p.consume("(") {
+		return p.parenthesized()
+	}
+
+	left, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if !p.consume(op) {
+			continue
+		}
+		p.skipSpaces()
+		right, err := p.operand()
+		if err != nil {
+			return nil, err
+		}
+		l, lok := p.comparable(left)
+		r, rok := p.comparable(right)
+		if !lok || !rok {
+			return nil, p.errorf("operands of %q must be literals, singular queries or value functions", op)
+		}
+		return comparison{op: op, left: l, right: r}, nil
+	}
+	switch left := left.(type) {
+	case filterQuery:
+		return left, nil
+	case functionExpr:
+		if left.name == "match" || left.name == "search" {
+			return left, nil
+		}
+	}
+	return nil, p.errorf("expected a test expression or a comparison")
+}
+
+func (p *jsonPathParser) parenthesized() (filterExpr, error) {
+	p.skipSpaces()
+	expr, err := p.logicalOr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if !p.consume(")") {
+		return nil, p.errorf("expected %q", ")")
+	}
+	return expr, nil
+}
+
+// comparable returns operand as an operand of a comparison.
+func (p *jsonPathParser) comparable(operand any) (filterValue, bool) {
+	switch operand := operand.(type) {
+	case literal:
+		return operand, true
+	case filterQuery:
+		return operand, operand.path.singular()
+	case functionExpr:
+		return operand, operand.name != "match" && operand.name != "search"
+	}
+	return nil, false
+}
+
+// operand parses a literal, a query or a function call.
+func (p *jsonPathParser) operand() (any, error) {
+	switch {
+	case p.peek("'") || p.peek(`"`):
+		s, err := p.stringLiteral()
+		if err != nil {
+			return nil, err
+		}
+		return literal{s}, nil
+	case p.consume("@"):
+		path, err := p.segments(true)
+		if err != nil {
+			return nil, err
+		}
+		return filterQuery{relative: true, path: path}, nil
+	case p.consume("$"):
+		path, err := p.segments(true)
+		if err != nil {
+			return nil, err
+		}
+		return filterQuery{path: path}, nil
+	case p.consume("true"):
+		return literal{true}, nil
+	case p.consume("false"):
+		return literal{false}, nil
+	case p.consume("null"):
+		return literal{nil}, nil
+	}
+	if p.i < len(p.s) && (p.s[p.i] == '-' || '0' <= p.s[p.i] && p.s[p.i] <= '9') {
+		return p.number()
+	}
+	return p.function()
+}
+
+func (p *jsonPathParser) number() (any, error) {
+	start := p.i
+	p.consume("-")
+	for p.i < len(p.s) && strings.IndexByte("0123456789.eE+-", p.s[p.i]) >= 0 {
+		p.i++
+	}
+	f, err := strconv.ParseFloat(p.s[start:p.i], 64)
+	if err != nil || math.IsInf(f, 0) {
+		return nil, p.errorf("invalid number %q", p.s[start:p.i])
+	}
+	return literal{f}, nil
+}
+
+func (p *jsonPathParser) function() (any, error) {
+	start := p.i
+	for p.i < len(p.s) && ('a' <= p.s[p.i] && p.s[p.i] <= 'z' || p.s[p.i] == '_') {
+		p.i++
+	}
+	f := functionExpr{name: p.s[start:p.i]}
+	var arity int
+	switch f.name {
+	case "length", "count", "value":
+		arity = 1
+	case "match", "search":
+		arity = 2
+	case "":
+		return nil, p.errorf("expected a literal, a query or a function")
+	default:
+		p.i = start
+		return nil, p.errorf("unknown function %q", f.name)
+	}
+	if !p.consume("(") {
+		return nil, p.errorf("expected %q", "(")
+	}
+	for i := range arity {
+		p.skipSpaces()
+		if i > 0 && !p.consume(",") {
+			return nil, p.errorf("function %q takes %d arguments", f.name, arity)
+		}
+		p.skipSpaces()
+		arg, err := p.operand()
+		if err != nil {
+			return nil, err
+		}
+		f.args = append(f.args, arg)
+	}
+	p.skipSpaces()
+	if !p.consume(")") {
+		return nil, p.errorf("function %q takes %d arguments", f.name, arity)
+	}
+
+	switch f.name {
+	case "count", "value":
+		if _, ok := f.args[0].(filterQuery); !ok {
+			return nil, p.errorf("function %q takes a query", f.name)
+		}
+	case "length", "match", "search":
+		for _, arg := range f.args {
+			if _, ok := p.comparable(arg); !ok {
+				return nil, p.errorf("function %q takes values", f.name)
+			}
+		}
+	}
+	if f.name == "match" || f.name == "search" {
+		// A regular expression given as a literal is compiled once; others,
+		// which depend on the document, never match.
+		if pattern, ok := f.args[1].(literal); ok {
+			if s, ok := pattern.v.(string); ok {
+				if f.name == "match" {
+					s = `^(?:` + s + `)$`
+				}
+				re, err := regexp.Compile(s)
+				if err != nil {
+					return nil, p.errorf("invalid regular expression %q: %v", s, err)
+				}
+				f.re = re
+			}
+		}
+	}
+	return f, nil
+}
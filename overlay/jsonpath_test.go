@@ -0,0 +1,84 @@
+package overlay
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// The example of RFC 9535, section 1.5.
+const bookstore = `{
+  "store": {
+    "book": [
+      {"category": "reference", "author": "Nigel Rees", "title": "Sayings of the Century", "price": 8.95},
+      {"category": "fiction", "author": "Evelyn Waugh", "title": "Sword of Honour", "price": 12.99},
+      {"category": "fiction", "author": "Herman Melville", "title": "Moby Dick", "isbn": "0-553-21311-3", "price": 8.99},
+      {"category": "fiction", "author": "J. R. R. Tolkien", "title": "The Lord of the Rings", "isbn": "0-395-19395-8", "price": 22.99}
+    ],
+    "bicycle": {"color": "red", "price": 399}
+  }
+}`
+
+func TestJSONPath(t *testing.T) {
+	var doc any
+	require.NoError(t, json.Unmarshal([]byte(bookstore), &doc))
+
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{`$.store.book[*].author`, []string{`$['store']['book'][0]['author']`, `$['store']['book'][1]['author']`, `$['store']['book'][2]['author']`, `$['store']['book'][3]['author']`}},
+		{`$..author`, []string{`$['store']['book'][0]['author']`, `$['store']['book'][1]['author']`, `$['store']['book'][2]['author']`, `$['store']['book'][3]['author']`}},
+		{`$.store.*`, []string{`$['store']['bicycle']`, `$['store']['book']`}},
+		{`$.store..price`, []string{`$['store']['bicycle']['price']`, `$['store']['book'][0]['price']`, `$['store']['book'][1]['price']`, `$['store']['book'][2]['price']`, `$['store']['book'][3]['price']`}},
+		{`$..book[2]`, []string{`$['store']['book'][2]`}},
+		{`$..book[-1]`, []string{`$['store']['book'][3]`}},
+		{`$..book[0,1]`, []string{`$['store']['book'][0]`, `$['store']['book'][1]`}},
+		{`$..book[:2]`, []string{`$['store']['book'][0]`, `$['store']['book'][1]`}},
+		{`$..book[::-2]`, []string{`$['store']['book'][3]`, `$['store']['book'][1]`}},
+		{`$..book[?@.isbn]`, []string{`$['store']['book'][2]`, `$['store']['book'][3]`}},
+		{`$..book[?@.price<10]`, []string{`$['store']['book'][0]`, `$['store']['book'][2]`}},
+		{`$..book[?(@.price < 10 && @.category == 'fiction')]`, []string{`$['store']['book'][2]`}},
+		{`$..book[?!@.isbn || @.price > 20]`, []string{`$['store']['book'][0]`, `$['store']['book'][1]`, `$['store']['book'][3]`}},
+		{`$..book[?@.price == $.store.book[0].price]`, []string{`$['store']['book'][0]`}},
+		{`$..book[?match(@.author, 'J.*')]`, []string{`$['store']['book'][3]`}},
+		{`$..book[?search(@.title, 'of')]`, []string{`$['store']['book'][0]`, `$['store']['book'][1]`, `$['store']['book'][3]`}},
+		{`$..book[?length(@.title) > 20]`, []string{`$['store']['book'][0]`, `$['store']['book'][3]`}},
+		{`$.store[?count(@.*) == 2]`, []string{`$['store']['bicycle']`}},
+		{`$["store"]['bicycle']["color"]`, []string{`$['store']['bicycle']['color']`}},
+		{`$.store.x-missing`, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			path, err := parseJSONPath(test.query)
+			require.NoError(t, err)
+			var actual []string
+			for _, n := range path.query(doc) {
+				actual = append(actual, formatPath(n.path))
+			}
+			require.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestJSONPathErrors(t *testing.T) {
+	for _, query := range []string{
+		`store`,
+		`$.`,
+		`$[`,
+		`$[1`,
+		`$['a`,
+		`$[?@.a ==]`,
+		`$[?@..a == 1]`,
+		`$[?unknown(@)]`,
+		`$[?length(@)]`,
+		`$[?match(@, '(')]`,
+		`$.a b`,
+	} {
+		t.Run(query, func(t *testing.T) {
+			_, err := parseJSONPath(query)
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,408 @@
+package overlay
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/oasdiff/yaml"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+var versionRegExp = regexp.MustCompile(`^1\.0\.\d+$`)
+
+// Overlay is an Overlay document.
+// See https://spec.openapis.org/overlay/v1.0.0.html#overlay-object
+type Overlay struct {
+	Extensions map[string]any `json:"-" yaml:"-"`
+
+	Overlay string    `json:"overlay" yaml:"overlay"` // Required
+	Info    *Info     `json:"info" yaml:"info"`       // Required
+	Extends string    `json:"extends,omitempty" yaml:"extends,omitempty"`
+	Actions []*Action `json:"actions" yaml:"actions"` // Required
+}
+
+// Info is the metadata of an Overlay document.
+// See https://spec.openapis.org/overlay/v1.0.0.html#info-object
+type Info struct {
+	Extensions map[string]any `json:"-" yaml:"-"`
+
+	Title   string `json:"title" yaml:"title"`     // Required
+	Version string `json:"version" yaml:"version"` // Required
+}
+
+// Action is a change to the nodes selected by Target. It removes them when
+// Remove is set, and merges Update into them otherwise.
+// See https://spec.openapis.org/overlay/v1.0.0.html#action-object
+type Action struct {
+	Extensions map[string]any `json:"-" yaml:"-"`
+
+	Target      string `json:"target" yaml:"target"` // Required
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Update      any    `json:"update,omitempty" yaml:"update,omitempty"`
+	Remove      bool   `json:"remove,omitempty" yaml:"remove,omitempty"`
+}
+
+// Parse decodes an Overlay document from YAML or JSON data.
+func Parse(data []byte) (*Overlay, error) {
+	o := &Overlay{}
+	if _, err := yaml.Unmarshal(data, o, yaml.DecodeOpts{DisableTimestamps: true}); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// ParseFile decodes the Overlay document of a local YAML or JSON file.
+func ParseFile(filename string) (*Overlay, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// MarshalJSON returns the JSON encoding of Overlay.
+func (o Overlay) MarshalJSON() ([]byte, error) {
+	type OverlayBis Overlay
+	return marshalWithExtensions(OverlayBis(o), o.Extensions)
+}
+
+// UnmarshalJSON sets Overlay to a copy of data.
+func (o *Overlay) UnmarshalJSON(data []byte) error {
+	type OverlayBis Overlay
+	var x OverlayBis
+	if err := json.Unmarshal(data, &x); err != nil {
+		return err
+	}
+	x.Extensions = unmarshalExtensions(data, "overlay", "info", "extends", "actions")
+	*o = Overlay(x)
+	return nil
+}
+
+// MarshalJSON returns the JSON encoding of Info.
+func (info Info) MarshalJSON() ([]byte, error) {
+	type InfoBis Info
+	return marshalWithExtensions(InfoBis(info), info.Extensions)
+}
+
+// UnmarshalJSON sets Info to a copy of data.
+func (info *Info) UnmarshalJSON(data []byte) error {
+	type InfoBis Info
+	var x InfoBis
+	if err := json.Unmarshal(data, &x); err != nil {
+		return err
+	}
+	x.Extensions = unmarshalExtensions(data, "title", "version")
+	*info = Info(x)
+	return nil
+}
+
+// MarshalJSON returns the JSON encoding of Action.
+func (action Action) MarshalJSON() ([]byte, error) {
+	type ActionBis Action
+	return marshalWithExtensions(ActionBis(action), action.Extensions)
+}
+
+// UnmarshalJSON sets Action to a copy of data.
+func (action *Action) UnmarshalJSON(data []byte) error {
+	type ActionBis Action
+	var x ActionBis
+	if err := json.Unmarshal(data, &x); err != nil {
+		return err
+	}
+	x.Extensions = unmarshalExtensions(data, "target", "description", "update", "remove")
+	*action = Action(x)
+	return nil
+}
+
+func marshalWithExtensions(v any, extensions map[string]any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(extensions) == 0 {
+		return data, err
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	maps.Copy(m, extensions)
+	return json.Marshal(m)
+}
+
+// unmarshalExtensions returns the fields of data other than known ones.
+func unmarshalExtensions(data []byte, known ...string) map[string]any {
+	var extensions map[string]any
+	_ = json.Unmarshal(data, &extensions)
+	for _, k := range known {
+		delete(extensions, k)
+	}
+	if len(extensions) == 0 {
+		return nil
+	}
+	return extensions
+}
+
+// Validate returns an error if the Overlay document does not comply with the
+// specification, for instance if a target is not a valid JSONPath query.
+func (o *Overlay) Validate() error {
+	if !versionRegExp.MatchString(o.Overlay) {
+		return fmt.Errorf("unsupported overlay version %q", o.Overlay)
+	}
+	if err := validateExtensions(o.Extensions); err != nil {
+		return err
+	}
+
+	if o.Info == nil {
+		return errors.New("value of info must be an object")
+	}
+	if o.Info.Title == "" {
+		return errors.New("value of info.title must be a non-empty string")
+	}
+	if o.Info.Version == "" {
+		return errors.New("value of info.version must be a non-empty string")
+	}
+	if err := validateExtensions(o.Info.Extensions); err != nil {
+		return fmt.Errorf("invalid info: %w", err)
+	}
+
+	if len(o.Actions) == 0 {
+		return errors.New("value of actions must be a non-empty array")
+	}
+	for i, action := range o.Actions {
+		if err := action.Validate(); err != nil {
+			return fmt.Errorf("invalid action %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Validate returns an error if Action does not comply with the specification.
+func (action *Action) Validate() error {
+	if action == nil {
+		return errors.New("value must be an object")
+	}
+	if action.Target == "" {
+		return errors.New("value of target must be a non-empty string")
+	}
+	if _, err := parseJSONPath(action.Target); err != nil {
+		return err
+	}
+	if action.Update == nil && !action.Remove {
+		return errors.New("either update or remove must be set")
+	}
+	return validateExtensions(action.Extensions)
+}
+
+func validateExtensions(extensions map[string]any) error {
+	var unknowns []string
+	for k := range extensions {
+		if !strings.HasPrefix(k, "x-") {
+			unknowns = append(unknowns, k)
+		}
+	}
+	if len(unknowns) != 0 {
+		slices.Sort(unknowns)
+		return fmt.Errorf("extra sibling fields: %+v", unknowns)
+	}
+	return nil
+}
+
+// Apply applies the Overlay to a copy of doc, which must be self-contained as
+// Bundle leaves it, and returns the copy once loaded and validated.
+func (o *Overlay) Apply(ctx context.Context, doc *openapi3.T) (*openapi3.T, error) {
+	data, err := doc.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	loader := openapi3.NewLoader()
+	loader.Context = ctx
+	loader.Overlays = []openapi3.DocumentOverlay{o}
+	result, err := loader.LoadFromData(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := result.Validate(ctx); err != nil {
+		return nil, fmt.Errorf("invalid overlaid document: %w", err)
+	}
+	return result, nil
+}
+
+// ApplyTo applies the actions of the Overlay, in order, to doc, a document
+// decoded into generic values, and returns the result. It implements
+// openapi3.DocumentOverlay.
+//
+// An update merges into each selected object: its members replace those of
+// the object, except that objects merge recursively and arrays are
+// concatenated. An update of a selected array is appended to it. To replace an
+// array, such as the servers of the document, remove it in an earlier action.
+// A target that selects no node is not an error.
+func (o *Overlay) ApplyTo(doc any) (any, error) {
+	for i, action := range o.Actions {
+		path, err := parseJSONPath(action.Target)
+		if err != nil {
+			return nil, fmt.Errorf("invalid action %d: %w", i, err)
+		}
+		nodes := path.query(doc)
+
+		if action.Remove {
+			// Remove the last nodes first, so that the paths of the others,
+			// and notably their array indices, remain valid.
+			slices.SortFunc(nodes, func(a, b jsonNode) int { return comparePaths(b.path, a.path) })
+			for _, n := range nodes {
+				if len(n.path) == 0 {
+					return nil, fmt.Errorf("action %d: cannot remove the whole document", i)
+				}
+				doc = removeAt(doc, n.path)
+			}
+			continue
+		}
+
+		for _, n := range nodes {
+			switch value := n.value.(type) {
+			case map[string]any:
+				update, ok := action.Update.(map[string]any)
+				if !ok {
+					return nil, fmt.Errorf("action %d: update of object %s must be an object", i, formatPath(n.path))
+				}
+				merge(value, update)
+			case []any:
+				doc = setAt(doc, n.path, append(value, copyValue(action.Update)))
+			default:
+				return nil, fmt.Errorf("action %d: cannot update %s, which is neither an object nor an array", i, formatPath(n.path))
+			}
+		}
+	}
+	return doc, nil
+}
+
+// merge merges update into target.
+func merge(target, update map[string]any) {
+	for k, v := range update {
+		switch v := v.(type) {
+		case map[string]any:
+			if t, ok := target[k].(map[string]any); ok {
+				merge(t, v)
+				continue
+			}
+		case []any:
+			if t, ok := target[k].([]any); ok {
+				target[k] = append(t, copyValue(v).([]any)...)
+				continue
+			}
+		}
+		target[k] = copyValue(v)
+	}
+}
+
+// copyValue returns a deep copy of v, so that an update merged into several
+// nodes does not share them.
+func copyValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		c := make(map[string]any, len(v))
+		for k, e := range v {
+			c[k] = copyValue(e)
+		}
+		return c
+	case []any:
+		c := make([]any, len(v))
+		for i, e := range v {
+			c[i] = copyValue(e)
+		}
+		return c
+	}
+	return v
+}
+
+// setAt sets the value at path and returns the updated root.
+func setAt(root any, path []any, value any) any {
+	if len(path) == 0 {
+		return value
+	}
+	switch parent := lookup(root, path[:len(path)-1]).(type) {
+	case map[string]any:
+		parent[path[len(path)-1].(string)] = value
+	case []any:
+		parent[path[len(path)-1].(int)] = value
+	}
+	return root
+}
+
+// removeAt removes the value at path, if any, and returns the updated root.
+func removeAt(root any, path []any) any {
+	key := path[len(path)-1]
+	switch parent := lookup(root, path[:len(path)-1]).(type) {
+	case map[string]any:
+		if k, ok := key.(string); ok {
+			delete(parent, k)
+		}
+	case []any:
+		if i, ok := key.(int); ok && i < len(parent) {
+			root = setAt(root, path[:len(path)-1], slices.Delete(parent, i, i+1))
+		}
+	}
+	return root
+}
+
+// lookup returns the value at path, or nil if there is none.
+func lookup(v any, path []any) any {
+	for _, key := range path {
+		switch key := key.(type) {
+		case string:
+			m, ok := v.(map[string]any)
+			if !ok {
+				return nil
+			}
+			v = m[key]
+		case int:
+			a, ok := v.([]any)
+			if !ok || key >= len(a) {
+				return nil
+			}
+			v = a[key]
+		}
+	}
+	return v
+}
+
+// comparePaths orders normalized paths in document order.
+func comparePaths(a, b []any) int {
+	for i := range min(len(a), len(b)) {
+		var c int
+		switch x := a[i].(type) {
+		case string:
+			y, _ := b[i].(string)
+			c = cmp.Compare(x, y)
+		case int:
+			y, _ := b[i].(int)
+			c = cmp.Compare(x, y)
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(a), len(b))
+}
+
+// formatPath returns the normalized JSONPath of a node.
+func formatPath(path []any) string {
+	var b strings.Builder
+	b.WriteString("$")
+	for _, key := range path {
+		switch key := key.(type) {
+		case string:
+			b.WriteString("['" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(key) + "']")
+		case int:
+			fmt.Fprintf(&b, "[%d]", key)
+		}
+	}
+	return b.String()
+}
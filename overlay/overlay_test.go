@@ -0,0 +1,270 @@
+package overlay_test
+
+import (
+	"net/url"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/overlay"
+)
+
+func TestApply(t *testing.T) {
+	ctx := t.Context()
+
+	o, err := overlay.ParseFile("testdata/production.overlay.yaml")
+	require.NoError(t, err)
+	require.NoError(t, o.Validate())
+
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromFile("testdata/openapi.yaml")
+	require.NoError(t, err)
+
+	overlaid, err := o.Apply(ctx, doc)
+	require.NoError(t, err)
+
+	require.Equal(t, openapi3.Servers{{URL: "https://api.example.com"}}, overlaid.Servers)
+	require.Nil(t, overlaid.Paths.Value("/admin"))
+	pets := overlaid.Paths.Value("/pets")
+	require.Nil(t, pets.Post)
+	require.Equal(t, []string{"pets", "public"}, pets.Get.Tags)
+	require.Equal(t, map[string]any{"x-rate-limit": float64(100)}, pets.Get.Extensions)
+
+	// The original document is left as is
+	require.Equal(t, "http://localhost:8080", doc.Servers[0].URL)
+	require.NotNil(t, doc.Paths.Value("/pets").Post)
+}
+
+func TestLoaderOverlays(t *testing.T) {
+	o, err := overlay.ParseFile("testdata/production.overlay.yaml")
+	require.NoError(t, err)
+
+	loader := openapi3.NewLoader()
+	loader.IncludeOrigin = true
+	loader.Overlays = []openapi3.DocumentOverlay{o}
+	doc, err := loader.LoadFromFile("testdata/openapi.yaml")
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(loader.Context))
+
+	require.Len(t, doc.Servers, 1)
+	require.Nil(t, doc.Paths.Value("/admin"))
+
+	// Nodes the overlay keeps have the origin of the original document
+	get := doc.Paths.Value("/pets").Get
+	require.NotNil(t, get.Origin)
+	require.Equal(t, "testdata/openapi.yaml", get.Origin.Key.File)
+	require.Equal(t, 9, get.Origin.Key.Line)
+
+	loader = openapi3.NewLoader()
+	loader.IncludeOrigin = true
+	original, err := loader.LoadFromFile("testdata/openapi.yaml")
+	require.NoError(t, err)
+	require.Equal(t, original.Paths.Value("/pets").Get.Responses.Value("200").Value.Origin, get.Responses.Value("200").Value.Origin)
+	require.Equal(t, original.Info.Origin, doc.Info.Origin)
+
+	// Nodes it adds have none
+	require.Nil(t, doc.Servers[0].Origin)
+}
+
+func TestLoaderOverlaysPrefetch(t *testing.T) {
+	fsys := fstest.MapFS{
+		"openapi.yaml": {Data: []byte(`
+openapi: 3.0.3
+info: {title: Pets, version: 1.0.0}
+paths:
+  /public: {$ref: 'public.yaml'}
+  /internal: {$ref: 'internal.yaml'}
+`)},
+		"public.yaml":   {Data: []byte(`get: {responses: {'200': {description: OK}}}`)},
+		"internal.yaml": {Data: []byte(`get: {responses: {'200': {description: OK}}}`)},
+	}
+	o, err := overlay.Parse([]byte(`
+overlay: 1.0.0
+info: {title: Public, version: 1.0.0}
+actions:
+  - target: $.paths['/internal']
+    remove: true
+`))
+	require.NoError(t, err)
+
+	// Documents removed by overlays are not read, and prefetched ones count within the policy as resolution uses them
+	for range 10 {
+		var mu sync.Mutex
+		var read []string
+		readFromFS := openapi3.ReadFromFS(fsys)
+		loader := openapi3.NewLoader()
+		loader.ReadFromURIFunc = func(loader *openapi3.Loader, location *url.URL) ([]byte, error) {
+			mu.Lock()
+			read = append(read, location.String())
+			mu.Unlock()
+			return readFromFS(loader, location)
+		}
+		loader.Overlays = []openapi3.DocumentOverlay{o}
+		loader.URIPolicy = &openapi3.URIPolicy{MaxDocuments: 2}
+		loader.PrefetchConcurrency = 4
+		doc, err := loader.LoadFromFile("openapi.yaml")
+		require.NoError(t, err)
+		require.NotNil(t, doc.Paths.Value("/public").Get)
+		require.Nil(t, doc.Paths.Value("/internal"))
+		require.ElementsMatch(t, []string{"openapi.yaml", "public.yaml"}, read)
+	}
+}
+
+func TestApplyTo(t *testing.T) {
+	tests := []struct {
+		name     string
+		overlay  string
+		doc      any
+		expected any
+		err      string
+	}{
+		{
+			name: "update merges objects and concatenates arrays",
+			overlay: `
+overlay: 1.0.0
+info: {title: t, version: v}
+actions:
+  - target: $.a
+    update: {b: {c: 2}, d: [2], e: x}
+`,
+			doc:      map[string]any{"a": map[string]any{"b": map[string]any{"c": 1, "f": 1}, "d": []any{1}, "e": 1}},
+			expected: map[string]any{"a": map[string]any{"b": map[string]any{"c": float64(2), "f": 1}, "d": []any{1, float64(2)}, "e": "x"}},
+		},
+		{
+			name: "update appends to arrays",
+			overlay: `
+overlay: 1.0.0
+info: {title: t, version: v}
+actions:
+  - target: $.a
+    update: {b: 1}
+`,
+			doc:      map[string]any{"a": []any{"x"}},
+			expected: map[string]any{"a": []any{"x", map[string]any{"b": float64(1)}}},
+		},
+		{
+			name: "remove array elements",
+			overlay: `
+overlay: 1.0.0
+info: {title: t, version: v}
+actions:
+  - target: $.a[?@ > 1]
+    remove: true
+`,
+			doc:      map[string]any{"a": []any{1, 2, 3, 0}},
+			expected: map[string]any{"a": []any{1, 0}},
+		},
+		{
+			name: "remove nested nodes",
+			overlay: `
+overlay: 1.0.0
+info: {title: t, version: v}
+actions:
+  - target: $..x-internal
+    remove: true
+`,
+			doc:      map[string]any{"a": map[string]any{"x-internal": true, "b": []any{map[string]any{"x-internal": false}}}},
+			expected: map[string]any{"a": map[string]any{"b": []any{map[string]any{}}}},
+		},
+		{
+			name: "target selecting nothing",
+			overlay: `
+overlay: 1.0.0
+info: {title: t, version: v}
+actions:
+  - target: $.nothing
+    remove: true
+`,
+			doc:      map[string]any{"a": 1},
+			expected: map[string]any{"a": 1},
+		},
+		{
+			name: "update of a scalar",
+			overlay: `
+overlay: 1.0.0
+info: {title: t, version: v}
+actions:
+  - target: $.a
+    update: {b: 1}
+`,
+			doc: map[string]any{"a": 1},
+			err: `action 0: cannot update $['a'], which is neither an object nor an array`,
+		},
+		{
+			name: "remove the document",
+			overlay: `
+overlay: 1.0.0
+info: {title: t, version: v}
+actions:
+  - target: $
+    remove: true
+`,
+			doc: map[string]any{"a": 1},
+			err: `action 0: cannot remove the whole document`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			o, err := overlay.Parse([]byte(test.overlay))
+			require.NoError(t, err)
+			require.NoError(t, o.Validate())
+
+			actual, err := o.ApplyTo(test.doc)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		overlay string
+		err     string
+	}{
+		{
+			overlay: `{overlay: 2.0.0, info: {title: t, version: v}, actions: [{target: $, remove: true}]}`,
+			err:     `unsupported overlay version "2.0.0"`,
+		},
+		{
+			overlay: `{overlay: 1.0.0, actions: [{target: $, remove: true}]}`,
+			err:     `value of info must be an object`,
+		},
+		{
+			overlay: `{overlay: 1.0.0, info: {title: t, version: v}, actions: []}`,
+			err:     `value of actions must be a non-empty array`,
+		},
+		{
+			overlay: `{overlay: 1.0.0, info: {title: t, version: v}, actions: [{target: $.a}]}`,
+			err:     `invalid action 0: either update or remove must be set`,
+		},
+		{
+			overlay: `{overlay: 1.0.0, info: {title: t, version: v}, actions: [{target: "$.a[", remove: true}]}`,
+			err:     `invalid action 0: invalid JSONPath "$.a[" at offset 4: expected a selector`,
+		},
+		{
+			overlay: `{overlay: 1.0.0, info: {title: t, version: v}, actions: [{target: $, remove: true, delete: true}]}`,
+			err:     `invalid action 0: extra sibling fields: [delete]`,
+		},
+		{
+			overlay: `{overlay: 1.0.1, info: {title: t, version: v, x-a: 1}, actions: [{target: $, remove: true, x-b: 2}], x-c: 3}`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.overlay, func(t *testing.T) {
+			o, err := overlay.Parse([]byte(test.overlay))
+			require.NoError(t, err)
+			if test.err == "" {
+				require.NoError(t, o.Validate())
+				return
+			}
+			require.EqualError(t, o.Validate(), test.err)
+		})
+	}
+}
@@ -99,6 +99,44 @@ func NewRouter(doc *openapi3.T) (routers.Router, error) {
 	return r, nil
 }
 
+// NewLocalRouter creates a gorilla/mux router of doc whose servers are served
+// from their paths, whatever the scheme and host of requests, such as for mock
+// servers and in-process tests.
+func NewLocalRouter(doc *openapi3.T) (routers.Router, error) {
+	local := *doc
+	local.Servers = localServers(doc.Servers)
+	return NewRouter(&local)
+}
+
+// localServers returns servers with their URLs reduced to their paths.
+func localServers(servers openapi3.Servers) openapi3.Servers {
+	var local openapi3.Servers
+	for _, server := range servers {
+		if server == nil {
+			continue
+		}
+		path := server.URL
+		if i := strings.Index(path, "://"); i >= 0 {
+			path = path[i+len("://"):]
+			if i := strings.IndexByte(path, '/'); i >= 0 {
+				path = path[i:]
+			} else {
+				path = "/"
+			}
+		}
+		if !strings.HasPrefix(path, "/") {
+			path = "/" + path
+		}
+		if slices.ContainsFunc(local, func(s *openapi3.Server) bool { return s.URL == path }) {
+			continue
+		}
+		s := *server
+		s.URL = path
+		local = append(local, &s)
+	}
+	return local
+}
+
 // FindRoute extracts the route and parameters of an http.Request
 func (r *Router) FindRoute(req *http.Request) (*routers.Route, map[string]string, error) {
 	for i, m := range r.muxes {
@@ -338,6 +338,34 @@ func TestRelativeURL(t *testing.T) {
 	require.Equal(t, "/hello", route.Path)
 }
 
+func TestLocalRouter(t *testing.T) {
+	helloGET := &openapi3.Operation{Responses: openapi3.NewResponses(openapi3.WithStatus(200, &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription("OK")}))}
+	doc := &openapi3.T{
+		OpenAPI: "3.0.0",
+		Info:    &openapi3.Info{Title: "local", Version: "1"},
+		Servers: openapi3.Servers{
+			{URL: "https://api.example.com/v1"},
+			{URL: "http://staging.example.com/v1"},
+			{URL: "https://example.com"},
+		},
+		Paths: openapi3.NewPaths(openapi3.WithPath("/hello", &openapi3.PathItem{Get: helloGET})),
+	}
+	require.NoError(t, doc.Validate(context.Background()))
+	router, err := NewLocalRouter(doc)
+	require.NoError(t, err)
+	// The servers of doc are left as they are
+	require.Equal(t, "https://api.example.com/v1", doc.Servers[0].URL)
+
+	for _, target := range []string{"http://localhost:8080/v1/hello", "/v1/hello", "/hello"} {
+		req, err := http.NewRequest(http.MethodGet, target, nil)
+		require.NoError(t, err)
+		route, _, err := router.FindRoute(req)
+		require.NoError(t, err, target)
+		require.Equal(t, "/hello", route.Path)
+	}
+	require.Equal(t, openapi3.Servers{{URL: "/v1"}, {URL: "/"}}, localServers(doc.Servers))
+}
+
 func Test_makeServers(t *testing.T) {
 	type testStruct struct {
 		name    string